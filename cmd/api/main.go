@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"gin-quickstart/internal/config"
 	"gin-quickstart/internal/handlers"
@@ -31,19 +37,81 @@ func main() {
 		&models.Product{},
 		&models.Category{},
 		&models.Order{},
+		&models.OrderItem{},
+		&models.OrderItemProduct{},
 		&models.CustomOrder{},
 		&models.Transaction{},
 		&models.Download{},
 		&models.Review{},
 		&models.Cart{},
+		&models.GuestCart{},
 		&models.Wishlist{},
 		&models.APILog{},
 		&models.Analytics{},
 		&models.Notification{},
+		&models.FeaturedProduct{},
+		&models.Bundle{},
+		&models.LicenseTier{},
+		&models.ProductStatusLog{},
+		&models.ProductRevision{},
+		&models.ProductRelation{},
+		&models.SlugRedirect{},
+		&models.ProductFile{},
+		&models.ProductQuestion{},
+		&models.ProductAnswer{},
+		&models.ProductQuestionVote{},
+		&models.SaleCampaign{},
+		&models.ProductPriceHistory{},
+		&models.Coupon{},
+		&models.CouponRedemption{},
+		&models.TaxRule{},
+		&models.OrderTax{},
+		&models.ExchangeRate{},
+		&models.LicenseKey{},
+		&models.LicenseActivation{},
+		&models.ProductTranslation{},
+		&models.CategoryTranslation{},
 	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// Map the legacy is_active flag onto the publishing workflow
+	if err := repositories.SetupProductWorkflow(db); err != nil {
+		log.Fatal("Failed to migrate product workflow:", err)
+	}
+
+	// Full-text search column, index and sync trigger for products
+	if err := repositories.SetupProductSearch(db); err != nil {
+		log.Fatal("Failed to setup product search:", err)
+	}
+
+	// Published product counts per category, kept by a trigger
+	if err := repositories.SetupCategoryProductCounts(db); err != nil {
+		log.Fatal("Failed to setup category product counts:", err)
+	}
+
+	if err := repositories.SetupBundleSearch(db); err != nil {
+		log.Fatal("Failed to setup bundle search:", err)
+	}
+
+	// Give orders from before multi-item checkout their line item
+	if err := repositories.SetupOrderItems(db); err != nil {
+		log.Fatal("Failed to migrate order items:", err)
+	}
+
+	// Link license keys to the order line they were issued for
+	if err := repositories.SetupLicenseKeys(db); err != nil {
+		log.Fatal("Failed to migrate license keys:", err)
+	}
+
+	if err := repositories.SetupAnalyticsIndexes(db); err != nil {
+		log.Fatal("Failed to setup analytics indexes:", err)
+	}
+
+	if err := repositories.SetupUserEmailIndex(db); err != nil {
+		log.Fatal("Failed to migrate user email index:", err)
+	}
+
 	log.Println("Database migration completed successfully")
 
 	// Set Gin mode based on environment
@@ -64,40 +132,92 @@ func main() {
 	reviewRepo := repositories.NewReviewRepository(db)
 	customOrderRepo := repositories.NewCustomOrderRepository(db)
 	notificationRepo := repositories.NewNotificationRepository(db)
+	featuredRepo := repositories.NewFeaturedProductRepository(db)
+	analyticsRepo := repositories.NewAnalyticsRepository(db)
+	bundleRepo := repositories.NewBundleRepository(db)
+	licenseTierRepo := repositories.NewLicenseTierRepository(db)
+	recommendationRepo := repositories.NewRecommendationRepository(db)
+	slugRepo := repositories.NewSlugRedirectRepository(db)
+	productFileRepo := repositories.NewProductFileRepository(db)
+	questionRepo := repositories.NewProductQuestionRepository(db)
+	campaignRepo := repositories.NewSaleCampaignRepository(db)
+	couponRepo := repositories.NewCouponRepository(db)
+	taxRepo := repositories.NewTaxRepository(db)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(db)
+	licenseKeyRepo := repositories.NewLicenseKeyRepository(db)
+	trashRepo := repositories.NewTrashRepository(db)
+	translationRepo := repositories.NewTranslationRepository(db)
+
+	// License keys are signed so shipped software can verify them offline
+	licenseSigningKey, err := utils.LicenseSigningKey(cfg.LicenseSigningKey)
+	if err != nil {
+		log.Fatal("Failed to load license signing key:", err)
+	}
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo, cfg)
 	userService := services.NewUserService(userRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
-	productService := services.NewProductService(productRepo, categoryRepo)
-	cartService := services.NewCartService(cartRepo, productRepo)
-	wishlistService := services.NewWishlistService(wishlistRepo, productRepo)
-	orderService := services.NewOrderService(orderRepo, transactionRepo, productRepo, cartRepo)
+	categoryService := services.NewCategoryService(categoryRepo, slugRepo)
+	currencyService := services.NewCurrencyService(exchangeRateRepo, userRepo)
+	campaignService := services.NewSaleCampaignService(campaignRepo, productRepo, categoryRepo, cfg.PriceHistoryInterval)
+	productService := services.NewProductService(productRepo, categoryRepo, slugRepo, campaignService)
+	couponService := services.NewCouponService(couponRepo, orderRepo, productRepo, categoryRepo, currencyService)
+	taxService := services.NewTaxService(taxRepo, userRepo, cfg.TaxDefaultCountry)
+	cartService := services.NewCartService(cartRepo, productRepo, licenseTierRepo, campaignService, couponService, taxService, currencyService, cfg.JWTSecret, cfg.GuestCartTTL, cfg.GuestCartCleanupInterval)
+	wishlistService := services.NewWishlistService(wishlistRepo, productRepo, campaignService)
+	licenseKeyService := services.NewLicenseKeyService(licenseKeyRepo, orderRepo, licenseSigningKey)
+	orderService := services.NewOrderService(orderRepo, transactionRepo, productRepo, cartRepo, bundleRepo, licenseTierRepo, campaignService, couponService, taxService, currencyService, licenseKeyService, cfg.PendingOrderTTL, cfg.PendingOrderExpiryInterval)
 	downloadService := services.NewDownloadService(downloadRepo, orderRepo, productRepo)
 	reviewService := services.NewReviewService(reviewRepo, productRepo, orderRepo)
 	customOrderService := services.NewCustomOrderService(customOrderRepo)
 	notificationService := services.NewNotificationService(notificationRepo)
 	analyticsService := services.NewAnalyticsService(userRepo, productRepo, orderRepo, reviewRepo)
+	featuredService := services.NewFeaturedProductService(featuredRepo, productRepo, campaignService, cfg.FeaturedMaxPerCategory)
+	productViewService := services.NewProductViewService(analyticsRepo, cfg.ViewDedupeWindow, cfg.ViewFlushInterval)
+	bundleService := services.NewBundleService(bundleRepo, productRepo, slugRepo)
+	licenseTierService := services.NewLicenseTierService(licenseTierRepo, productRepo)
+	workflowService := services.NewProductWorkflowService(productRepo, categoryRepo, slugRepo, campaignService, cfg.PublishCheckInterval)
+	importService := services.NewProductImportService(productRepo, categoryRepo, slugRepo)
+	recommendationService := services.NewRecommendationService(recommendationRepo, productRepo, campaignService, cfg.RecommendationRefreshInterval)
+	productFileService := services.NewProductFileService(productFileRepo, productRepo)
+	questionService := services.NewProductQuestionService(questionRepo, productRepo, notificationService)
+	trashService := services.NewTrashService(trashRepo, reviewRepo, userRepo, slugRepo, cfg.TrashRetention, cfg.TrashPurgeInterval)
+	translationService := services.NewTranslationService(translationRepo, productRepo, categoryRepo, cfg.DefaultLocale, cfg.SupportedLocales)
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, cfg)
+	authHandler := handlers.NewAuthHandler(authService, cartService, cfg)
 	userHandler := handlers.NewUserHandler(userService)
-	categoryHandler := handlers.NewCategoryHandler(categoryService)
-	productHandler := handlers.NewProductHandler(productService)
-	cartHandler := handlers.NewCartHandler(cartService)
-	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
-	orderHandler := handlers.NewOrderHandler(orderService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, translationService)
+	productHandler := handlers.NewProductHandler(productService, productViewService, bundleService, currencyService, translationService)
+	cartHandler := handlers.NewCartHandler(cartService, currencyService, cfg)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService, currencyService, translationService)
+	orderHandler := handlers.NewOrderHandler(orderService, currencyService)
 	downloadHandler := handlers.NewDownloadHandler(downloadService)
 	reviewHandler := handlers.NewReviewHandler(reviewService)
 	customOrderHandler := handlers.NewCustomOrderHandler(customOrderService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	featuredHandler := handlers.NewFeaturedProductHandler(featuredService, currencyService, translationService)
+	bundleHandler := handlers.NewBundleHandler(bundleService)
+	licenseTierHandler := handlers.NewLicenseTierHandler(licenseTierService)
+	workflowHandler := handlers.NewProductWorkflowHandler(workflowService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService, currencyService, translationService)
+	importHandler := handlers.NewProductImportHandler(importService)
+	productFileHandler := handlers.NewProductFileHandler(productFileService)
+	questionHandler := handlers.NewProductQuestionHandler(questionService)
+	campaignHandler := handlers.NewSaleCampaignHandler(campaignService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+	taxHandler := handlers.NewTaxHandler(taxService)
+	currencyHandler := handlers.NewCurrencyHandler(currencyService)
+	licenseKeyHandler := handlers.NewLicenseKeyHandler(licenseKeyService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	translationHandler := handlers.NewTranslationHandler(translationService)
 
 	// Setup Gin router
 	r := gin.Default()
 
 	// Setup routes
-	routes.SetupRoutes(r, cfg, authHandler, userHandler, categoryHandler, productHandler, cartHandler, wishlistHandler, orderHandler, downloadHandler, reviewHandler, customOrderHandler, notificationHandler, analyticsHandler, apiLogRepo)
+	routes.SetupRoutes(r, cfg, authHandler, userHandler, categoryHandler, productHandler, cartHandler, wishlistHandler, orderHandler, downloadHandler, reviewHandler, customOrderHandler, notificationHandler, analyticsHandler, featuredHandler, bundleHandler, licenseTierHandler, workflowHandler, recommendationHandler, importHandler, productFileHandler, questionHandler, campaignHandler, couponHandler, taxHandler, currencyHandler, licenseKeyHandler, trashHandler, translationHandler, apiLogRepo)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.AppPort)
@@ -105,7 +225,27 @@ func main() {
 	log.Printf("📝 Environment: %s", cfg.AppEnv)
 	log.Printf("🗄️  Database: Connected to %s", cfg.DBName)
 
-	if err := r.Run(addr); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := &http.Server{Addr: addr, Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Shut down on SIGINT/SIGTERM, letting in-flight requests finish
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down server:", err)
+	}
+
+	// Store the views buffered since the last periodic flush
+	if err := productViewService.Flush(); err != nil {
+		log.Println("Failed to flush product views:", err)
 	}
 }
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"log"
+
+	"gin-quickstart/internal/config"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/pkg/utils"
+)
+
+// Recomputes rating_average, rating_count and the star histogram of every
+// product from its reviews. Run after importing reviews or fixing data by hand:
+//
+//	go run ./cmd/rebuild-ratings
+func main() {
+	cfg := config.LoadConfig()
+
+	db, err := utils.InitDB(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	reviewRepo := repositories.NewReviewRepository(db)
+	if err := reviewRepo.RebuildProductRatings(); err != nil {
+		log.Fatal("Failed to rebuild product ratings:", err)
+	}
+
+	log.Println("Product rating aggregates rebuilt successfully")
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -31,6 +34,40 @@ type Config struct {
 	MidtransServerKey string
 	MidtransClientKey string
 	MidtransIsProd    bool
+
+	// Catalog
+	FeaturedMaxPerCategory int
+	ViewDedupeWindow       time.Duration
+	ViewFlushInterval      time.Duration
+	PublishCheckInterval   time.Duration
+
+	// Recommendations
+	RecommendationRefreshInterval time.Duration
+
+	// Pricing
+	PriceHistoryInterval time.Duration
+
+	// Licensing
+	LicenseSigningKey string // Base64 Ed25519 seed; required
+
+	// Trash
+	TrashRetention     time.Duration // 0 keeps deleted rows until purged by hand
+	TrashPurgeInterval time.Duration
+
+	// Guest carts
+	GuestCartTTL             time.Duration // Inactivity after which a guest cart expires
+	GuestCartCleanupInterval time.Duration
+
+	// Orders
+	PendingOrderTTL            time.Duration // Orders without a payment proof are cancelled after this
+	PendingOrderExpiryInterval time.Duration
+
+	// Tax
+	TaxDefaultCountry string // Where buyers without a billing country are taxed, empty to not tax them
+
+	// Localization
+	DefaultLocale    string   // Locale of the content stored on products and categories
+	SupportedLocales []string // Includes DefaultLocale
 }
 
 func LoadConfig() *Config {
@@ -39,6 +76,8 @@ func LoadConfig() *Config {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	defaultLocale := strings.ToLower(getEnv("DEFAULT_LOCALE", "id"))
+
 	return &Config{
 		AppName:    getEnv("APP_NAME", "gin-quickstart"),
 		AppEnv:     getEnv("APP_ENV", "development"),
@@ -63,12 +102,82 @@ func LoadConfig() *Config {
 		MidtransServerKey: getEnv("MIDTRANS_SERVER_KEY", ""),
 		MidtransClientKey: getEnv("MIDTRANS_CLIENT_KEY", ""),
 		MidtransIsProd:    getEnv("MIDTRANS_IS_PROD", "false") == "true",
+
+		// Catalog
+		FeaturedMaxPerCategory: getEnvInt("FEATURED_MAX_PER_CATEGORY", 3),
+		ViewDedupeWindow:       getEnvDuration("VIEW_DEDUPE_WINDOW", 30*time.Minute),
+		ViewFlushInterval:      getEnvDuration("VIEW_FLUSH_INTERVAL", 30*time.Second),
+		PublishCheckInterval:   getEnvDuration("PUBLISH_CHECK_INTERVAL", time.Minute),
+
+		// Recommendations
+		RecommendationRefreshInterval: getEnvDuration("RECOMMENDATION_REFRESH_INTERVAL", time.Hour),
+
+		// Pricing
+		PriceHistoryInterval: getEnvDuration("PRICE_HISTORY_INTERVAL", 5*time.Minute),
+
+		// Licensing
+		LicenseSigningKey: getEnv("LICENSE_SIGNING_KEY", ""),
+
+		// Trash
+		TrashRetention:     getEnvOptionalDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		// Guest carts
+		GuestCartTTL:             getEnvDuration("GUEST_CART_TTL", 7*24*time.Hour),
+		GuestCartCleanupInterval: getEnvDuration("GUEST_CART_CLEANUP_INTERVAL", time.Hour),
+
+		// Orders
+		PendingOrderTTL:            getEnvDuration("PENDING_ORDER_TTL", 48*time.Hour),
+		PendingOrderExpiryInterval: getEnvDuration("PENDING_ORDER_EXPIRY_INTERVAL", time.Hour),
+
+		// Tax
+		TaxDefaultCountry: getEnv("TAX_DEFAULT_COUNTRY", "ID"),
+
+		// Localization
+		DefaultLocale:    defaultLocale,
+		SupportedLocales: supportedLocales(defaultLocale, getEnv("SUPPORTED_LOCALES", "id,en")),
 	}
 }
 
+// supportedLocales parses a comma separated locale list, making sure the
+// default locale is part of it
+func supportedLocales(defaultLocale, list string) []string {
+	locales := []string{defaultLocale}
+	for _, locale := range strings.Split(list, ",") {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		if locale == "" || locale == defaultLocale {
+			continue
+		}
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvOptionalDuration is getEnvDuration that also accepts 0 to turn a feature off
+func getEnvOptionalDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return defaultValue
+}
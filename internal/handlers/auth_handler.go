@@ -5,6 +5,7 @@ import (
 	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,17 +13,34 @@ import (
 )
 
 type AuthHandler struct {
-	service services.AuthService
-	config  *config.Config
+	service     services.AuthService
+	cartService services.CartService
+	config      *config.Config
 }
 
-func NewAuthHandler(service services.AuthService, config *config.Config) *AuthHandler {
+func NewAuthHandler(service services.AuthService, cartService services.CartService, config *config.Config) *AuthHandler {
 	return &AuthHandler{
-		service: service,
-		config:  config,
+		service:     service,
+		cartService: cartService,
+		config:      config,
 	}
 }
 
+// mergeGuestCart moves the visitor's guest cart, if they had one, into the
+// user's cart. Signing in does not fail when merging does.
+func (h *AuthHandler) mergeGuestCart(c *gin.Context, userID uint) {
+	token := cartToken(c)
+	if token == "" {
+		return
+	}
+
+	if err := h.cartService.MergeGuestCart(token, userID); err != nil {
+		log.Println("Failed to merge guest cart:", err)
+		return
+	}
+	c.SetCookie(cartTokenCookie, "", -1, "/", "", h.config.AppEnv == "production", true)
+}
+
 // Register godoc
 // @Summary Register new user
 // @Tags auth
@@ -44,6 +62,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	h.mergeGuestCart(c, authResp.User.ID)
+
 	utils.SuccessResponse(c, http.StatusCreated, "Registration successful", authResp)
 }
 
@@ -68,6 +88,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	h.mergeGuestCart(c, authResp.User.ID)
+
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", authResp)
 }
 
@@ -111,6 +133,8 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 		return
 	}
 
+	h.mergeGuestCart(c, authResp.User.ID)
+
 	utils.SuccessResponse(c, http.StatusOK, "Google login successful", authResp)
 }
 
@@ -155,5 +179,7 @@ func (h *AuthHandler) GithubCallback(c *gin.Context) {
 		return
 	}
 
+	h.mergeGuestCart(c, authResp.User.ID)
+
 	utils.SuccessResponse(c, http.StatusOK, "GitHub login successful", authResp)
 }
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BundleHandler struct {
+	bundleService services.BundleService
+}
+
+func NewBundleHandler(bundleService services.BundleService) *BundleHandler {
+	return &BundleHandler{
+		bundleService: bundleService,
+	}
+}
+
+// CreateBundle godoc
+// @Summary Create bundle (Admin only)
+// @Tags bundles
+// @Accept json,multipart/form-data
+// @Produce json
+// @Param bundle body models.BundleRequest true "Bundle data"
+// @Param images formData file false "Bundle images"
+// @Success 201 {object} utils.Response
+// @Router /bundles [post]
+// @Security Bearer
+func (h *BundleHandler) CreateBundle(c *gin.Context) {
+	var req models.BundleRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+
+	bundle, err := h.bundleService.CreateBundle(req, formImages(c), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Bundle created successfully", bundle)
+}
+
+// GetAllBundles godoc
+// @Summary Get all bundles
+// @Tags bundles
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Full-text search over title and description"
+// @Success 200 {object} utils.Response
+// @Router /bundles [get]
+func (h *BundleHandler) GetAllBundles(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	search := c.Query("search")
+
+	bundles, total, err := h.bundleService.GetAllBundles(page, limit, search)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bundles retrieved successfully", gin.H{
+		"bundles": bundles,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+// GetBundleByID godoc
+// @Summary Get bundle by ID
+// @Tags bundles
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Success 200 {object} utils.Response
+// @Router /bundles/{id} [get]
+func (h *BundleHandler) GetBundleByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bundle ID")
+		return
+	}
+
+	bundle, err := h.bundleService.GetBundleByID(uint(id), middleware.GetUserRole(c) == "admin")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bundle retrieved successfully", bundle)
+}
+
+// GetBundleBySlug godoc
+// @Summary Get bundle by slug
+// @Tags bundles
+// @Produce json
+// @Param slug path string true "Bundle slug"
+// @Success 200 {object} utils.Response
+// @Success 301 {object} utils.Response
+// @Router /bundles/slug/{slug} [get]
+func (h *BundleHandler) GetBundleBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	bundle, err := h.bundleService.GetBundleBySlug(slug, middleware.GetUserRole(c) == "admin")
+	if err != nil {
+		if current, redirectErr := h.bundleService.ResolveSlugRedirect(slug); redirectErr == nil {
+			slugRedirect(c, slug, current)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bundle retrieved successfully", bundle)
+}
+
+// UpdateBundle godoc
+// @Summary Update bundle (Admin only)
+// @Tags bundles
+// @Accept json,multipart/form-data
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Param bundle body models.BundleRequest true "Bundle data"
+// @Param images formData file false "Bundle images (replace existing)"
+// @Success 200 {object} utils.Response
+// @Router /bundles/{id} [put]
+// @Security Bearer
+func (h *BundleHandler) UpdateBundle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bundle ID")
+		return
+	}
+
+	var req models.BundleRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bundle, err := h.bundleService.UpdateBundle(uint(id), req, formImages(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bundle updated successfully", bundle)
+}
+
+// DeleteBundle godoc
+// @Summary Delete bundle (Admin only)
+// @Tags bundles
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Success 200 {object} utils.Response
+// @Router /bundles/{id} [delete]
+// @Security Bearer
+func (h *BundleHandler) DeleteBundle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid bundle ID")
+		return
+	}
+
+	if err := h.bundleService.DeleteBundle(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bundle deleted successfully", nil)
+}
+
+// formImages returns the "images" files of a multipart request, if any
+func formImages(c *gin.Context) []*multipart.FileHeader {
+	form, err := c.MultipartForm()
+	if err != nil || form == nil {
+		return nil
+	}
+	return form.File["images"]
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"gin-quickstart/internal/config"
 	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
 	"net/http"
@@ -10,14 +12,56 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Guest carts are identified by a signed token sent in this header or cookie
+const (
+	cartTokenHeader = "X-Cart-Token"
+	cartTokenCookie = "cart_token"
+)
+
 type CartHandler struct {
-	cartService services.CartService
+	cartService     services.CartService
+	currencyService services.CurrencyService
+	config          *config.Config
 }
 
-func NewCartHandler(cartService services.CartService) *CartHandler {
+func NewCartHandler(cartService services.CartService, currencyService services.CurrencyService, config *config.Config) *CartHandler {
 	return &CartHandler{
-		cartService: cartService,
+		cartService:     cartService,
+		currencyService: currencyService,
+		config:          config,
+	}
+}
+
+// cartOwner resolves whose cart the request works on: the signed in user's,
+// otherwise the guest cart of the request's cart token. With create set a
+// guest without a live cart gets a new one. The guest's token is sent back in
+// the response header and cookie. It answers the request with an error and
+// returns false when the cart cannot be resolved.
+func (h *CartHandler) cartOwner(c *gin.Context, create bool) (models.CartOwner, bool) {
+	if userID := middleware.GetUserID(c); userID != 0 {
+		return models.CartOwner{UserID: userID}, true
+	}
+
+	owner, token, err := h.cartService.GuestCart(cartToken(c), create)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return owner, false
 	}
+	if token != "" {
+		c.Header(cartTokenHeader, token)
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(cartTokenCookie, token, int(h.config.GuestCartTTL.Seconds()), "/", "", h.config.AppEnv == "production", true)
+	}
+	return owner, true
+}
+
+// cartToken reads the guest cart token from the header, falling back to the cookie
+func cartToken(c *gin.Context) string {
+	if token := c.GetHeader(cartTokenHeader); token != "" {
+		return token
+	}
+	token, _ := c.Cookie(cartTokenCookie)
+	return token
 }
 
 // AddToCart godoc
@@ -26,13 +70,15 @@ func NewCartHandler(cartService services.CartService) *CartHandler {
 // @Accept json
 // @Produce json
 // @Param item body object true "Cart item"
+// @Param X-Cart-Token header string false "Guest cart token, when not signed in"
 // @Success 201 {object} utils.Response
 // @Router /cart [post]
 // @Security Bearer
 func (h *CartHandler) AddToCart(c *gin.Context) {
 	var req struct {
-		ProductID uint `json:"product_id" binding:"required"`
-		Quantity  int  `json:"quantity" binding:"required,gt=0"`
+		ProductID     uint  `json:"product_id" binding:"required"`
+		LicenseTierID *uint `json:"license_tier_id"`
+		Quantity      int   `json:"quantity" binding:"required,gt=0"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -40,9 +86,12 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 		return
 	}
 
-	userID := middleware.GetUserID(c)
+	owner, ok := h.cartOwner(c, true)
+	if !ok {
+		return
+	}
 
-	cart, err := h.cartService.AddToCart(userID, req.ProductID, req.Quantity)
+	cart, err := h.cartService.AddToCart(owner, req.ProductID, req.LicenseTierID, req.Quantity)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -51,26 +100,35 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusCreated, "Item added to cart", cart)
 }
 
-// GetUserCart godoc
-// @Summary Get user's cart
+// GetCart godoc
+// @Summary Get the user's or guest's cart
 // @Tags cart
 // @Produce json
+// @Param currency query string false "Currency of the total, defaults to the user's preference"
+// @Param coupon query string false "Coupon code to preview the discount of"
+// @Param X-Cart-Token header string false "Guest cart token, when not signed in"
 // @Success 200 {object} utils.Response
 // @Router /cart [get]
 // @Security Bearer
-func (h *CartHandler) GetUserCart(c *gin.Context) {
-	userID := middleware.GetUserID(c)
+func (h *CartHandler) GetCart(c *gin.Context) {
+	owner, ok := h.cartOwner(c, false)
+	if !ok {
+		return
+	}
+
+	currency, err := h.currencyService.ResolveCurrency(c.Query("currency"), owner.UserID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	carts, total, err := h.cartService.GetUserCart(userID)
+	cart, err := h.cartService.GetCart(owner, currency, c.Query("coupon"))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Cart retrieved successfully", gin.H{
-		"items": carts,
-		"total": total,
-	})
+	utils.SuccessResponse(c, http.StatusOK, "Cart retrieved successfully", cart)
 }
 
 // UpdateCartItem godoc
@@ -80,6 +138,7 @@ func (h *CartHandler) GetUserCart(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Cart item ID"
 // @Param item body object true "Update data"
+// @Param X-Cart-Token header string false "Guest cart token, when not signed in"
 // @Success 200 {object} utils.Response
 // @Router /cart/{id} [put]
 // @Security Bearer
@@ -99,9 +158,12 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 		return
 	}
 
-	userID := middleware.GetUserID(c)
+	owner, ok := h.cartOwner(c, false)
+	if !ok {
+		return
+	}
 
-	cart, err := h.cartService.UpdateCartItem(userID, uint(cartID), req.Quantity)
+	cart, err := h.cartService.UpdateCartItem(owner, uint(cartID), req.Quantity)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -115,6 +177,7 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 // @Tags cart
 // @Produce json
 // @Param id path int true "Cart item ID"
+// @Param X-Cart-Token header string false "Guest cart token, when not signed in"
 // @Success 200 {object} utils.Response
 // @Router /cart/{id} [delete]
 // @Security Bearer
@@ -125,9 +188,12 @@ func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 		return
 	}
 
-	userID := middleware.GetUserID(c)
+	owner, ok := h.cartOwner(c, false)
+	if !ok {
+		return
+	}
 
-	if err := h.cartService.RemoveFromCart(userID, uint(cartID)); err != nil {
+	if err := h.cartService.RemoveFromCart(owner, uint(cartID)); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -136,16 +202,20 @@ func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 }
 
 // ClearCart godoc
-// @Summary Clear user's cart
+// @Summary Clear the user's or guest's cart
 // @Tags cart
 // @Produce json
+// @Param X-Cart-Token header string false "Guest cart token, when not signed in"
 // @Success 200 {object} utils.Response
 // @Router /cart/clear [delete]
 // @Security Bearer
 func (h *CartHandler) ClearCart(c *gin.Context) {
-	userID := middleware.GetUserID(c)
+	owner, ok := h.cartOwner(c, false)
+	if !ok {
+		return
+	}
 
-	if err := h.cartService.ClearCart(userID); err != nil {
+	if err := h.cartService.ClearCart(owner); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
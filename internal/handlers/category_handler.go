@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"fmt"
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
 	"net/http"
@@ -10,12 +13,14 @@ import (
 )
 
 type CategoryHandler struct {
-	categoryService services.CategoryService
+	categoryService    services.CategoryService
+	translationService services.TranslationService
 }
 
-func NewCategoryHandler(categoryService services.CategoryService) *CategoryHandler {
+func NewCategoryHandler(categoryService services.CategoryService, translationService services.TranslationService) *CategoryHandler {
 	return &CategoryHandler{
-		categoryService: categoryService,
+		categoryService:    categoryService,
+		translationService: translationService,
 	}
 }
 
@@ -24,22 +29,18 @@ func NewCategoryHandler(categoryService services.CategoryService) *CategoryHandl
 // @Tags categories
 // @Accept json
 // @Produce json
-// @Param category body object true "Category data"
+// @Param category body models.CategoryRequest true "Category data, parent_id nests it under another category"
 // @Success 201 {object} utils.Response
 // @Router /categories [post]
 // @Security Bearer
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
-	var req struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-	}
-
+	var req models.CategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	category, err := h.categoryService.CreateCategory(req.Name, req.Description)
+	category, err := h.categoryService.CreateCategory(req)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -61,9 +62,41 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 		return
 	}
 
+	pointers := make([]*models.Category, len(categories))
+	for i := range categories {
+		pointers[i] = &categories[i]
+	}
+	if !localizeCategories(c, h.translationService, pointers...) {
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Categories retrieved successfully", categories)
 }
 
+// GetCategoryTree godoc
+// @Summary Get all categories as a tree
+// @Tags categories
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.GetCategoryTree()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pointers := make([]*models.Category, len(tree))
+	for i := range tree {
+		pointers[i] = &tree[i]
+	}
+	if !localizeCategories(c, h.translationService, pointers...) {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category tree retrieved successfully", tree)
+}
+
 // GetCategoryByID godoc
 // @Summary Get category by ID
 // @Tags categories
@@ -83,6 +116,9 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 		return
 	}
+	if !localizeCategories(c, h.translationService, category) {
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Category retrieved successfully", category)
 }
@@ -91,17 +127,26 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 // @Summary Get category by slug
 // @Tags categories
 // @Produce json
+// @Description Old slugs of renamed categories answer with 301 and the current slug
 // @Param slug path string true "Category slug"
 // @Success 200 {object} utils.Response
+// @Success 301 {object} utils.Response
 // @Router /categories/slug/{slug} [get]
 func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
 	category, err := h.categoryService.GetCategoryBySlug(slug)
 	if err != nil {
+		if current, redirectErr := h.categoryService.ResolveSlugRedirect(slug); redirectErr == nil {
+			slugRedirect(c, slug, current)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 		return
 	}
+	if !localizeCategories(c, h.translationService, category) {
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Category retrieved successfully", category)
 }
@@ -112,7 +157,7 @@ func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
-// @Param category body object true "Category data"
+// @Param category body models.CategoryUpdateRequest true "Category data"
 // @Success 200 {object} utils.Response
 // @Router /categories/{id} [put]
 // @Security Bearer
@@ -123,31 +168,87 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
+	var req models.CategoryUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
 
+	utils.SuccessResponse(c, http.StatusOK, "Category updated successfully", category)
+}
+
+// MoveCategory godoc
+// @Summary Move a category and its subcategories under another parent (Admin only)
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param request body models.CategoryMoveRequest true "New parent, null for the top level"
+// @Success 200 {object} utils.Response
+// @Router /categories/{id}/move [put]
+// @Security Bearer
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	var req models.CategoryMoveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	category, err := h.categoryService.UpdateCategory(uint(id), req.Name, req.Description)
+	category, err := h.categoryService.MoveCategory(uint(id), req)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Category updated successfully", category)
+	utils.SuccessResponse(c, http.StatusOK, "Category moved successfully", category)
+}
+
+// ReorderCategories godoc
+// @Summary Set the display order of a parent's subcategories (Admin only)
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body models.CategoryReorderRequest true "Parent (null for the top level) and every child ID in the new order"
+// @Success 200 {object} utils.Response
+// @Router /categories/reorder [put]
+// @Security Bearer
+func (h *CategoryHandler) ReorderCategories(c *gin.Context) {
+	var req models.CategoryReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	categories, err := h.categoryService.ReorderCategories(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categories reordered successfully", categories)
 }
 
 // DeleteCategory godoc
 // @Summary Delete category (Admin only)
 // @Tags categories
 // @Produce json
+// @Description Products and subcategories move to reassign_to. Without it, a category that still has any answers 409 listing them.
 // @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category receiving the products and subcategories"
 // @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /categories/{id} [delete]
 // @Security Bearer
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
@@ -157,10 +258,31 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	if err := h.categoryService.DeleteCategory(uint(id)); err != nil {
+	var reassignTo *uint
+	if target := c.Query("reassign_to"); target != "" {
+		targetID, err := strconv.ParseUint(target, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reassign_to category ID")
+			return
+		}
+		val := uint(targetID)
+		reassignTo = &val
+	}
+
+	conflict, err := h.categoryService.DeleteCategory(uint(id), middleware.GetUserID(c), reassignTo)
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if conflict != nil {
+		c.JSON(http.StatusConflict, utils.Response{
+			Success: false,
+			Message: fmt.Sprintf("Category still has %d product(s) and %d subcategory(ies), pass reassign_to to move them", conflict.ProductCount, len(conflict.Subcategories)),
+			Data:    conflict,
+		})
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", nil)
 }
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CouponHandler struct {
+	couponService services.CouponService
+}
+
+func NewCouponHandler(couponService services.CouponService) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+	}
+}
+
+// GetCoupons godoc
+// @Summary Get coupons (Admin only)
+// @Tags admin
+// @Produce json
+// @Param search query string false "Search by code"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response
+// @Router /admin/coupons [get]
+// @Security Bearer
+func (h *CouponHandler) GetCoupons(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	coupons, total, err := h.couponService.GetCoupons(page, limit, c.Query("search"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coupons retrieved successfully", gin.H{
+		"coupons": coupons,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+	})
+}
+
+// GetCoupon godoc
+// @Summary Get a coupon (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/coupons/{id} [get]
+// @Security Bearer
+func (h *CouponHandler) GetCoupon(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	coupon, err := h.couponService.GetCoupon(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coupon retrieved successfully", coupon)
+}
+
+// CreateCoupon godoc
+// @Summary Create a coupon (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param coupon body models.CouponRequest true "Coupon"
+// @Success 201 {object} utils.Response
+// @Router /admin/coupons [post]
+// @Security Bearer
+func (h *CouponHandler) CreateCoupon(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+
+	var req models.CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	coupon, err := h.couponService.CreateCoupon(req, adminID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Coupon created successfully", coupon)
+}
+
+// UpdateCoupon godoc
+// @Summary Update a coupon (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Param coupon body models.CouponRequest true "Coupon"
+// @Success 200 {object} utils.Response
+// @Router /admin/coupons/{id} [put]
+// @Security Bearer
+func (h *CouponHandler) UpdateCoupon(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	var req models.CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	coupon, err := h.couponService.UpdateCoupon(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coupon updated successfully", coupon)
+}
+
+// DeleteCoupon godoc
+// @Summary Delete a coupon (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/coupons/{id} [delete]
+// @Security Bearer
+func (h *CouponHandler) DeleteCoupon(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	if err := h.couponService.DeleteCoupon(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coupon deleted successfully", nil)
+}
+
+// GetRedemptions godoc
+// @Summary Get the orders a coupon was used on (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response
+// @Router /admin/coupons/{id}/redemptions [get]
+// @Security Bearer
+func (h *CouponHandler) GetRedemptions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	redemptions, total, err := h.couponService.GetRedemptions(uint(id), page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Redemptions retrieved successfully", gin.H{
+		"redemptions": redemptions,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+	})
+}
+
+// GetUsageReport godoc
+// @Summary Get coupon usage in a period, amounts in the base currency (Admin only)
+// @Tags admin
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)" default(30 days ago)
+// @Param end_date query string false "End date (YYYY-MM-DD), inclusive" default(today)
+// @Success 200 {object} utils.Response
+// @Router /admin/coupons/usage [get]
+// @Security Bearer
+func (h *CouponHandler) GetUsageReport(c *gin.Context) {
+	today := time.Now().Truncate(24 * time.Hour)
+	startDate := today.AddDate(0, 0, -30)
+	endDate := today
+
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date, expected YYYY-MM-DD")
+			return
+		}
+		startDate = parsed
+	}
+
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date, expected YYYY-MM-DD")
+			return
+		}
+		endDate = parsed
+	}
+
+	// The end date counts as a whole day
+	report, err := h.couponService.GetUsageReport(startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Coupon usage retrieved successfully", gin.H{
+		"start_date": startDate.Format("2006-01-02"),
+		"end_date":   endDate.Format("2006-01-02"),
+		"currency":   models.BaseCurrency,
+		"coupons":    report,
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyHandler struct {
+	currencyService services.CurrencyService
+}
+
+func NewCurrencyHandler(currencyService services.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{
+		currencyService: currencyService,
+	}
+}
+
+// GetCurrencies godoc
+// @Summary Get supported currencies and their current exchange rates
+// @Tags currencies
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /currencies [get]
+func (h *CurrencyHandler) GetCurrencies(c *gin.Context) {
+	currencies, err := h.currencyService.GetCurrencies()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Currencies retrieved successfully", gin.H{
+		"base":       models.BaseCurrency,
+		"currencies": currencies,
+	})
+}
+
+// GetRates godoc
+// @Summary Get exchange rate history (Admin only)
+// @Tags admin
+// @Produce json
+// @Param currency query string false "Currency code"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response
+// @Router /admin/exchange-rates [get]
+// @Security Bearer
+func (h *CurrencyHandler) GetRates(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	rates, total, err := h.currencyService.GetRates(c.Query("currency"), page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exchange rates retrieved successfully", gin.H{
+		"rates": rates,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// CreateRate godoc
+// @Summary Add an exchange rate taking effect at a given time (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param rate body models.ExchangeRateRequest true "Exchange rate"
+// @Success 201 {object} utils.Response
+// @Router /admin/exchange-rates [post]
+// @Security Bearer
+func (h *CurrencyHandler) CreateRate(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+
+	var req models.ExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rate, err := h.currencyService.CreateRate(req, adminID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Exchange rate created successfully", rate)
+}
+
+// DeleteRate godoc
+// @Summary Delete an exchange rate (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Exchange rate ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/exchange-rates/{id} [delete]
+// @Security Bearer
+func (h *CurrencyHandler) DeleteRate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid exchange rate ID")
+		return
+	}
+
+	if err := h.currencyService.DeleteRate(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exchange rate deleted successfully", nil)
+}
+
+// displayCurrency converts product prices to the currency asked for with
+// ?currency= or preferred by the signed-in user. It answers the request with
+// an error and returns false when the conversion is not possible.
+func displayCurrency(c *gin.Context, currencyService services.CurrencyService, products ...*models.Product) bool {
+	currency, err := currencyService.ResolveCurrency(c.Query("currency"), middleware.GetUserID(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if err := currencyService.ApplyDisplay(currency, products...); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// displayCurrencyList is displayCurrency for a slice of products
+func displayCurrencyList(c *gin.Context, currencyService services.CurrencyService, products []models.Product) bool {
+	pointers := make([]*models.Product, len(products))
+	for i := range products {
+		pointers[i] = &products[i]
+	}
+	return displayCurrency(c, currencyService, pointers...)
+}
@@ -18,17 +18,18 @@ func NewCustomOrderHandler(customOrderService services.CustomOrderService) *Cust
 }
 
 type CreateCustomOrderRequest struct {
-	Title        string  `json:"title" binding:"required"`
-	Description  string  `json:"description" binding:"required"`
-	Requirements string  `json:"requirements"`
-	Budget       float64 `json:"budget"`
+	Title        string `json:"title" binding:"required"`
+	Description  string `json:"description" binding:"required"`
+	Requirements string `json:"requirements"`
+	Currency     string `json:"currency"`
+	Budget       int64  `json:"budget"`
 }
 
 type ProcessCustomOrderRequest struct {
-	Status        string   `json:"status" binding:"required"`
-	AdminNotes    string   `json:"admin_notes"`
-	QuotedPrice   *float64 `json:"quoted_price"`
-	EstimatedDays *int     `json:"estimated_days"`
+	Status        string `json:"status" binding:"required"`
+	AdminNotes    string `json:"admin_notes"`
+	QuotedPrice   *int64 `json:"quoted_price"`
+	EstimatedDays *int   `json:"estimated_days"`
 }
 
 // CreateCustomOrder godoc
@@ -55,6 +56,7 @@ func (h *CustomOrderHandler) CreateCustomOrder(c *gin.Context) {
 		req.Title,
 		req.Description,
 		req.Requirements,
+		req.Currency,
 		req.Budget,
 	)
 	if err != nil {
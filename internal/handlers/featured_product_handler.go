@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeaturedProductHandler struct {
+	featuredService    services.FeaturedProductService
+	currencyService    services.CurrencyService
+	translationService services.TranslationService
+}
+
+func NewFeaturedProductHandler(featuredService services.FeaturedProductService, currencyService services.CurrencyService, translationService services.TranslationService) *FeaturedProductHandler {
+	return &FeaturedProductHandler{
+		featuredService:    featuredService,
+		currencyService:    currencyService,
+		translationService: translationService,
+	}
+}
+
+// GetFeaturedProducts godoc
+// @Summary Get featured products
+// @Tags products
+// @Produce json
+// @Param limit query int false "Number of products" default(10)
+// @Param currency query string false "Display currency, defaults to the user's preference"
+// @Success 200 {object} utils.Response
+// @Router /products/featured [get]
+func (h *FeaturedProductHandler) GetFeaturedProducts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	products, err := h.featuredService.GetFeaturedProducts(limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !displayCurrencyList(c, h.currencyService, products) || !localizeProductList(c, h.translationService, products) {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Featured products retrieved successfully", products)
+}
+
+// GetSlots godoc
+// @Summary Get all featured slots (Admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /admin/featured [get]
+// @Security Bearer
+func (h *FeaturedProductHandler) GetSlots(c *gin.Context) {
+	slots, err := h.featuredService.GetSlots()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Featured slots retrieved successfully", slots)
+}
+
+// CreateSlot godoc
+// @Summary Create featured slot (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param slot body models.FeaturedProductRequest true "Featured slot"
+// @Success 201 {object} utils.Response
+// @Router /admin/featured [post]
+// @Security Bearer
+func (h *FeaturedProductHandler) CreateSlot(c *gin.Context) {
+	var req models.FeaturedProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	adminID := middleware.GetUserID(c)
+
+	slot, err := h.featuredService.CreateSlot(req, adminID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Featured slot created successfully", slot)
+}
+
+// UpdateSlot godoc
+// @Summary Update featured slot (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Featured slot ID"
+// @Param slot body models.FeaturedProductRequest true "Featured slot"
+// @Success 200 {object} utils.Response
+// @Router /admin/featured/{id} [put]
+// @Security Bearer
+func (h *FeaturedProductHandler) UpdateSlot(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid featured slot ID")
+		return
+	}
+
+	var req models.FeaturedProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slot, err := h.featuredService.UpdateSlot(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Featured slot updated successfully", slot)
+}
+
+// DeleteSlot godoc
+// @Summary Delete featured slot (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Featured slot ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/featured/{id} [delete]
+// @Security Bearer
+func (h *FeaturedProductHandler) DeleteSlot(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid featured slot ID")
+		return
+	}
+
+	if err := h.featuredService.DeleteSlot(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Featured slot deleted successfully", nil)
+}
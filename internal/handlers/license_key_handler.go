@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LicenseKeyHandler struct {
+	licenseService services.LicenseKeyService
+}
+
+func NewLicenseKeyHandler(licenseService services.LicenseKeyService) *LicenseKeyHandler {
+	return &LicenseKeyHandler{
+		licenseService: licenseService,
+	}
+}
+
+// GetPublicKey godoc
+// @Summary Get the Ed25519 public key for verifying license keys offline
+// @Tags licenses
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /licenses/public-key [get]
+func (h *LicenseKeyHandler) GetPublicKey(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Public key retrieved successfully", gin.H{
+		"algorithm":  "Ed25519",
+		"public_key": base64.StdEncoding.EncodeToString(h.licenseService.PublicKey()),
+		"prefix":     utils.LicenseKeyPrefix,
+	})
+}
+
+// ValidateLicense godoc
+// @Summary Validate a license key, optionally for a machine fingerprint
+// @Tags licenses
+// @Accept json
+// @Produce json
+// @Param request body models.LicenseValidateRequest true "License key"
+// @Success 200 {object} utils.Response
+// @Router /licenses/validate [post]
+func (h *LicenseKeyHandler) ValidateLicense(c *gin.Context) {
+	var req models.LicenseValidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.licenseService.Validate(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License validated", result)
+}
+
+// ActivateLicense godoc
+// @Summary Activate a license key on a machine
+// @Tags licenses
+// @Accept json
+// @Produce json
+// @Param request body models.LicenseActivationRequest true "License key and machine fingerprint"
+// @Success 200 {object} utils.Response
+// @Router /licenses/activate [post]
+func (h *LicenseKeyHandler) ActivateLicense(c *gin.Context) {
+	var req models.LicenseActivationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	activation, err := h.licenseService.Activate(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License activated successfully", activation)
+}
+
+// DeactivateLicense godoc
+// @Summary Release a machine's activation of a license key
+// @Tags licenses
+// @Accept json
+// @Produce json
+// @Param request body models.LicenseActivationRequest true "License key and machine fingerprint"
+// @Success 200 {object} utils.Response
+// @Router /licenses/deactivate [post]
+func (h *LicenseKeyHandler) DeactivateLicense(c *gin.Context) {
+	var req models.LicenseActivationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.licenseService.Deactivate(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License deactivated successfully", nil)
+}
+
+// GetMyLicenses godoc
+// @Summary Get the current user's license keys and their activations
+// @Tags licenses
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /user/licenses [get]
+// @Security Bearer
+func (h *LicenseKeyHandler) GetMyLicenses(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	licenses, err := h.licenseService.GetUserLicenses(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Licenses retrieved successfully", licenses)
+}
+
+// RemoveActivation godoc
+// @Summary Release an activation of one of the current user's license keys
+// @Tags licenses
+// @Produce json
+// @Param id path int true "License ID"
+// @Param activation_id path int true "Activation ID"
+// @Success 200 {object} utils.Response
+// @Router /user/licenses/{id}/activations/{activation_id} [delete]
+// @Security Bearer
+func (h *LicenseKeyHandler) RemoveActivation(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	licenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid license ID")
+		return
+	}
+
+	activationID, err := strconv.ParseUint(c.Param("activation_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid activation ID")
+		return
+	}
+
+	if err := h.licenseService.DeactivateForUser(userID, uint(licenseID), uint(activationID)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Activation removed successfully", nil)
+}
+
+// RevokeLicense godoc
+// @Summary Revoke a license key (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "License ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/licenses/{id}/revoke [post]
+// @Security Bearer
+func (h *LicenseKeyHandler) RevokeLicense(c *gin.Context) {
+	licenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid license ID")
+		return
+	}
+
+	if err := h.licenseService.Revoke(uint(licenseID)); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License revoked successfully", nil)
+}
+
+// IssueOrderLicenses godoc
+// @Summary Issue missing license keys for a paid order (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/orders/{id}/licenses [post]
+// @Security Bearer
+func (h *LicenseKeyHandler) IssueOrderLicenses(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	licenses, err := h.licenseService.IssueForOrderID(uint(orderID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License keys issued successfully", licenses)
+}
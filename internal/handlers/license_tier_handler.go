@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LicenseTierHandler struct {
+	tierService services.LicenseTierService
+}
+
+func NewLicenseTierHandler(tierService services.LicenseTierService) *LicenseTierHandler {
+	return &LicenseTierHandler{
+		tierService: tierService,
+	}
+}
+
+// GetProductLicenses godoc
+// @Summary Get license tiers available for a product
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/licenses [get]
+func (h *LicenseTierHandler) GetProductLicenses(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	tiers, err := h.tierService.GetProductTiers(uint(productID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License tiers retrieved successfully", tiers)
+}
+
+// CreateLicense godoc
+// @Summary Add a license tier to a product (Admin only)
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param tier body models.LicenseTierRequest true "License tier"
+// @Success 201 {object} utils.Response
+// @Router /products/{id}/licenses [post]
+// @Security Bearer
+func (h *LicenseTierHandler) CreateLicense(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var req models.LicenseTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tier, err := h.tierService.CreateTier(uint(productID), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "License tier created successfully", tier)
+}
+
+// UpdateLicense godoc
+// @Summary Update a product license tier (Admin only)
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param tier_id path int true "License tier ID"
+// @Param tier body models.LicenseTierRequest true "License tier"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/licenses/{tier_id} [put]
+// @Security Bearer
+func (h *LicenseTierHandler) UpdateLicense(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	tierID, err := strconv.ParseUint(c.Param("tier_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid license tier ID")
+		return
+	}
+
+	var req models.LicenseTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tier, err := h.tierService.UpdateTier(uint(productID), uint(tierID), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License tier updated successfully", tier)
+}
+
+// DeleteLicense godoc
+// @Summary Delete a product license tier (Admin only)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param tier_id path int true "License tier ID"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/licenses/{tier_id} [delete]
+// @Security Bearer
+func (h *LicenseTierHandler) DeleteLicense(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	tierID, err := strconv.ParseUint(c.Param("tier_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid license tier ID")
+		return
+	}
+
+	if err := h.tierService.DeleteTier(uint(productID), uint(tierID)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "License tier deleted successfully", nil)
+}
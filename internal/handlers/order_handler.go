@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
 	"net/http"
@@ -11,17 +12,19 @@ import (
 )
 
 type OrderHandler struct {
-	orderService services.OrderService
+	orderService    services.OrderService
+	currencyService services.CurrencyService
 }
 
-func NewOrderHandler(orderService services.OrderService) *OrderHandler {
+func NewOrderHandler(orderService services.OrderService, currencyService services.CurrencyService) *OrderHandler {
 	return &OrderHandler{
-		orderService: orderService,
+		orderService:    orderService,
+		currencyService: currencyService,
 	}
 }
 
 // CreateOrder godoc
-// @Summary Create order (checkout)
+// @Summary Create order (checkout) for a product or a bundle
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -31,8 +34,12 @@ func NewOrderHandler(orderService services.OrderService) *OrderHandler {
 // @Security Bearer
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	var req struct {
-		ProductID uint `json:"product_id" binding:"required"`
-		Quantity  int  `json:"quantity" binding:"required,gt=0"`
+		ProductID     uint   `json:"product_id" binding:"required_without=BundleID"`
+		BundleID      uint   `json:"bundle_id"`
+		LicenseTierID *uint  `json:"license_tier_id"`
+		Quantity      int    `json:"quantity" binding:"omitempty,gt=0"`
+		Currency      string `json:"currency"`
+		CouponCode    string `json:"coupon_code"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -42,7 +49,21 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 	userID := middleware.GetUserID(c)
 
-	order, err := h.orderService.CreateOrder(userID, req.ProductID, req.Quantity)
+	currency, err := h.currencyService.ResolveCurrency(req.Currency, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var order *models.Order
+	if req.BundleID > 0 {
+		order, err = h.orderService.CreateBundleOrder(userID, req.BundleID, currency, req.CouponCode)
+	} else {
+		if req.Quantity == 0 {
+			req.Quantity = 1
+		}
+		order, err = h.orderService.CreateOrder(userID, req.ProductID, req.LicenseTierID, req.Quantity, currency, req.CouponCode)
+	}
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -51,6 +72,83 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusCreated, "Order created successfully. Please upload payment proof.", order)
 }
 
+// CheckoutCart godoc
+// @Summary Check out the whole cart as one order
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param checkout body object false "Currency to pay in and coupon code"
+// @Success 201 {object} utils.Response
+// @Router /cart/checkout [post]
+// @Security Bearer
+func (h *OrderHandler) CheckoutCart(c *gin.Context) {
+	var req struct {
+		Currency   string `json:"currency"`
+		CouponCode string `json:"coupon_code"`
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	userID := middleware.GetUserID(c)
+
+	currency, err := h.currencyService.ResolveCurrency(req.Currency, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.orderService.CheckoutCart(userID, currency, req.CouponCode)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Order created successfully. Please upload payment proof.", order)
+}
+
+// UpgradeLicense godoc
+// @Summary Upgrade an owned product license to a higher tier
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param upgrade body object true "Product and target license tier"
+// @Success 201 {object} utils.Response
+// @Router /orders/upgrade [post]
+// @Security Bearer
+func (h *OrderHandler) UpgradeLicense(c *gin.Context) {
+	var req struct {
+		ProductID     uint   `json:"product_id" binding:"required"`
+		LicenseTierID uint   `json:"license_tier_id" binding:"required"`
+		Currency      string `json:"currency"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+
+	currency, err := h.currencyService.ResolveCurrency(req.Currency, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.orderService.UpgradeLicense(userID, req.ProductID, req.LicenseTierID, currency)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Upgrade order created successfully. Please upload payment proof.", order)
+}
+
 // GetUserOrders godoc
 // @Summary Get user's orders
 // @Tags orders
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductFileHandler struct {
+	fileService services.ProductFileService
+}
+
+func NewProductFileHandler(fileService services.ProductFileService) *ProductFileHandler {
+	return &ProductFileHandler{
+		fileService: fileService,
+	}
+}
+
+// GetProductTree godoc
+// @Summary Browse the file tree of a source code product
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/tree [get]
+func (h *ProductFileHandler) GetProductTree(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	tree, err := h.fileService.GetTree(uint(productID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Product file tree retrieved successfully", tree)
+}
+
+// GetProductFile godoc
+// @Summary Read a previewable file of a source code product
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param path query string true "File path inside the archive"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/tree/file [get]
+func (h *ProductFileHandler) GetProductFile(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	filePath := c.Query("path")
+	if filePath == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File path is required")
+		return
+	}
+
+	content, err := h.fileService.GetFileContent(uint(productID), filePath)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "File preview retrieved successfully", content)
+}
+
+// UploadDeliverable godoc
+// @Summary Upload the downloadable file of a product (Admin only)
+// @Description Source code products must upload a ZIP; its file tree is indexed for browsing.
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param file formData file true "Deliverable file"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/deliverable [post]
+// @Security Bearer
+func (h *ProductFileHandler) UploadDeliverable(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Deliverable file is required")
+		return
+	}
+
+	tree, err := h.fileService.UploadDeliverable(uint(productID), file)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Deliverable uploaded successfully", tree)
+}
+
+// SetPreviewableFiles godoc
+// @Summary Mark archive files as previewable or hidden (Admin only)
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductFilePreviewRequest true "Files to update"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/tree/preview [put]
+// @Security Bearer
+func (h *ProductFileHandler) SetPreviewableFiles(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductFilePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tree, err := h.fileService.SetPreviewable(uint(productID), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Previewable files updated successfully", tree)
+}
@@ -1,22 +1,38 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// listingBundleLimit is how many bundles are shown with the product listing
+const listingBundleLimit = 4
+
 type ProductHandler struct {
-	productService services.ProductService
+	productService     services.ProductService
+	viewService        services.ProductViewService
+	bundleService      services.BundleService
+	currencyService    services.CurrencyService
+	translationService services.TranslationService
 }
 
-func NewProductHandler(productService services.ProductService) *ProductHandler {
+func NewProductHandler(productService services.ProductService, viewService services.ProductViewService, bundleService services.BundleService, currencyService services.CurrencyService, translationService services.TranslationService) *ProductHandler {
 	return &ProductHandler{
-		productService: productService,
+		productService:     productService,
+		viewService:        viewService,
+		bundleService:      bundleService,
+		currencyService:    currencyService,
+		translationService: translationService,
 	}
 }
 
@@ -31,7 +47,6 @@ func NewProductHandler(productService services.ProductService) *ProductHandler {
 // @Param stock formData int true "Product stock"
 // @Param category_id formData int true "Category ID"
 // @Param is_featured formData bool false "Is featured"
-// @Param is_active formData bool false "Is active"
 // @Param image formData file false "Product image"
 // @Success 201 {object} utils.Response
 // @Router /products [post]
@@ -67,42 +82,116 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param category_id query int false "Filter by category"
-// @Param search query string false "Search by name or description"
+// @Param include_subcategories query bool false "Also list products of the category's subcategories"
+// @Param search query string false "Full-text search over title, description, tech stack and features"
+// @Param min_price query int false "Minimum price in IDR (discount price when on sale)"
+// @Param max_price query int false "Maximum price in IDR (discount price when on sale)"
+// @Param type query string false "Product types, comma separated (source_code,pdf,template,other)"
+// @Param tech_stack query string false "Tech stack tags, comma separated (all must match)"
+// @Param min_rating query number false "Minimum average rating"
+// @Param on_sale query bool false "Only products with a discount"
+// @Param sort query string false "relevance, newest, price_asc, price_desc, popular, rating"
+// @Param currency query string false "Display currency, defaults to the user's preference"
 // @Success 200 {object} utils.Response
 // @Router /products [get]
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	search := c.Query("search")
 
-	var categoryID *uint
+	filter := models.ProductFilter{
+		IncludeSubcategories: c.Query("include_subcategories") == "true",
+		Search:               c.Query("search"),
+		Types:                queryList(c, "type"),
+		TechStacks:           queryList(c, "tech_stack"),
+		OnSale:               c.Query("on_sale") == "true",
+		Sort:                 c.Query("sort"),
+	}
+
 	if catID := c.Query("category_id"); catID != "" {
 		id, err := strconv.ParseUint(catID, 10, 32)
 		if err == nil {
 			val := uint(id)
-			categoryID = &val
+			filter.CategoryID = &val
 		}
 	}
 
-	products, total, err := h.productService.GetAllProducts(page, limit, categoryID, search)
+	var err error
+	if filter.MinPrice, err = queryInt(c, "min_price"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid min_price")
+		return
+	}
+	if filter.MaxPrice, err = queryInt(c, "max_price"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid max_price")
+		return
+	}
+	if filter.MinRating, err = queryFloat(c, "min_rating"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid min_rating")
+		return
+	}
+
+	if err := h.productService.ValidateFilter(&filter); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products, total, err := h.productService.GetAllProducts(page, limit, filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !displayCurrencyList(c, h.currencyService, products) || !localizeProductList(c, h.translationService, products) {
+		return
+	}
+
+	facets, err := h.productService.GetProductFacets(filter)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	// Matching bundles are listed alongside the first page of products. Bundles
+	// have no category, type, price range or tech stack of their own, so any
+	// filter besides a search leaves them out.
+	bundles := []models.Bundle{}
+	if page <= 1 && searchOnly(filter) {
+		found, _, err := h.bundleService.GetAllBundles(1, listingBundleLimit, filter.Search)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if found != nil {
+			bundles = found
+		}
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Products retrieved successfully", gin.H{
 		"products": products,
+		"bundles":  bundles,
 		"total":    total,
 		"page":     page,
 		"limit":    limit,
+		"facets":   facets,
 	})
 }
 
+// searchOnly reports whether the listing filter narrows products by search alone
+func searchOnly(filter models.ProductFilter) bool {
+	return filter.CategoryID == nil &&
+		filter.MinPrice == nil &&
+		filter.MaxPrice == nil &&
+		len(filter.Types) == 0 &&
+		len(filter.TechStacks) == 0 &&
+		filter.MinRating == nil &&
+		!filter.OnSale &&
+		filter.Sort == ""
+}
+
 // GetProductByID godoc
 // @Summary Get product by ID
 // @Tags products
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param currency query string false "Display currency, defaults to the user's preference"
 // @Success 200 {object} utils.Response
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProductByID(c *gin.Context) {
@@ -117,25 +206,42 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 		return
 	}
+	if !displayCurrency(c, h.currencyService, product) || !localizeProducts(c, h.translationService, product) {
+		return
+	}
+
+	h.viewService.RecordView(product.ID, visitorKey(c))
 
 	utils.SuccessResponse(c, http.StatusOK, "Product retrieved successfully", product)
 }
 
 // GetProductBySlug godoc
 // @Summary Get product by slug
+// @Description Old slugs of renamed products answer with 301 and the current slug
 // @Tags products
 // @Produce json
 // @Param slug path string true "Product slug"
+// @Param currency query string false "Display currency, defaults to the user's preference"
 // @Success 200 {object} utils.Response
+// @Success 301 {object} utils.Response
 // @Router /products/slug/{slug} [get]
 func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
 	product, err := h.productService.GetProductBySlug(slug)
 	if err != nil {
+		if current, redirectErr := h.productService.ResolveSlugRedirect(slug); redirectErr == nil {
+			slugRedirect(c, slug, current)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 		return
 	}
+	if !displayCurrency(c, h.currencyService, product) || !localizeProducts(c, h.translationService, product) {
+		return
+	}
+
+	h.viewService.RecordView(product.ID, visitorKey(c))
 
 	utils.SuccessResponse(c, http.StatusOK, "Product retrieved successfully", product)
 }
@@ -152,9 +258,9 @@ func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
 // @Param stock formData int false "Product stock"
 // @Param category_id formData int false "Category ID"
 // @Param is_featured formData bool false "Is featured"
-// @Param is_active formData bool false "Is active"
 // @Param image formData file false "Product image"
 // @Success 200 {object} utils.Response
+// @Success 202 {object} utils.Response "Published product: changes stored as a revision awaiting review"
 // @Router /products/{id} [put]
 // @Security Bearer
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -172,11 +278,15 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	file, _ := c.FormFile("image")
 
-	product, err := h.productService.UpdateProduct(uint(id), req, file)
+	product, revision, err := h.productService.UpdateProduct(uint(id), req, file, middleware.GetUserID(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if revision != nil {
+		utils.SuccessResponse(c, http.StatusAccepted, "Changes submitted for review", revision)
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Product updated successfully", product)
 }
@@ -196,7 +306,7 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.productService.DeleteProduct(uint(id)); err != nil {
+	if err := h.productService.DeleteProduct(uint(id), middleware.GetUserID(c)); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -204,30 +314,12 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Product deleted successfully", nil)
 }
 
-// GetFeaturedProducts godoc
-// @Summary Get featured products
-// @Tags products
-// @Produce json
-// @Param limit query int false "Number of products" default(10)
-// @Success 200 {object} utils.Response
-// @Router /products/featured [get]
-func (h *ProductHandler) GetFeaturedProducts(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	products, err := h.productService.GetFeaturedProducts(limit)
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	utils.SuccessResponse(c, http.StatusOK, "Featured products retrieved successfully", products)
-}
-
 // GetProductsByCategory godoc
 // @Summary Get products by category
 // @Tags products
 // @Produce json
 // @Param category_id path int true "Category ID"
+// @Param include_subcategories query bool false "Also list products of the category's subcategories"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} utils.Response
@@ -242,11 +334,14 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, total, err := h.productService.GetProductsByCategory(uint(categoryID), page, limit)
+	products, total, err := h.productService.GetProductsByCategory(uint(categoryID), c.Query("include_subcategories") == "true", page, limit)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if !displayCurrencyList(c, h.currencyService, products) || !localizeProductList(c, h.translationService, products) {
+		return
+	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Products retrieved successfully", gin.H{
 		"products": products,
@@ -255,3 +350,128 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 		"limit":    limit,
 	})
 }
+
+// GetTechStacks godoc
+// @Summary Autocomplete tech stack tags
+// @Tags tech-stacks
+// @Produce json
+// @Param q query string false "Tag prefix"
+// @Param limit query int false "Max suggestions" default(10)
+// @Success 200 {object} utils.Response
+// @Router /tech-stacks [get]
+func (h *ProductHandler) GetTechStacks(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	tags, err := h.productService.SearchTechStacks(c.Query("q"), limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tech stacks retrieved successfully", tags)
+}
+
+// GetProductsByTechStack godoc
+// @Summary Get products using a technology
+// @Tags tech-stacks
+// @Produce json
+// @Param tag path string true "Tech stack tag"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param sort query string false "newest, price_asc, price_desc, popular, rating"
+// @Success 200 {object} utils.Response
+// @Router /tech-stacks/{tag}/products [get]
+func (h *ProductHandler) GetProductsByTechStack(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := models.ProductFilter{
+		TechStacks: []string{c.Param("tag")},
+		Sort:       c.Query("sort"),
+	}
+
+	if err := h.productService.ValidateFilter(&filter); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products, total, err := h.productService.GetAllProducts(page, limit, filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !displayCurrencyList(c, h.currencyService, products) || !localizeProductList(c, h.translationService, products) {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Products retrieved successfully", gin.H{
+		"tag":      c.Param("tag"),
+		"products": products,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// slugRedirect answers a request for an old slug with a 301 pointing at the current one
+func slugRedirect(c *gin.Context, oldSlug, currentSlug string) {
+	location := strings.TrimSuffix(c.Request.URL.Path, oldSlug) + currentSlug
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, utils.Response{
+		Success: true,
+		Message: "Slug has moved",
+		Data: gin.H{
+			"slug":     currentSlug,
+			"location": location,
+		},
+	})
+}
+
+// queryList reads a query parameter given either repeated or comma separated
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// queryFloat parses an optional numeric query parameter
+func queryFloat(c *gin.Context, key string) (*float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
+
+// queryInt parses an optional integer query parameter
+func queryInt(c *gin.Context, key string) (*int64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	val, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
+
+// visitorKey identifies a visitor for view deduplication: the user ID when
+// authenticated, otherwise a hash of the client IP and user agent.
+func visitorKey(c *gin.Context) string {
+	if userID := middleware.GetUserID(c); userID != 0 {
+		return fmt.Sprintf("u:%d", userID)
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize limits uploaded catalog files
+const maxImportSize = 20 << 20 // 20MB
+
+type ProductImportHandler struct {
+	importService services.ProductImportService
+}
+
+func NewProductImportHandler(importService services.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportProducts godoc
+// @Summary Bulk import products from CSV or JSON lines (Admin only)
+// @Description Rows are matched by slug: existing products are updated, new ones created as drafts. Nothing is written unless every row is valid.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or JSONL file"
+// @Param format query string false "csv or jsonl (defaults to the file extension)"
+// @Param dry_run query bool false "Only validate and return the report"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /admin/products/import [post]
+// @Security Bearer
+func (h *ProductImportHandler) ImportProducts(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Import file exceeds maximum size of 20MB")
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+		if format == "ndjson" {
+			format = "jsonl"
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.Query("dry_run") == "true"
+
+	report, err := h.importService.Import(file, format, dryRun, middleware.GetUserID(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if report.Failed > 0 {
+		c.JSON(http.StatusUnprocessableEntity, utils.Response{
+			Success: false,
+			Message: fmt.Sprintf("%d of %d rows failed validation", report.Failed, report.Total),
+			Data:    report,
+		})
+		return
+	}
+
+	message := "Products imported successfully"
+	if dryRun {
+		message = "Import file is valid"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, report)
+}
+
+// ExportProducts godoc
+// @Summary Export the full product catalog (Admin only)
+// @Tags admin
+// @Produce text/csv
+// @Param format query string false "csv or jsonl" default(csv)
+// @Success 200 {file} file
+// @Router /admin/products/export [get]
+// @Security Bearer
+func (h *ProductImportHandler) ExportProducts(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "jsonl" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "format must be csv or jsonl")
+		return
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	var write func(row models.ProductImportRow) error
+	var flush func()
+
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write(services.ProductCSVColumns); err != nil {
+			return
+		}
+		write = func(row models.ProductImportRow) error {
+			return writer.Write(services.ProductCSVRecord(row))
+		}
+		flush = writer.Flush
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(c.Writer)
+		write = func(row models.ProductImportRow) error {
+			return encoder.Encode(row)
+		}
+		flush = func() {}
+	}
+
+	c.Status(http.StatusOK)
+
+	// Rows are flushed per batch so large catalogs stream instead of buffering
+	written := 0
+	err := h.importService.Export(func(row models.ProductImportRow) error {
+		if err := write(row); err != nil {
+			return err
+		}
+		written++
+		if written%100 == 0 {
+			flush()
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	flush()
+	c.Writer.Flush()
+
+	if err != nil {
+		// Headers are already sent; all we can do is stop the stream
+		c.Error(err)
+	}
+}
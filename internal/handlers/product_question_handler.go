@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductQuestionHandler struct {
+	questionService services.ProductQuestionService
+}
+
+func NewProductQuestionHandler(questionService services.ProductQuestionService) *ProductQuestionHandler {
+	return &ProductQuestionHandler{
+		questionService: questionService,
+	}
+}
+
+// GetProductQuestions godoc
+// @Summary Get approved questions and answers for a product
+// @Tags questions
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param sort query string false "top or newest" default(top)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/questions [get]
+func (h *ProductQuestionHandler) GetProductQuestions(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	questions, total, err := h.questionService.GetProductQuestions(uint(productID), c.DefaultQuery("sort", "top"), page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Questions retrieved successfully", gin.H{
+		"questions": questions,
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// AskQuestion godoc
+// @Summary Ask a pre-sales question about a product
+// @Description Questions are listed once a moderator approves them or they get answered
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductQuestionRequest true "Question"
+// @Success 201 {object} utils.Response
+// @Router /products/{id}/questions [post]
+// @Security Bearer
+func (h *ProductQuestionHandler) AskQuestion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	question, err := h.questionService.AskQuestion(userID, uint(productID), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Question submitted for moderation", question)
+}
+
+// AnswerQuestion godoc
+// @Summary Answer a product question (product creator or admin)
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path int true "Question ID"
+// @Param request body models.ProductAnswerRequest true "Answer"
+// @Success 201 {object} utils.Response
+// @Router /questions/{id}/answers [post]
+// @Security Bearer
+func (h *ProductQuestionHandler) AnswerQuestion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	var req models.ProductAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	answer, err := h.questionService.AnswerQuestion(uint(questionID), userID, middleware.GetUserRole(c), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Answer posted successfully", answer)
+}
+
+// UpvoteQuestion godoc
+// @Summary Upvote a question
+// @Tags questions
+// @Produce json
+// @Param id path int true "Question ID"
+// @Success 200 {object} utils.Response
+// @Router /questions/{id}/vote [post]
+// @Security Bearer
+func (h *ProductQuestionHandler) UpvoteQuestion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	if err := h.questionService.Upvote(uint(questionID), userID); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Question upvoted", nil)
+}
+
+// RemoveUpvote godoc
+// @Summary Remove an upvote from a question
+// @Tags questions
+// @Produce json
+// @Param id path int true "Question ID"
+// @Success 200 {object} utils.Response
+// @Router /questions/{id}/vote [delete]
+// @Security Bearer
+func (h *ProductQuestionHandler) RemoveUpvote(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	if err := h.questionService.RemoveUpvote(uint(questionID), userID); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Upvote removed", nil)
+}
+
+// DeleteQuestion godoc
+// @Summary Delete your own question
+// @Tags questions
+// @Produce json
+// @Param id path int true "Question ID"
+// @Success 200 {object} utils.Response
+// @Router /questions/{id} [delete]
+// @Security Bearer
+func (h *ProductQuestionHandler) DeleteQuestion(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	if err := h.questionService.DeleteQuestion(uint(questionID), userID); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Question deleted successfully", nil)
+}
+
+// AdminGetQuestions godoc
+// @Summary Get questions for moderation (Admin only)
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, approved or rejected"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response
+// @Router /admin/questions [get]
+// @Security Bearer
+func (h *ProductQuestionHandler) AdminGetQuestions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	questions, total, err := h.questionService.AdminGetQuestions(c.Query("status"), page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Questions retrieved successfully", gin.H{
+		"questions": questions,
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// ApproveQuestion godoc
+// @Summary Approve a question so it is listed (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Question ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/questions/{id}/approve [post]
+// @Security Bearer
+func (h *ProductQuestionHandler) ApproveQuestion(c *gin.Context) {
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	question, err := h.questionService.Approve(uint(questionID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Question approved", question)
+}
+
+// RejectQuestion godoc
+// @Summary Reject a question (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Question ID"
+// @Param request body models.QuestionModerationRequest false "Reason"
+// @Success 200 {object} utils.Response
+// @Router /admin/questions/{id}/reject [post]
+// @Security Bearer
+func (h *ProductQuestionHandler) RejectQuestion(c *gin.Context) {
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	var req models.QuestionModerationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	question, err := h.questionService.Reject(uint(questionID), req.Reason)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Question rejected", question)
+}
+
+// AdminDeleteQuestion godoc
+// @Summary Delete a question and its answers (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Question ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/questions/{id} [delete]
+// @Security Bearer
+func (h *ProductQuestionHandler) AdminDeleteQuestion(c *gin.Context) {
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	if err := h.questionService.AdminDeleteQuestion(uint(questionID)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Question deleted successfully", nil)
+}
+
+// AdminDeleteAnswer godoc
+// @Summary Delete an answer (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Answer ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/answers/{id} [delete]
+// @Security Bearer
+func (h *ProductQuestionHandler) AdminDeleteAnswer(c *gin.Context) {
+	answerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	if err := h.questionService.AdminDeleteAnswer(uint(answerID)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Answer deleted successfully", nil)
+}
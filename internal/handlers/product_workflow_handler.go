@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductWorkflowHandler struct {
+	workflowService services.ProductWorkflowService
+}
+
+func NewProductWorkflowHandler(workflowService services.ProductWorkflowService) *ProductWorkflowHandler {
+	return &ProductWorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+// GetProducts godoc
+// @Summary Get products in any workflow state (Admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Statuses, comma separated (draft,in_review,scheduled,published,archived)"
+// @Param search query string false "Full-text search"
+// @Param category_id query int false "Filter by category"
+// @Success 200 {object} utils.Response
+// @Router /admin/products [get]
+// @Security Bearer
+func (h *ProductWorkflowHandler) GetProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := models.ProductFilter{
+		Search:   c.Query("search"),
+		Statuses: queryList(c, "status"),
+		Sort:     c.Query("sort"),
+	}
+
+	if catID := c.Query("category_id"); catID != "" {
+		id, err := strconv.ParseUint(catID, 10, 32)
+		if err == nil {
+			val := uint(id)
+			filter.CategoryID = &val
+		}
+	}
+
+	products, total, err := h.workflowService.GetProducts(page, limit, filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Products retrieved successfully", gin.H{
+		"products": products,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetProduct godoc
+// @Summary Get product with its workflow history (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id} [get]
+// @Security Bearer
+func (h *ProductWorkflowHandler) GetProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	product, history, err := h.workflowService.GetProduct(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Product retrieved successfully", gin.H{
+		"product": product,
+		"history": history,
+	})
+}
+
+// SubmitForReview godoc
+// @Summary Submit a draft product for review (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest false "Comment for the reviewer"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/submit [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) SubmitForReview(c *gin.Context) {
+	h.handleTransition(c, "Product submitted for review", func(id, actorID uint, req models.ProductWorkflowRequest) (*models.Product, error) {
+		return h.workflowService.SubmitForReview(id, actorID, req.Comment)
+	})
+}
+
+// Approve godoc
+// @Summary Approve a product in review (Admin only)
+// @Description Publishes the product immediately, or schedules it when publish_at is in the future
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest false "Reviewer comment and optional publish_at"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/approve [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) Approve(c *gin.Context) {
+	h.handleTransition(c, "Product approved", h.workflowService.Approve)
+}
+
+// Reject godoc
+// @Summary Request changes on a product in review (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest true "Reviewer comment"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/reject [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) Reject(c *gin.Context) {
+	h.handleTransition(c, "Changes requested", func(id, actorID uint, req models.ProductWorkflowRequest) (*models.Product, error) {
+		return h.workflowService.Reject(id, actorID, req.Comment)
+	})
+}
+
+// Archive godoc
+// @Summary Archive a product (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest false "Comment"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/archive [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) Archive(c *gin.Context) {
+	h.handleTransition(c, "Product archived", func(id, actorID uint, req models.ProductWorkflowRequest) (*models.Product, error) {
+		return h.workflowService.Archive(id, actorID, req.Comment)
+	})
+}
+
+// RevertToDraft godoc
+// @Summary Move a product back to draft (Admin only)
+// @Description Unpublishes, unschedules or restores an archived product as a draft
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest false "Comment"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/draft [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) RevertToDraft(c *gin.Context) {
+	h.handleTransition(c, "Product moved to draft", func(id, actorID uint, req models.ProductWorkflowRequest) (*models.Product, error) {
+		return h.workflowService.RevertToDraft(id, actorID, req.Comment)
+	})
+}
+
+// GetPendingRevisions godoc
+// @Summary List changes to live products waiting for review (Admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Router /admin/product-revisions [get]
+// @Security Bearer
+func (h *ProductWorkflowHandler) GetPendingRevisions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	revisions, total, err := h.workflowService.GetPendingRevisions(page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Revisions retrieved successfully", gin.H{
+		"revisions": revisions,
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// GetPendingRevision godoc
+// @Summary Get a live product's changes waiting for review (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/revision [get]
+// @Security Bearer
+func (h *ProductWorkflowHandler) GetPendingRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	revision, err := h.workflowService.GetPendingRevision(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Revision retrieved successfully", revision)
+}
+
+// ApproveRevision godoc
+// @Summary Approve a live product's pending changes (Admin only)
+// @Description The reviewer must be someone other than the author of the changes
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest false "Reviewer comment"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/revision/approve [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) ApproveRevision(c *gin.Context) {
+	h.handleTransition(c, "Changes approved", func(id, actorID uint, req models.ProductWorkflowRequest) (*models.Product, error) {
+		return h.workflowService.ApproveRevision(id, actorID, req.Comment)
+	})
+}
+
+// RejectRevision godoc
+// @Summary Reject a live product's pending changes (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body models.ProductWorkflowRequest true "Reviewer comment"
+// @Success 200 {object} utils.Response
+// @Router /admin/products/{id}/revision/reject [post]
+// @Security Bearer
+func (h *ProductWorkflowHandler) RejectRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductWorkflowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	revision, err := h.workflowService.RejectRevision(uint(id), middleware.GetUserID(c), req.Comment)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Changes rejected", revision)
+}
+
+// handleTransition parses the product ID and optional body shared by all workflow actions
+func (h *ProductWorkflowHandler) handleTransition(c *gin.Context, message string, action func(id, actorID uint, req models.ProductWorkflowRequest) (*models.Product, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductWorkflowRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	product, err := action(uint(id), middleware.GetUserID(c), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, product)
+}
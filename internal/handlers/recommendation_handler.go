@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendationService services.RecommendationService
+	currencyService       services.CurrencyService
+	translationService    services.TranslationService
+}
+
+func NewRecommendationHandler(recommendationService services.RecommendationService, currencyService services.CurrencyService, translationService services.TranslationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+		currencyService:       currencyService,
+		translationService:    translationService,
+	}
+}
+
+// GetRelatedProducts godoc
+// @Summary Get related products ("customers also bought", then similar products)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param limit query int false "Number of products" default(8)
+// @Param currency query string false "Display currency, defaults to the user's preference"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/related [get]
+func (h *RecommendationHandler) GetRelatedProducts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "8"))
+
+	products, err := h.recommendationService.GetRelatedProducts(uint(id), limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if !displayCurrencyList(c, h.currencyService, products) || !localizeProductList(c, h.translationService, products) {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Related products retrieved successfully", products)
+}
+
+// GetUserRecommendations godoc
+// @Summary Get personal product recommendations
+// @Description Based on the user's paid orders and wishlist, falling back to best sellers
+// @Tags user
+// @Produce json
+// @Param limit query int false "Number of products" default(10)
+// @Param currency query string false "Display currency, defaults to the user's preference"
+// @Success 200 {object} utils.Response
+// @Router /user/recommendations [get]
+// @Security Bearer
+func (h *RecommendationHandler) GetUserRecommendations(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	products, err := h.recommendationService.GetUserRecommendations(userID, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !displayCurrencyList(c, h.currencyService, products) || !localizeProductList(c, h.translationService, products) {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recommendations retrieved successfully", products)
+}
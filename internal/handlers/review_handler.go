@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"gin-quickstart/internal/middleware"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
 	"net/http"
@@ -191,7 +192,9 @@ func (h *ReviewHandler) AdminDeleteReview(c *gin.Context) {
 		return
 	}
 
-	err = h.reviewService.AdminDeleteReview(uint(reviewID))
+	adminID := middleware.GetUserID(c)
+
+	err = h.reviewService.AdminDeleteReview(uint(reviewID), adminID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
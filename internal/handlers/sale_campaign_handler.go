@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SaleCampaignHandler struct {
+	campaignService services.SaleCampaignService
+}
+
+func NewSaleCampaignHandler(campaignService services.SaleCampaignService) *SaleCampaignHandler {
+	return &SaleCampaignHandler{
+		campaignService: campaignService,
+	}
+}
+
+// GetActiveSales godoc
+// @Summary Get sale campaigns running now
+// @Tags sales
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /sales [get]
+func (h *SaleCampaignHandler) GetActiveSales(c *gin.Context) {
+	campaigns, err := h.campaignService.GetActiveCampaigns()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sales retrieved successfully", campaigns)
+}
+
+// GetPriceHistory godoc
+// @Summary Get a product's price history and lowest price in the last 30 days
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/price-history [get]
+func (h *SaleCampaignHandler) GetPriceHistory(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	history, err := h.campaignService.GetPriceHistory(uint(productID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Price history retrieved successfully", history)
+}
+
+// GetCampaigns godoc
+// @Summary Get sale campaigns (Admin only)
+// @Tags admin
+// @Produce json
+// @Param state query string false "active, upcoming or ended"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response
+// @Router /admin/campaigns [get]
+// @Security Bearer
+func (h *SaleCampaignHandler) GetCampaigns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	campaigns, total, err := h.campaignService.GetCampaigns(page, limit, c.Query("state"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Campaigns retrieved successfully", gin.H{
+		"campaigns": campaigns,
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+// GetCampaign godoc
+// @Summary Get a sale campaign (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/campaigns/{id} [get]
+// @Security Bearer
+func (h *SaleCampaignHandler) GetCampaign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid campaign ID")
+		return
+	}
+
+	campaign, err := h.campaignService.GetCampaign(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Campaign retrieved successfully", campaign)
+}
+
+// CreateCampaign godoc
+// @Summary Create a sale campaign (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param campaign body models.SaleCampaignRequest true "Campaign"
+// @Success 201 {object} utils.Response
+// @Router /admin/campaigns [post]
+// @Security Bearer
+func (h *SaleCampaignHandler) CreateCampaign(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+
+	var req models.SaleCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaign, err := h.campaignService.CreateCampaign(req, adminID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Campaign created successfully", campaign)
+}
+
+// UpdateCampaign godoc
+// @Summary Update a sale campaign (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Param campaign body models.SaleCampaignRequest true "Campaign"
+// @Success 200 {object} utils.Response
+// @Router /admin/campaigns/{id} [put]
+// @Security Bearer
+func (h *SaleCampaignHandler) UpdateCampaign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid campaign ID")
+		return
+	}
+
+	var req models.SaleCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaign, err := h.campaignService.UpdateCampaign(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Campaign updated successfully", campaign)
+}
+
+// DeleteCampaign godoc
+// @Summary Delete a sale campaign (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/campaigns/{id} [delete]
+// @Security Bearer
+func (h *SaleCampaignHandler) DeleteCampaign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid campaign ID")
+		return
+	}
+
+	if err := h.campaignService.DeleteCampaign(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Campaign deleted successfully", nil)
+}
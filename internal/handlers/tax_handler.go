@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxHandler struct {
+	taxService services.TaxService
+}
+
+func NewTaxHandler(taxService services.TaxService) *TaxHandler {
+	return &TaxHandler{
+		taxService: taxService,
+	}
+}
+
+// GetRules godoc
+// @Summary Get tax rules (Admin only)
+// @Tags admin
+// @Produce json
+// @Param country query string false "Country code, e.g. ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/tax-rules [get]
+// @Security Bearer
+func (h *TaxHandler) GetRules(c *gin.Context) {
+	rules, err := h.taxService.GetRules(c.Query("country"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax rules retrieved successfully", rules)
+}
+
+// GetRule godoc
+// @Summary Get a tax rule (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/tax-rules/{id} [get]
+// @Security Bearer
+func (h *TaxHandler) GetRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tax rule ID")
+		return
+	}
+
+	rule, err := h.taxService.GetRule(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax rule retrieved successfully", rule)
+}
+
+// CreateRule godoc
+// @Summary Create a tax rule (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param rule body models.TaxRuleRequest true "Tax rule"
+// @Success 201 {object} utils.Response
+// @Router /admin/tax-rules [post]
+// @Security Bearer
+func (h *TaxHandler) CreateRule(c *gin.Context) {
+	adminID := middleware.GetUserID(c)
+
+	var req models.TaxRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.taxService.CreateRule(req, adminID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tax rule created successfully", rule)
+}
+
+// UpdateRule godoc
+// @Summary Update a tax rule (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Param rule body models.TaxRuleRequest true "Tax rule"
+// @Success 200 {object} utils.Response
+// @Router /admin/tax-rules/{id} [put]
+// @Security Bearer
+func (h *TaxHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tax rule ID")
+		return
+	}
+
+	var req models.TaxRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, err := h.taxService.UpdateRule(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax rule updated successfully", rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete a tax rule (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Tax rule ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/tax-rules/{id} [delete]
+// @Security Bearer
+func (h *TaxHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tax rule ID")
+		return
+	}
+
+	if err := h.taxService.DeleteRule(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax rule deleted successfully", nil)
+}
+
+// GetTaxReport godoc
+// @Summary Get the tax collected on paid orders in a period, amounts in the base currency (Admin only)
+// @Tags admin
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)" default(first day of this month)
+// @Param end_date query string false "End date (YYYY-MM-DD), inclusive" default(today)
+// @Success 200 {object} utils.Response
+// @Router /admin/tax-report [get]
+// @Security Bearer
+func (h *TaxHandler) GetTaxReport(c *gin.Context) {
+	today := time.Now().Truncate(24 * time.Hour)
+	startDate := today.AddDate(0, 0, 1-today.Day())
+	endDate := today
+
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid start_date, expected YYYY-MM-DD")
+			return
+		}
+		startDate = parsed
+	}
+
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid end_date, expected YYYY-MM-DD")
+			return
+		}
+		endDate = parsed
+	}
+
+	// The end date counts as a whole day
+	report, err := h.taxService.GetTaxReport(startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var taxable, tax int64
+	for _, line := range report {
+		taxable += line.TaxableAmount
+		tax += line.TaxAmount
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax report retrieved successfully", gin.H{
+		"start_date":     startDate.Format("2006-01-02"),
+		"end_date":       endDate.Format("2006-01-02"),
+		"currency":       models.BaseCurrency,
+		"taxable_amount": taxable,
+		"tax_amount":     tax,
+		"lines":          report,
+	})
+}
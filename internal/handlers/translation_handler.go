@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	translationService services.TranslationService
+}
+
+func NewTranslationHandler(translationService services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+	}
+}
+
+// GetLocales godoc
+// @Summary Get the supported content locales
+// @Tags locales
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /locales [get]
+func (h *TranslationHandler) GetLocales(c *gin.Context) {
+	defaultLocale, supported := h.translationService.Locales()
+
+	utils.SuccessResponse(c, http.StatusOK, "Locales retrieved successfully", gin.H{
+		"default":   defaultLocale,
+		"supported": supported,
+		"current":   middleware.GetLocale(c),
+	})
+}
+
+// GetProductTranslations godoc
+// @Summary Get a product's translations (Admin only)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/translations [get]
+// @Security Bearer
+func (h *TranslationHandler) GetProductTranslations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	translations, err := h.translationService.GetProductTranslations(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Translations retrieved successfully", translations)
+}
+
+// SetProductTranslation godoc
+// @Summary Create or replace a product's translation for a locale (Admin only)
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param locale path string true "Locale, e.g. en"
+// @Param request body models.ProductTranslationRequest true "Translated content"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/translations/{locale} [put]
+// @Security Bearer
+func (h *TranslationHandler) SetProductTranslation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	translation, err := h.translationService.SetProductTranslation(uint(id), c.Param("locale"), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Translation saved successfully", translation)
+}
+
+// DeleteProductTranslation godoc
+// @Summary Delete a product's translation for a locale (Admin only)
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param locale path string true "Locale, e.g. en"
+// @Success 200 {object} utils.Response
+// @Router /products/{id}/translations/{locale} [delete]
+// @Security Bearer
+func (h *TranslationHandler) DeleteProductTranslation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	if err := h.translationService.DeleteProductTranslation(uint(id), c.Param("locale")); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Translation deleted successfully", nil)
+}
+
+// GetCategoryTranslations godoc
+// @Summary Get a category's translations (Admin only)
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} utils.Response
+// @Router /categories/{id}/translations [get]
+// @Security Bearer
+func (h *TranslationHandler) GetCategoryTranslations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	translations, err := h.translationService.GetCategoryTranslations(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Translations retrieved successfully", translations)
+}
+
+// SetCategoryTranslation godoc
+// @Summary Create or replace a category's translation for a locale (Admin only)
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param locale path string true "Locale, e.g. en"
+// @Param request body models.CategoryTranslationRequest true "Translated content"
+// @Success 200 {object} utils.Response
+// @Router /categories/{id}/translations/{locale} [put]
+// @Security Bearer
+func (h *TranslationHandler) SetCategoryTranslation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	var req models.CategoryTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	translation, err := h.translationService.SetCategoryTranslation(uint(id), c.Param("locale"), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Translation saved successfully", translation)
+}
+
+// DeleteCategoryTranslation godoc
+// @Summary Delete a category's translation for a locale (Admin only)
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param locale path string true "Locale, e.g. en"
+// @Success 200 {object} utils.Response
+// @Router /categories/{id}/translations/{locale} [delete]
+// @Security Bearer
+func (h *TranslationHandler) DeleteCategoryTranslation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	if err := h.translationService.DeleteCategoryTranslation(uint(id), c.Param("locale")); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Translation deleted successfully", nil)
+}
+
+// localizeProducts shows the products in the request's locale. It answers the
+// request with an error and returns false when the translations cannot be loaded.
+func localizeProducts(c *gin.Context, translationService services.TranslationService, products ...*models.Product) bool {
+	if err := translationService.LocalizeProducts(middleware.GetLocale(c), products...); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	return true
+}
+
+// localizeProductList is localizeProducts for a slice of products
+func localizeProductList(c *gin.Context, translationService services.TranslationService, products []models.Product) bool {
+	pointers := make([]*models.Product, len(products))
+	for i := range products {
+		pointers[i] = &products[i]
+	}
+	return localizeProducts(c, translationService, pointers...)
+}
+
+// localizeCategories is localizeProducts for categories
+func localizeCategories(c *gin.Context, translationService services.TranslationService, categories ...*models.Category) bool {
+	if err := translationService.LocalizeCategories(middleware.GetLocale(c), categories...); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	return true
+}
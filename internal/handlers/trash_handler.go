@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/services"
+	"gin-quickstart/pkg/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService services.TrashService
+}
+
+func NewTrashHandler(trashService services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetTrash godoc
+// @Summary List deleted items of one type (Admin only)
+// @Tags admin
+// @Produce json
+// @Param type path string true "Entity type (products, categories, reviews, users)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response
+// @Router /admin/trash/{type} [get]
+// @Security Bearer
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	items, total, err := h.trashService.GetTrash(c.Param("type"), page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trash retrieved successfully", gin.H{
+		"items": items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// RestoreItem godoc
+// @Summary Restore a deleted item (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param type path string true "Entity type (products, categories, reviews, users)"
+// @Param id path int true "Item ID"
+// @Param request body models.TrashRestoreRequest false "New email for users whose address was taken"
+// @Success 200 {object} utils.Response
+// @Router /admin/trash/{type}/{id}/restore [post]
+// @Security Bearer
+func (h *TrashHandler) RestoreItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	var req models.TrashRestoreRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	item, err := h.trashService.Restore(c.Param("type"), uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Item restored successfully", item)
+}
+
+// PurgeItem godoc
+// @Summary Permanently delete an item from the trash (Admin only)
+// @Tags admin
+// @Produce json
+// @Param type path string true "Entity type (products, categories, reviews, users)"
+// @Param id path int true "Item ID"
+// @Success 200 {object} utils.Response
+// @Router /admin/trash/{type}/{id} [delete]
+// @Security Bearer
+func (h *TrashHandler) PurgeItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	if err := h.trashService.Purge(c.Param("type"), uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Item permanently deleted", nil)
+}
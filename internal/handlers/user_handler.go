@@ -65,6 +65,33 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully", user)
 }
 
+// UpdateBilling godoc
+// @Summary Update billing country, region and business tax details
+// @Security Bearer
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param billing body models.BillingDetailsRequest true "Billing details"
+// @Success 200 {object} utils.Response
+// @Router /user/billing [put]
+func (h *UserHandler) UpdateBilling(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	var req models.BillingDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	user, err := h.service.UpdateBilling(userID, &req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Billing details updated successfully", user)
+}
+
 // ChangePassword godoc
 // @Summary Change password
 // @Security Bearer
@@ -101,7 +128,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 func (h *UserHandler) DeleteAccount(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
-	if err := h.service.DeleteUser(userID); err != nil {
+	if err := h.service.DeleteUser(userID, userID); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -182,6 +209,38 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "User updated successfully", user)
 }
 
+// Admin: VerifyTaxID godoc
+// @Summary Mark a user's tax ID as verified for their billing country (Admin)
+// @Security Bearer
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param verification body models.TaxIDVerificationRequest true "Verification"
+// @Success 200 {object} utils.Response
+// @Router /admin/users/{id}/tax-id [put]
+func (h *UserHandler) VerifyTaxID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid user ID")
+		return
+	}
+
+	var req models.TaxIDVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	user, err := h.service.SetTaxIDVerified(uint(id), *req.Verified)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax ID verification updated successfully", user)
+}
+
 // Admin: DeleteUser godoc
 // @Summary Delete user (Admin)
 // @Security Bearer
@@ -196,7 +255,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteUser(uint(id)); err != nil {
+	if err := h.service.DeleteUser(uint(id), middleware.GetUserID(c)); err != nil {
 		utils.NotFoundResponse(c, err.Error())
 		return
 	}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"gin-quickstart/internal/middleware"
+	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/services"
 	"gin-quickstart/pkg/utils"
 	"net/http"
@@ -11,12 +12,16 @@ import (
 )
 
 type WishlistHandler struct {
-	wishlistService services.WishlistService
+	wishlistService    services.WishlistService
+	currencyService    services.CurrencyService
+	translationService services.TranslationService
 }
 
-func NewWishlistHandler(wishlistService services.WishlistService) *WishlistHandler {
+func NewWishlistHandler(wishlistService services.WishlistService, currencyService services.CurrencyService, translationService services.TranslationService) *WishlistHandler {
 	return &WishlistHandler{
-		wishlistService: wishlistService,
+		wishlistService:    wishlistService,
+		currencyService:    currencyService,
+		translationService: translationService,
 	}
 }
 
@@ -54,6 +59,7 @@ func (h *WishlistHandler) AddToWishlist(c *gin.Context) {
 // @Summary Get user's wishlist
 // @Tags wishlist
 // @Produce json
+// @Param currency query string false "Display currency, defaults to the user's preference"
 // @Success 200 {object} utils.Response
 // @Router /wishlist [get]
 // @Security Bearer
@@ -66,6 +72,14 @@ func (h *WishlistHandler) GetUserWishlist(c *gin.Context) {
 		return
 	}
 
+	products := make([]*models.Product, len(wishlists))
+	for i := range wishlists {
+		products[i] = wishlists[i].Product
+	}
+	if !displayCurrency(c, h.currencyService, products...) || !localizeProducts(c, h.translationService, products...) {
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Wishlist retrieved successfully", wishlists)
 }
 
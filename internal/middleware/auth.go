@@ -41,6 +41,37 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware sets the user context when a valid bearer token is
+// present but lets anonymous requests through.
+func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := utils.ValidateToken(parts[1], cfg.JWTSecret); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("user_email", claims.Email)
+				c.Set("user_role", claims.Role)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// GuestOrAuthMiddleware lets requests without an Authorization header through
+// as guests, but rejects a header that is malformed, invalid or expired like
+// AuthMiddleware does, so a signed-in user is never silently served as a guest.
+func GuestOrAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	auth := AuthMiddleware(cfg)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
+
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("user_role")
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"gin-quickstart/internal/config"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale picks the content locale from ?lang= or else the Accept-Language
+// header, falling back to the default locale, and echoes it in Content-Language.
+func Locale(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := NegotiateLocale(c.Query("lang"), c.GetHeader("Accept-Language"), cfg.SupportedLocales, cfg.DefaultLocale)
+
+		c.Set("locale", locale)
+		c.Header("Content-Language", locale)
+
+		c.Next()
+	}
+}
+
+func GetLocale(c *gin.Context) string {
+	locale, exists := c.Get("locale")
+	if !exists {
+		return ""
+	}
+	return locale.(string)
+}
+
+// NegotiateLocale returns the first supported locale among lang and the
+// Accept-Language entries by preference. A regional tag such as en-US also
+// matches its language, en.
+func NegotiateLocale(lang, acceptLanguage string, supported []string, fallback string) string {
+	candidates := []string{}
+	if lang != "" {
+		candidates = append(candidates, lang)
+	}
+	candidates = append(candidates, parseAcceptLanguage(acceptLanguage)...)
+
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(candidate), "_", "-"))
+		base, _, _ := strings.Cut(candidate, "-")
+		for _, locale := range supported {
+			if locale == candidate {
+				return locale
+			}
+		}
+		for _, locale := range supported {
+			if locale == base {
+				return locale
+			}
+		}
+	}
+
+	return fallback
+}
+
+// parseAcceptLanguage returns the language tags of the header, most preferred first
+func parseAcceptLanguage(header string) []string {
+	type tag struct {
+		name    string
+		quality float64
+	}
+
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if name == "" || name == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = value
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, tag{name: name, quality: quality})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.name
+	}
+	return names
+}
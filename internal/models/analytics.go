@@ -21,14 +21,14 @@ type AnalyticsResponse struct {
 }
 
 type DashboardStats struct {
-	TotalUsers      int     `json:"total_users"`
-	ActiveUsers     int     `json:"active_users"`
-	TotalProducts   int     `json:"total_products"`
-	TotalOrders     int     `json:"total_orders"`
-	TotalRevenue    float64 `json:"total_revenue"`
-	PendingOrders   int     `json:"pending_orders"`
-	CompletedOrders int     `json:"completed_orders"`
-	TotalDownloads  int     `json:"total_downloads"`
-	TodayVisitors   int     `json:"today_visitors"`
-	MonthlyRevenue  float64 `json:"monthly_revenue"`
+	TotalUsers      int   `json:"total_users"`
+	ActiveUsers     int   `json:"active_users"`
+	TotalProducts   int   `json:"total_products"`
+	TotalOrders     int   `json:"total_orders"`
+	TotalRevenue    int64 `json:"total_revenue"`
+	PendingOrders   int   `json:"pending_orders"`
+	CompletedOrders int   `json:"completed_orders"`
+	TotalDownloads  int   `json:"total_downloads"`
+	TodayVisitors   int   `json:"today_visitors"`
+	MonthlyRevenue  int64 `json:"monthly_revenue"`
 }
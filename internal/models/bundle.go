@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Bundle sells several products together at its own price. Buying a bundle
+// grants download access to every product in it.
+type Bundle struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Title         string         `gorm:"size:255;not null" json:"title"`
+	Slug          string         `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	Description   string         `gorm:"type:text" json:"description"`
+	Currency      string         `gorm:"size:3;not null;default:'IDR'" json:"currency"` // Shared by all bundled products
+	Price         int64          `gorm:"not null" json:"price"`                         // Minor units of Currency
+	PreviewImages string         `gorm:"type:jsonb;default:'[]'" json:"preview_images"` // JSON array
+	IsActive      bool           `gorm:"default:true" json:"is_active"`
+	Products      []Product      `gorm:"many2many:bundle_items;" json:"products,omitempty"`
+	OriginalPrice int64          `gorm:"-" json:"original_price"` // Sum of the products' own prices
+	CreatedBy     uint           `json:"created_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type BundleRequest struct {
+	Title       string `json:"title" form:"title"`
+	Description string `json:"description" form:"description"`
+	Price       int64  `json:"price" form:"price" binding:"min=0"`
+	ProductIDs  []uint `json:"product_ids" form:"product_ids"`
+	IsActive    *bool  `json:"is_active" form:"is_active"`
+}
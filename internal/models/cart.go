@@ -7,25 +7,59 @@ import (
 )
 
 type Cart struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	UserID    uint           `json:"user_id"`
-	User      *User          `json:"user,omitempty"`
-	ProductID uint           `json:"product_id"`
-	Product   *Product       `json:"product,omitempty"`
-	Quantity  int            `gorm:"default:1" json:"quantity"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	UserID        *uint          `gorm:"index" json:"user_id,omitempty"` // Set for signed in users, GuestCartID otherwise
+	User          *User          `json:"user,omitempty"`
+	GuestCartID   *string        `gorm:"size:36;index" json:"-"`
+	ProductID     uint           `json:"product_id"`
+	Product       *Product       `json:"product,omitempty"`
+	LicenseTierID *uint          `json:"license_tier_id,omitempty"`
+	LicenseTier   *LicenseTier   `json:"license_tier,omitempty"`
+	Quantity      int            `gorm:"default:1" json:"quantity"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// GuestCart is the cart of a visitor who has not signed in, identified by a
+// signed cart token. It expires once the visitor has been inactive for a while.
+type GuestCart struct {
+	ID        string    `gorm:"size:36;primaryKey" json:"id"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CartOwner identifies whose cart is used: a user's, or a guest cart when UserID is 0
+type CartOwner struct {
+	UserID      uint
+	GuestCartID string
 }
 
 type CartAddRequest struct {
-	ProductID uint `json:"product_id" binding:"required"`
-	Quantity  int  `json:"quantity" binding:"min=1"`
+	ProductID     uint  `json:"product_id" binding:"required"`
+	LicenseTierID *uint `json:"license_tier_id"`
+	Quantity      int   `json:"quantity" binding:"min=1"`
 }
 
 type CartResponse struct {
-	ID        uint            `json:"id"`
-	Product   ProductResponse `json:"product"`
-	Quantity  int             `json:"quantity"`
-	CreatedAt time.Time       `json:"created_at"`
+	ID          uint            `json:"id"`
+	Product     ProductResponse `json:"product"`
+	LicenseTier *LicenseTier    `json:"license_tier,omitempty"`
+	Quantity    int             `json:"quantity"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// CartSummary is the cart with its total expressed in a single currency and
+// the tax it would be charged. With a coupon it also previews the discount;
+// the coupon is checked again at checkout.
+type CartSummary struct {
+	Items       []Cart `json:"items"`
+	Currency    string `json:"currency"`
+	Total       int64  `json:"total"`
+	CouponCode  string `json:"coupon_code,omitempty"`
+	CouponError string `json:"coupon_error,omitempty"`
+	Discount    int64  `json:"discount"`
+	Tax         int64  `json:"tax"` // Included in FinalTotal
+	FinalTotal  int64  `json:"final_total"`
 }
@@ -7,20 +7,23 @@ import (
 )
 
 type Category struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"size:100;not null" json:"name"`
-	Slug        string         `gorm:"size:100;uniqueIndex;not null" json:"slug"`
-	Description string         `gorm:"type:text" json:"description"`
-	Icon        string         `gorm:"size:255" json:"icon,omitempty"`
-	ParentID    *uint          `json:"parent_id,omitempty"`
-	Parent      *Category      `json:"parent,omitempty"`
-	Children    []Category     `gorm:"foreignKey:ParentID" json:"children,omitempty"`
-	Order       int            `gorm:"default:0" json:"order"`
-	IsActive    bool           `gorm:"default:true" json:"is_active"`
-	Products    []Product      `json:"products,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Name              string         `gorm:"size:100;not null" json:"name"`
+	Slug              string         `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+	Description       string         `gorm:"type:text" json:"description"`
+	Icon              string         `gorm:"size:255" json:"icon,omitempty"`
+	ParentID          *uint          `json:"parent_id,omitempty"`
+	Parent            *Category      `json:"parent,omitempty"`
+	Children          []Category     `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Order             int            `gorm:"default:0" json:"order"`
+	IsActive          bool           `gorm:"default:true" json:"is_active"`
+	ProductCount      int            `gorm:"not null;default:0" json:"product_count"` // Published products directly in it, kept by a trigger
+	TotalProductCount *int           `gorm:"-" json:"total_product_count,omitempty"`  // ProductCount including descendants
+	Products          []Product      `json:"products,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedBy         *uint          `gorm:"index" json:"-"` // Who moved the row to the trash
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type CategoryRequest struct {
@@ -28,10 +31,55 @@ type CategoryRequest struct {
 	Description string `json:"description"`
 	Icon        string `json:"icon"`
 	ParentID    *uint  `json:"parent_id"`
-	Order       int    `json:"order"`
+	Order       *int   `json:"order"` // Defaults to after the last sibling
 	IsActive    *bool  `json:"is_active"`
 }
 
+type CategoryUpdateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// CategoryMoveRequest moves a category and its subtree under another parent,
+// or to the top level when ParentID is null
+type CategoryMoveRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+// CategoryReorderRequest sets the order of all children of a parent, or of the
+// top-level categories when ParentID is null
+type CategoryReorderRequest struct {
+	ParentID *uint  `json:"parent_id"`
+	IDs      []uint `json:"ids" binding:"required,min=1"`
+}
+
+// CategoryDeleteConflict lists what still belongs to a category that was not
+// deleted because no reassignment target was given
+type CategoryDeleteConflict struct {
+	ProductCount  int64             `json:"product_count"`
+	Products      []CategoryProduct `json:"products"` // First CategoryConflictLimit products
+	Subcategories []Category        `json:"subcategories"`
+}
+
+// CategoryConflictLimit caps the products listed in a CategoryDeleteConflict
+const CategoryConflictLimit = 100
+
+type CategoryProduct struct {
+	ID     uint   `json:"id"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Status string `json:"status"`
+}
+
+// CategoryBreadcrumb is one step of the path from the top level to a category
+type CategoryBreadcrumb struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
 type CategoryResponse struct {
 	ID          uint               `json:"id"`
 	Name        string             `json:"name"`
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Coupon is a promo code buyers enter at checkout. Like sale campaigns it
+// discounts by a percentage or a fixed amount, limited to a scope.
+type Coupon struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Code              string         `gorm:"size:50;uniqueIndex;not null" json:"code"` // Stored upper case
+	Description       string         `gorm:"type:text" json:"description,omitempty"`
+	DiscountType      string         `gorm:"size:20;not null" json:"discount_type"`      // percentage, fixed
+	DiscountValue     int64          `gorm:"not null" json:"discount_value"`             // Whole percent, or minor units of Currency
+	Currency          string         `gorm:"size:3;not null" json:"currency"`            // Currency of fixed discounts and MinOrderAmount
+	MinOrderAmount    int64          `gorm:"not null;default:0" json:"min_order_amount"` // Eligible subtotal needed, in minor units of Currency
+	Scope             string         `gorm:"size:20;not null" json:"scope"`              // store, category, product
+	UsageLimit        *int           `json:"usage_limit"`                                // Redemptions across all users, nil for unlimited
+	PerUserLimit      *int           `json:"per_user_limit"`                             // Redemptions per user, nil for unlimited
+	UsedCount         int            `gorm:"not null;default:0" json:"used_count"`       // Redemptions of orders that were not cancelled
+	FirstPurchaseOnly bool           `gorm:"not null;default:false" json:"first_purchase_only"`
+	StartsAt          *time.Time     `json:"starts_at"`
+	EndsAt            *time.Time     `json:"ends_at"`
+	IsActive          bool           `gorm:"not null" json:"is_active"`
+	Products          []Product      `gorm:"many2many:coupon_products" json:"products,omitempty"`
+	Categories        []Category     `gorm:"many2many:coupon_categories" json:"categories,omitempty"`
+	CreatedBy         uint           `json:"created_by"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type CouponRequest struct {
+	Code              string     `json:"code" binding:"required,max=50"`
+	Description       string     `json:"description"`
+	DiscountType      string     `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	DiscountValue     int64      `json:"discount_value" binding:"required,gt=0"`
+	Currency          string     `json:"currency"` // defaults to BaseCurrency
+	MinOrderAmount    int64      `json:"min_order_amount" binding:"min=0"`
+	Scope             string     `json:"scope" binding:"required,oneof=store category product"`
+	ProductIDs        []uint     `json:"product_ids"`
+	CategoryIDs       []uint     `json:"category_ids"`
+	UsageLimit        *int       `json:"usage_limit" binding:"omitempty,gt=0"`
+	PerUserLimit      *int       `json:"per_user_limit" binding:"omitempty,gt=0"`
+	FirstPurchaseOnly bool       `json:"first_purchase_only"`
+	StartsAt          *time.Time `json:"starts_at"`
+	EndsAt            *time.Time `json:"ends_at"`
+	IsActive          *bool      `json:"is_active"`
+}
+
+// CouponRedemption records a coupon used on an order. It is removed again when
+// the order is cancelled or its payment rejected, freeing the use.
+type CouponRedemption struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	CouponID       uint      `gorm:"index;not null" json:"coupon_id"`
+	Coupon         *Coupon   `json:"coupon,omitempty"`
+	UserID         uint      `gorm:"index;not null" json:"user_id"`
+	User           *User     `json:"user,omitempty"`
+	OrderID        uint      `gorm:"uniqueIndex;not null" json:"order_id"`
+	Order          *Order    `json:"order,omitempty"`
+	DiscountAmount int64     `gorm:"not null" json:"discount_amount"` // Minor units of Currency
+	Currency       string    `gorm:"size:3;not null" json:"currency"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CouponUsage summarizes a coupon's redemptions, with amounts in BaseCurrency
+type CouponUsage struct {
+	CouponID        uint   `json:"coupon_id"`
+	Code            string `json:"code"`
+	UsageLimit      *int   `json:"usage_limit"`
+	Redemptions     int64  `json:"redemptions"`
+	PaidRedemptions int64  `json:"paid_redemptions"`
+	UniqueUsers     int64  `json:"unique_users"`
+	TotalDiscount   int64  `json:"total_discount"`
+	PaidRevenue     int64  `json:"paid_revenue"` // Net of tax, like the analytics revenue
+	Currency        string `json:"currency"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BaseCurrency is the store's accounting currency. Exchange rates are quoted
+// against it and revenue reports are expressed in it.
+const BaseCurrency = "IDR"
+
+// Currencies lists the supported ISO 4217 codes with the number of minor unit
+// digits used to store their amounts. All money fields hold integer minor units;
+// rupiah is priced in whole units, so IDR has no minor digits.
+var Currencies = map[string]int{
+	"IDR": 0,
+	"USD": 2,
+	"EUR": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"AUD": 2,
+	"JPY": 0,
+}
+
+// ExchangeRate is the value of one unit of Currency in BaseCurrency from
+// EffectiveAt until the next rate for the same currency takes over.
+type ExchangeRate struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Currency    string         `gorm:"size:3;not null;index:idx_exchange_rate_currency_time" json:"currency"`
+	Rate        float64        `gorm:"type:numeric(20,10);not null" json:"rate"`
+	EffectiveAt time.Time      `gorm:"not null;index:idx_exchange_rate_currency_time" json:"effective_at"`
+	CreatedBy   uint           `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type ExchangeRateRequest struct {
+	Currency    string     `json:"currency" binding:"required,len=3"`
+	Rate        float64    `json:"rate" binding:"required,gt=0"`
+	EffectiveAt *time.Time `json:"effective_at"` // defaults to now
+}
+
+// PriceDisplay is a product's price converted into the currency the buyer asked for
+type PriceDisplay struct {
+	Currency      string  `json:"currency"`
+	Price         int64   `json:"price"`
+	DiscountPrice *int64  `json:"discount_price,omitempty"`
+	SalePrice     *int64  `json:"sale_price,omitempty"`
+	ExchangeRate  float64 `json:"exchange_rate"`
+}
+
+// CurrencyInfo describes a supported currency and its current rate to BaseCurrency
+type CurrencyInfo struct {
+	Code        string     `json:"code"`
+	MinorUnits  int        `json:"minor_units"`
+	IsBase      bool       `json:"is_base"`
+	Rate        *float64   `json:"rate"` // nil when no rate is in effect yet
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
+}
@@ -15,14 +15,15 @@ type CustomOrder struct {
 	Title        string         `gorm:"size:255;not null" json:"title"`
 	Description  string         `gorm:"type:text;not null" json:"description"`
 	Requirements string         `gorm:"type:text" json:"requirements"`
-	BudgetMin    float64        `json:"budget_min"`
-	BudgetMax    float64        `json:"budget_max"`
+	Currency     string         `gorm:"size:3;not null;default:'IDR'" json:"currency"`
+	BudgetMin    int64          `json:"budget_min"` // Minor units of Currency
+	BudgetMax    int64          `json:"budget_max"`
 	Deadline     *time.Time     `json:"deadline,omitempty"`
 	Status       string         `gorm:"size:30;not null" json:"status"` // submitted, under_review, in_progress, completed, cancelled
 	AdminNotes   string         `gorm:"type:text" json:"admin_notes,omitempty"`
 	Attachments  string         `gorm:"type:jsonb" json:"attachments"` // JSON array
-	QuoteAmount  *float64       `json:"quote_amount,omitempty"`
-	AgreedAmount *float64       `json:"agreed_amount,omitempty"`
+	QuoteAmount  *int64         `json:"quote_amount,omitempty"`
+	AgreedAmount *int64         `json:"agreed_amount,omitempty"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -32,8 +33,9 @@ type CustomOrderCreateRequest struct {
 	Title        string     `json:"title" binding:"required"`
 	Description  string     `json:"description" binding:"required"`
 	Requirements string     `json:"requirements"`
-	BudgetMin    float64    `json:"budget_min" binding:"required,min=0"`
-	BudgetMax    float64    `json:"budget_max" binding:"required,min=0"`
+	Currency     string     `json:"currency"`
+	BudgetMin    int64      `json:"budget_min" binding:"required,min=0"`
+	BudgetMax    int64      `json:"budget_max" binding:"required,min=0"`
 	Deadline     *time.Time `json:"deadline,omitempty"`
 }
 
@@ -41,16 +43,16 @@ type CustomOrderUpdateRequest struct {
 	Title        string     `json:"title"`
 	Description  string     `json:"description"`
 	Requirements string     `json:"requirements"`
-	BudgetMin    float64    `json:"budget_min" binding:"min=0"`
-	BudgetMax    float64    `json:"budget_max" binding:"min=0"`
+	BudgetMin    int64      `json:"budget_min" binding:"min=0"`
+	BudgetMax    int64      `json:"budget_max" binding:"min=0"`
 	Deadline     *time.Time `json:"deadline,omitempty"`
 }
 
 type CustomOrderAdminUpdateRequest struct {
-	Status       string   `json:"status" binding:"omitempty,oneof=submitted under_review in_progress completed cancelled"`
-	AdminNotes   string   `json:"admin_notes"`
-	QuoteAmount  *float64 `json:"quote_amount"`
-	AgreedAmount *float64 `json:"agreed_amount"`
+	Status       string `json:"status" binding:"omitempty,oneof=submitted under_review in_progress completed cancelled"`
+	AdminNotes   string `json:"admin_notes"`
+	QuoteAmount  *int64 `json:"quote_amount"`
+	AgreedAmount *int64 `json:"agreed_amount"`
 }
 
 type CustomOrderResponse struct {
@@ -59,12 +61,13 @@ type CustomOrderResponse struct {
 	Title        string     `json:"title"`
 	Description  string     `json:"description"`
 	Requirements string     `json:"requirements"`
-	BudgetMin    float64    `json:"budget_min"`
-	BudgetMax    float64    `json:"budget_max"`
+	Currency     string     `json:"currency"`
+	BudgetMin    int64      `json:"budget_min"`
+	BudgetMax    int64      `json:"budget_max"`
 	Deadline     *time.Time `json:"deadline,omitempty"`
 	Status       string     `json:"status"`
 	AdminNotes   string     `json:"admin_notes,omitempty"`
-	QuoteAmount  *float64   `json:"quote_amount,omitempty"`
-	AgreedAmount *float64   `json:"agreed_amount,omitempty"`
+	QuoteAmount  *int64     `json:"quote_amount,omitempty"`
+	AgreedAmount *int64     `json:"agreed_amount,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 }
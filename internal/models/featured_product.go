@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FeaturedProduct is an admin curated slot on the featured list.
+// A slot is live when the current time falls within StartsAt and EndsAt (either may be open).
+type FeaturedProduct struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ProductID uint           `gorm:"index;not null" json:"product_id"`
+	Product   *Product       `json:"product,omitempty"`
+	Position  int            `gorm:"default:0;index" json:"position"`
+	StartsAt  *time.Time     `json:"starts_at,omitempty"`
+	EndsAt    *time.Time     `json:"ends_at,omitempty"`
+	CreatedBy uint           `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type FeaturedProductRequest struct {
+	ProductID uint       `json:"product_id" binding:"required"`
+	Position  int        `json:"position" binding:"min=0"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+}
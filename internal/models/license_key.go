@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LicenseKey is a signed key issued for a paid order line of a product that
+// requires one. The key embeds a LicenseKeyPayload and its Ed25519 signature.
+// Each order line gets at most one key per product.
+type LicenseKey struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	Key            string              `gorm:"size:512;uniqueIndex;not null" json:"key"`
+	OrderID        uint                `gorm:"index;not null" json:"order_id"`
+	OrderItemID    *uint               `gorm:"uniqueIndex:idx_license_keys_item_product" json:"order_item_id,omitempty"` // Empty on keys from before per-line issuance
+	UserID         uint                `gorm:"index;not null" json:"user_id"`
+	ProductID      uint                `gorm:"index;uniqueIndex:idx_license_keys_item_product;not null" json:"product_id"`
+	Product        *Product            `json:"product,omitempty"`
+	LicenseTierID  *uint               `json:"license_tier_id,omitempty"`
+	LicenseTier    *LicenseTier        `json:"license_tier,omitempty"`
+	MaxActivations int                 `gorm:"not null;default:1" json:"max_activations"`
+	Status         string              `gorm:"size:20;not null;default:'active'" json:"status"` // active, revoked, replaced
+	RevokedAt      *time.Time          `json:"revoked_at,omitempty"`
+	Activations    []LicenseActivation `json:"activations,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt      `gorm:"index" json:"-"`
+}
+
+// LicenseActivation binds a license key to one machine
+type LicenseActivation struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	LicenseKeyID    uint      `gorm:"uniqueIndex:idx_license_activation_machine;not null" json:"license_key_id"`
+	Fingerprint     string    `gorm:"size:255;uniqueIndex:idx_license_activation_machine;not null" json:"fingerprint"`
+	MachineName     string    `gorm:"size:255" json:"machine_name,omitempty"`
+	LastValidatedAt time.Time `json:"last_validated_at"`
+	CreatedAt       time.Time `json:"activated_at"`
+}
+
+// LicenseKeyPayload is the signed content of a license key. Shipped software
+// can verify it offline with the public key from GET /licenses/public-key.
+type LicenseKeyPayload struct {
+	LicenseID      uint   `json:"lid"`
+	ProductID      uint   `json:"pid"`
+	OrderID        uint   `json:"oid"`
+	Tier           string `json:"tier,omitempty"`
+	MaxActivations int    `json:"max"`
+	IssuedAt       int64  `json:"iat"`
+}
+
+type LicenseActivationRequest struct {
+	Key         string `json:"key" binding:"required"`
+	Fingerprint string `json:"fingerprint" binding:"required,max=255"`
+	MachineName string `json:"machine_name" binding:"max=255"`
+}
+
+type LicenseValidateRequest struct {
+	Key         string `json:"key" binding:"required"`
+	Fingerprint string `json:"fingerprint" binding:"max=255"`
+}
+
+// LicenseValidation is the answer to a validation request. Valid is only true
+// for a genuine, active key that is activated on the given fingerprint, if any.
+type LicenseValidation struct {
+	Valid           bool               `json:"valid"`
+	Status          string             `json:"status,omitempty"`
+	Reason          string             `json:"reason,omitempty"`
+	Payload         *LicenseKeyPayload `json:"payload,omitempty"`
+	Activated       bool               `json:"activated"`
+	ActivationsUsed int                `json:"activations_used"`
+	MaxActivations  int                `json:"max_activations"`
+}
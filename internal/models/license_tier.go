@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LicenseTier struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ProductID uint           `gorm:"index;not null" json:"product_id"`
+	Product   *Product       `json:"product,omitempty"`
+	Name      string         `gorm:"size:20;not null" json:"name"` // personal, commercial, extended
+	Price     int64          `gorm:"not null" json:"price"`        // Minor units of the product currency
+	Terms     string         `gorm:"type:text" json:"terms"`
+	Seats     int            `gorm:"default:1" json:"seats"`
+	IsActive  bool           `gorm:"not null" json:"is_active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type LicenseTierRequest struct {
+	Name     string `json:"name" binding:"required,oneof=personal commercial extended"`
+	Price    int64  `json:"price" binding:"required,gt=0"`
+	Terms    string `json:"terms"`
+	Seats    int    `json:"seats" binding:"min=0"`
+	IsActive *bool  `json:"is_active"`
+}
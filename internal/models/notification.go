@@ -10,7 +10,7 @@ type Notification struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	UserID    uint           `json:"user_id"`
 	User      *User          `json:"user,omitempty"`
-	Type      string         `gorm:"size:50;not null" json:"type"` // order, payment, system, custom_order
+	Type      string         `gorm:"size:50;not null" json:"type"` // order, payment, system, custom_order, question
 	Title     string         `gorm:"size:255;not null" json:"title"`
 	Message   string         `gorm:"type:text;not null" json:"message"`
 	ActionURL string         `gorm:"size:500" json:"action_url,omitempty"`
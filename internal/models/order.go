@@ -11,27 +11,78 @@ type Order struct {
 	OrderNumber    string         `gorm:"size:50;uniqueIndex;not null" json:"order_number"`
 	UserID         uint           `json:"user_id"`
 	User           *User          `json:"user,omitempty"`
-	ProductID      *uint          `json:"product_id,omitempty"`
+	ProductID      *uint          `json:"product_id,omitempty"` // Product, bundle and tier of single-item orders; Items lists every line
 	Product        *Product       `json:"product,omitempty"`
-	OrderType      string         `gorm:"size:20;not null" json:"order_type"` // product, custom
-	Status         string         `gorm:"size:20;not null" json:"status"`     // pending, processing, completed, cancelled, refunded
-	TotalAmount    float64        `gorm:"not null" json:"total_amount"`
-	DiscountAmount float64        `gorm:"default:0" json:"discount_amount"`
-	FinalAmount    float64        `gorm:"not null" json:"final_amount"`
+	BundleID       *uint          `json:"bundle_id,omitempty"`
+	Bundle         *Bundle        `json:"bundle,omitempty"`
+	LicenseTierID  *uint          `json:"license_tier_id,omitempty"`
+	LicenseTier    *LicenseTier   `json:"license_tier,omitempty"`
+	Quantity       int            `gorm:"not null;default:1" json:"quantity"`
+	OrderType      string         `gorm:"size:20;not null" json:"order_type"`            // product, bundle, license_upgrade, cart, custom
+	Status         string         `gorm:"size:20;not null" json:"status"`                // pending, processing, completed, cancelled, refunded
+	Currency       string         `gorm:"size:3;not null;default:'IDR'" json:"currency"` // Currency the order is charged in
+	TotalAmount    int64          `gorm:"not null" json:"total_amount"`                  // Minor units of Currency
+	DiscountAmount int64          `gorm:"default:0" json:"discount_amount"`              // Coupon discount, minor units of Currency
+	CouponID       *uint          `gorm:"index" json:"coupon_id,omitempty"`
+	CouponCode     string         `gorm:"size:50" json:"coupon_code,omitempty"`
+	TaxAmount      int64          `gorm:"not null;default:0" json:"tax_amount"` // Tax contained in FinalAmount, minor units of Currency
+	FinalAmount    int64          `gorm:"not null" json:"final_amount"`         // What the buyer pays, tax included
+	TaxCountry     string         `gorm:"size:2" json:"tax_country,omitempty"`  // Where the buyer was taxed
+	TaxRegion      string         `gorm:"size:100" json:"tax_region,omitempty"`
+	BusinessName   string         `gorm:"size:255" json:"business_name,omitempty"` // Buyer's business details at checkout
+	BuyerTaxID     string         `gorm:"size:50" json:"buyer_tax_id,omitempty"`
+	ListCurrency   string         `gorm:"size:3;not null;default:'IDR'" json:"list_currency"`          // Currency of the items' prices, Currency when they differ
+	ExchangeRate   float64        `gorm:"type:numeric(20,10);not null;default:1" json:"exchange_rate"` // ListCurrency to Currency, locked at checkout
+	BaseRate       float64        `gorm:"type:numeric(20,10);not null;default:1" json:"base_rate"`     // Currency to BaseCurrency, locked at checkout
 	PaymentMethod  string         `gorm:"size:50" json:"payment_method"`
-	PaymentStatus  string         `gorm:"size:20;not null" json:"payment_status"` // pending, paid, failed, refunded
+	PaymentStatus  string         `gorm:"size:20;not null" json:"payment_status"` // pending, paid, failed, cancelled, expired, refunded
 	PaymentID      string         `gorm:"size:255" json:"payment_id"`
 	Notes          string         `gorm:"type:text" json:"notes,omitempty"`
 	CustomOrder    *CustomOrder   `json:"custom_order,omitempty"`
+	Items          []OrderItem    `json:"items,omitempty"`
+	Taxes          []OrderTax     `json:"taxes,omitempty"`
 	Transactions   []Transaction  `json:"transactions,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// OrderItem is one line of an order: a product, optionally in a license tier,
+// or a bundle. Title and prices are snapshots taken at checkout.
+type OrderItem struct {
+	ID             uint               `gorm:"primaryKey" json:"id"`
+	OrderID        uint               `gorm:"index;not null" json:"order_id"`
+	ProductID      *uint              `gorm:"index" json:"product_id,omitempty"`
+	Product        *Product           `json:"product,omitempty"`
+	BundleID       *uint              `gorm:"index" json:"bundle_id,omitempty"`
+	Bundle         *Bundle            `json:"bundle,omitempty"`
+	LicenseTierID  *uint              `json:"license_tier_id,omitempty"`
+	LicenseTier    *LicenseTier       `json:"license_tier,omitempty"`
+	Title          string             `gorm:"size:255;not null" json:"title"`
+	Quantity       int                `gorm:"not null;default:1" json:"quantity"`
+	ListCurrency   string             `gorm:"size:3;not null" json:"list_currency"`                        // Currency of UnitPrice
+	UnitPrice      int64              `gorm:"not null" json:"unit_price"`                                  // Minor units of ListCurrency, sale and tier applied
+	ExchangeRate   float64            `gorm:"type:numeric(20,10);not null;default:1" json:"exchange_rate"` // ListCurrency to the order's Currency
+	Amount         int64              `gorm:"not null" json:"amount"`                                      // Line total in minor units of the order's Currency
+	DiscountAmount int64              `gorm:"not null;default:0" json:"discount_amount"`                   // Share of the order's coupon discount
+	BundleProducts []OrderItemProduct `json:"bundle_products,omitempty"`                                   // What a bundle line granted, at checkout
+	TaxRate        float64            `gorm:"type:numeric(7,4);not null;default:0" json:"tax_rate"`        // Percent
+	TaxAmount      int64              `gorm:"not null;default:0" json:"tax_amount"`                        // Tax on Amount less DiscountAmount
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// OrderItemProduct is a product a bundle line granted. It is recorded at
+// checkout so later edits of the bundle never change what was bought.
+type OrderItemProduct struct {
+	OrderItemID uint     `gorm:"primaryKey" json:"order_item_id"`
+	ProductID   uint     `gorm:"primaryKey;index" json:"product_id"`
+	Product     *Product `json:"product,omitempty"`
+}
+
 type OrderCreateRequest struct {
 	ProductID     *uint  `json:"product_id"`
-	OrderType     string `json:"order_type" binding:"required,oneof=product custom"`
+	BundleID      *uint  `json:"bundle_id"`
+	OrderType     string `json:"order_type" binding:"required,oneof=product bundle custom"`
 	PaymentMethod string `json:"payment_method" binding:"required"`
 	Notes         string `json:"notes,omitempty"`
 }
@@ -42,9 +93,12 @@ type OrderResponse struct {
 	OrderType      string       `json:"order_type"`
 	Status         string       `json:"status"`
 	Product        *Product     `json:"product,omitempty"`
-	TotalAmount    float64      `json:"total_amount"`
-	DiscountAmount float64      `json:"discount_amount"`
-	FinalAmount    float64      `json:"final_amount"`
+	Bundle         *Bundle      `json:"bundle,omitempty"`
+	LicenseTier    *LicenseTier `json:"license_tier,omitempty"`
+	Currency       string       `json:"currency"`
+	TotalAmount    int64        `json:"total_amount"`
+	DiscountAmount int64        `json:"discount_amount"`
+	FinalAmount    int64        `json:"final_amount"`
 	PaymentMethod  string       `json:"payment_method"`
 	PaymentStatus  string       `json:"payment_status"`
 	CustomOrder    *CustomOrder `json:"custom_order,omitempty"`
@@ -7,31 +7,43 @@ import (
 )
 
 type Product struct {
-	ID             uint           `gorm:"primaryKey" json:"id"`
-	Title          string         `gorm:"size:255;not null" json:"title"`
-	Slug           string         `gorm:"size:255;uniqueIndex;not null" json:"slug"`
-	Description    string         `gorm:"type:text" json:"description"`
-	CategoryID     uint           `json:"category_id"`
-	Category       *Category      `json:"category,omitempty"`
-	Type           string         `gorm:"size:50;not null" json:"type"` // source_code, pdf, template, other
-	Price          float64        `gorm:"not null" json:"price"`
-	DiscountPrice  *float64       `json:"discount_price,omitempty"`
-	PreviewImages  string         `gorm:"type:jsonb" json:"preview_images"` // JSON array
-	DemoURL        string         `gorm:"size:500" json:"demo_url,omitempty"`
-	FileURL        string         `gorm:"size:500" json:"file_url,omitempty"`
-	TechStack      string         `gorm:"type:jsonb" json:"tech_stack"`   // JSON array
-	Features       string         `gorm:"type:jsonb" json:"features"`     // JSON array
-	Requirements   string         `gorm:"type:jsonb" json:"requirements"` // JSON array
-	DownloadsCount int            `gorm:"default:0" json:"downloads_count"`
-	ViewsCount     int            `gorm:"default:0" json:"views_count"`
-	RatingAverage  float64        `gorm:"default:0" json:"rating_average"`
-	IsActive       bool           `gorm:"default:true" json:"is_active"`
-	CreatedBy      uint           `json:"created_by"`
-	Creator        *User          `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
-	Reviews        []Review       `json:"reviews,omitempty"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint                 `gorm:"primaryKey" json:"id"`
+	Title           string               `gorm:"size:255;not null" json:"title"`
+	Slug            string               `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	Description     string               `gorm:"type:text" json:"description"`
+	CategoryID      uint                 `json:"category_id"`
+	Category        *Category            `json:"category,omitempty"`
+	Type            string               `gorm:"size:50;not null" json:"type"` // source_code, pdf, template, other
+	Currency        string               `gorm:"size:3;not null;default:'IDR'" json:"currency"`
+	Price           int64                `gorm:"not null" json:"price"` // Minor units of Currency
+	DiscountPrice   *int64               `json:"discount_price,omitempty"`
+	PreviewImages   string               `gorm:"type:jsonb;default:'[]'" json:"preview_images"` // JSON array
+	DemoURL         string               `gorm:"size:500" json:"demo_url,omitempty"`
+	FileURL         string               `gorm:"size:500" json:"-"`                              // Private deliverable path, never exposed
+	RequiresLicense bool                 `gorm:"not null;default:false" json:"requires_license"` // Issue license keys when paid
+	TechStack       StringArray          `gorm:"type:jsonb;default:'[]'" json:"tech_stack"`
+	Features        StringArray          `gorm:"type:jsonb;default:'[]'" json:"features"`
+	Requirements    StringArray          `gorm:"type:jsonb;default:'[]'" json:"requirements"`
+	DownloadsCount  int                  `gorm:"default:0" json:"downloads_count"`
+	ViewsCount      int                  `gorm:"default:0" json:"views_count"`
+	RatingAverage   float64              `gorm:"default:0" json:"rating_average"`
+	RatingCount     int                  `gorm:"default:0" json:"rating_count"`
+	RatingHistogram RatingHistogram      `gorm:"embedded" json:"rating_histogram"`
+	Status          string               `gorm:"size:20;not null;default:'draft';index" json:"status"` // draft, in_review, scheduled, published, archived
+	PublishAt       *time.Time           `json:"publish_at,omitempty"`
+	PublishedAt     *time.Time           `json:"published_at,omitempty"`
+	CreatedBy       uint                 `json:"created_by"`
+	Creator         *User                `gorm:"foreignKey:CreatedBy" json:"creator,omitempty"`
+	Reviews         []Review             `json:"reviews,omitempty"`
+	SalePrice       *int64               `gorm:"-" json:"sale_price,omitempty"`       // Set when an active campaign beats the regular price
+	Sale            *ProductSale         `gorm:"-" json:"sale,omitempty"`             // Winning active campaign, if any
+	LowestPrice30d  *int64               `gorm:"-" json:"lowest_price_30d,omitempty"` // Lowest price in the 30 days before the current price
+	Display         *PriceDisplay        `gorm:"-" json:"display,omitempty"`          // Prices converted to the requested currency
+	Breadcrumbs     []CategoryBreadcrumb `gorm:"-" json:"breadcrumbs,omitempty"`      // Category path from the top level
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	DeletedBy       *uint                `gorm:"index" json:"-"` // Who moved the row to the trash
+	DeletedAt       gorm.DeletedAt       `gorm:"index" json:"-"`
 }
 
 type ProductCreateRequest struct {
@@ -39,8 +51,9 @@ type ProductCreateRequest struct {
 	Description   string   `json:"description" binding:"required"`
 	CategoryID    uint     `json:"category_id" binding:"required"`
 	Type          string   `json:"type" binding:"required,oneof=source_code pdf template other"`
-	Price         float64  `json:"price" binding:"required,min=0"`
-	DiscountPrice *float64 `json:"discount_price,omitempty"`
+	Currency      string   `json:"currency,omitempty"`
+	Price         int64    `json:"price" binding:"required,min=0"`
+	DiscountPrice *int64   `json:"discount_price,omitempty"`
 	DemoURL       string   `json:"demo_url,omitempty"`
 	TechStack     []string `json:"tech_stack,omitempty"`
 	Features      []string `json:"features,omitempty"`
@@ -52,31 +65,74 @@ type ProductUpdateRequest struct {
 	Description   string   `json:"description"`
 	CategoryID    uint     `json:"category_id"`
 	Type          string   `json:"type" binding:"omitempty,oneof=source_code pdf template other"`
-	Price         float64  `json:"price" binding:"min=0"`
-	DiscountPrice *float64 `json:"discount_price,omitempty"`
+	Currency      string   `json:"currency,omitempty"`
+	Price         int64    `json:"price" binding:"min=0"`
+	DiscountPrice *int64   `json:"discount_price,omitempty"`
 	DemoURL       string   `json:"demo_url,omitempty"`
 	TechStack     []string `json:"tech_stack,omitempty"`
 	Features      []string `json:"features,omitempty"`
 	Requirements  []string `json:"requirements,omitempty"`
-	IsActive      *bool    `json:"is_active,omitempty"`
 }
 
 type ProductResponse struct {
-	ID             uint      `json:"id"`
-	Title          string    `json:"title"`
-	Slug           string    `json:"slug"`
-	Description    string    `json:"description"`
-	Category       *Category `json:"category,omitempty"`
-	Type           string    `json:"type"`
-	Price          float64   `json:"price"`
-	DiscountPrice  *float64  `json:"discount_price,omitempty"`
-	PreviewImages  []string  `json:"preview_images,omitempty"`
-	DemoURL        string    `json:"demo_url,omitempty"`
-	TechStack      []string  `json:"tech_stack,omitempty"`
-	Features       []string  `json:"features,omitempty"`
-	DownloadsCount int       `json:"downloads_count"`
-	ViewsCount     int       `json:"views_count"`
-	RatingAverage  float64   `json:"rating_average"`
-	IsActive       bool      `json:"is_active"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID              uint            `json:"id"`
+	Title           string          `json:"title"`
+	Slug            string          `json:"slug"`
+	Description     string          `json:"description"`
+	Category        *Category       `json:"category,omitempty"`
+	Type            string          `json:"type"`
+	Currency        string          `json:"currency"`
+	Price           int64           `json:"price"`
+	DiscountPrice   *int64          `json:"discount_price,omitempty"`
+	PreviewImages   []string        `json:"preview_images,omitempty"`
+	DemoURL         string          `json:"demo_url,omitempty"`
+	TechStack       []string        `json:"tech_stack,omitempty"`
+	Features        []string        `json:"features,omitempty"`
+	DownloadsCount  int             `json:"downloads_count"`
+	ViewsCount      int             `json:"views_count"`
+	RatingAverage   float64         `json:"rating_average"`
+	RatingCount     int             `json:"rating_count"`
+	RatingHistogram RatingHistogram `json:"rating_histogram"`
+	Status          string          `json:"status"`
+	PublishedAt     *time.Time      `json:"published_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// RatingHistogram counts reviews per star rating. It is maintained together
+// with RatingAverage and RatingCount whenever reviews change.
+type RatingHistogram struct {
+	OneStar   int `gorm:"column:rating_1_count;default:0" json:"1"`
+	TwoStar   int `gorm:"column:rating_2_count;default:0" json:"2"`
+	ThreeStar int `gorm:"column:rating_3_count;default:0" json:"3"`
+	FourStar  int `gorm:"column:rating_4_count;default:0" json:"4"`
+	FiveStar  int `gorm:"column:rating_5_count;default:0" json:"5"`
+}
+
+// ProductFilter holds listing filters and sort order for GET /products.
+// It is validated and whitelisted by the repository before use.
+type ProductFilter struct {
+	CategoryID           *uint
+	IncludeSubcategories bool // Also match products in descendants of CategoryID
+	Search               string
+	MinPrice             *int64 // BaseCurrency minor units
+	MaxPrice             *int64
+	Types                []string
+	TechStacks           []string
+	MinRating            *float64
+	OnSale               bool
+	Sort                 string   // relevance, newest, price_asc, price_desc, popular, rating
+	Statuses             []string // defaults to published only; admin listings may widen it
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type ProductFacets struct {
+	Categories  []FacetCount `json:"categories"`
+	Types       []FacetCount `json:"types"`
+	TechStacks  []FacetCount `json:"tech_stacks"`
+	PriceRanges []FacetCount `json:"price_ranges"`
 }
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// ProductFile is one entry of a source code deliverable's archive. Only the
+// index is stored; contents are read from the archive on demand and only for
+// files an admin marked as previewable.
+type ProductFile struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ProductID     uint      `gorm:"uniqueIndex:idx_product_file_path;not null" json:"product_id"`
+	Path          string    `gorm:"size:1000;uniqueIndex:idx_product_file_path;not null" json:"path"`
+	Size          int64     `gorm:"not null" json:"size"`
+	Language      string    `gorm:"size:50" json:"language,omitempty"`
+	IsPreviewable bool      `gorm:"not null;default:false" json:"is_previewable"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ProductTreeNode is a directory or file in the browsable archive tree
+type ProductTreeNode struct {
+	Name          string             `json:"name"`
+	Path          string             `json:"path"`
+	Type          string             `json:"type"` // dir, file
+	Size          int64              `json:"size"`
+	Language      string             `json:"language,omitempty"`
+	IsPreviewable bool               `json:"is_previewable,omitempty"`
+	Children      []*ProductTreeNode `json:"children,omitempty"`
+}
+
+type ProductTree struct {
+	FilesCount int64              `json:"files_count"`
+	TotalSize  int64              `json:"total_size"`
+	Languages  []FacetCount       `json:"languages"`
+	Tree       []*ProductTreeNode `json:"tree"`
+}
+
+type ProductFileContent struct {
+	Path      string `json:"path"`
+	Language  string `json:"language,omitempty"`
+	Size      int64  `json:"size"`
+	Lines     int    `json:"lines"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated"`
+}
+
+type ProductFilePreviewRequest struct {
+	Paths       []string `json:"paths" binding:"required,min=1"`
+	Previewable *bool    `json:"previewable" binding:"required"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// ProductImportRow is one product in an import file. CSV files use the same
+// column names, with list fields separated by "|".
+type ProductImportRow struct {
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	CategorySlug  string   `json:"category_slug"`
+	Type          string   `json:"type"`
+	Currency      string   `json:"currency,omitempty"` // defaults to BaseCurrency
+	Price         int64    `json:"price"`
+	DiscountPrice *int64   `json:"discount_price,omitempty"`
+	DemoURL       string   `json:"demo_url,omitempty"`
+	TechStack     []string `json:"tech_stack"`
+	Features      []string `json:"features"`
+	Requirements  []string `json:"requirements"`
+	Status        string   `json:"status,omitempty"` // export only, ignored on import
+}
+
+type ProductImportRowResult struct {
+	Row    int      `json:"row"`
+	Slug   string   `json:"slug"`
+	Action string   `json:"action"` // create, update, review (published product, changes await review), skip
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ProductImportReport struct {
+	DryRun        bool                     `json:"dry_run"`
+	Applied       bool                     `json:"applied"`
+	Total         int                      `json:"total"`
+	Created       int                      `json:"created"`
+	Updated       int                      `json:"updated"`
+	PendingReview int                      `json:"pending_review"` // Published products whose changes await review
+	Failed        int                      `json:"failed"`
+	Rows          []ProductImportRowResult `json:"rows"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProductQuestion struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	ProductID    uint            `gorm:"index;not null" json:"product_id"`
+	Product      *Product        `json:"product,omitempty"`
+	UserID       uint            `gorm:"index;not null" json:"user_id"`
+	User         *User           `json:"user,omitempty"`
+	Body         string          `gorm:"type:text;not null" json:"body"`
+	Status       string          `gorm:"size:20;not null;default:'pending';index" json:"status"` // pending, approved, rejected
+	RejectReason string          `gorm:"size:500" json:"reject_reason,omitempty"`
+	UpvotesCount int             `gorm:"not null;default:0" json:"upvotes_count"`
+	AnswersCount int             `gorm:"not null;default:0" json:"answers_count"`
+	ModeratedAt  *time.Time      `json:"moderated_at,omitempty"`
+	Answers      []ProductAnswer `gorm:"foreignKey:QuestionID" json:"answers,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt  `gorm:"index" json:"-"`
+}
+
+type ProductAnswer struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	QuestionID uint           `gorm:"index;not null" json:"question_id"`
+	UserID     uint           `gorm:"not null" json:"user_id"`
+	User       *User          `json:"user,omitempty"`
+	Body       string         `gorm:"type:text;not null" json:"body"`
+	IsCreator  bool           `gorm:"not null;default:false" json:"is_creator"` // answered by the product's creator rather than an admin
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ProductQuestionVote records one upvote per user and question
+type ProductQuestionVote struct {
+	QuestionID uint      `gorm:"primaryKey" json:"question_id"`
+	UserID     uint      `gorm:"primaryKey" json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ProductQuestionRequest struct {
+	Body string `json:"body" binding:"required,min=10,max=1000"`
+}
+
+type ProductAnswerRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+type QuestionModerationRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
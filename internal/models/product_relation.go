@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ProductRelation is one precomputed "related product" edge. Co-purchase edges
+// come from paid orders; similar edges from shared category and tech stack.
+type ProductRelation struct {
+	ProductID        uint      `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	RelatedProductID uint      `gorm:"primaryKey;autoIncrement:false" json:"related_product_id"`
+	Source           string    `gorm:"size:20;not null" json:"source"` // co_purchase, similar
+	Score            float64   `gorm:"not null" json:"score"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
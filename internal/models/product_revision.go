@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ProductRevision holds edits to a product that is already published or
+// scheduled. The storefront keeps the approved content until a reviewer other
+// than the editor approves the revision. A product has at most one pending
+// revision; later edits build on it.
+type ProductRevision struct {
+	ID          uint                   `gorm:"primaryKey" json:"id"`
+	ProductID   uint                   `gorm:"not null;index;uniqueIndex:idx_product_revisions_pending,where:status = 'pending'" json:"product_id"`
+	Product     *Product               `json:"product,omitempty"`
+	Content     ProductRevisionContent `gorm:"type:jsonb;not null" json:"content"`
+	Status      string                 `gorm:"size:20;not null;default:'pending';index" json:"status"` // pending, approved, rejected
+	Comment     string                 `gorm:"type:text" json:"comment,omitempty"`                     // Reviewer's comment
+	SubmittedBy uint                   `gorm:"not null" json:"submitted_by"`
+	ReviewedBy  *uint                  `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time             `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// ProductRevisionContent is the editable content of a product as a revision
+// proposes it. The slug follows the title when the revision is approved.
+type ProductRevisionContent struct {
+	Title           string      `json:"title"`
+	Description     string      `json:"description"`
+	CategoryID      uint        `json:"category_id"`
+	Type            string      `json:"type"`
+	Currency        string      `json:"currency"`
+	Price           int64       `json:"price"`
+	DiscountPrice   *int64      `json:"discount_price,omitempty"`
+	PreviewImages   string      `json:"preview_images"`
+	DemoURL         string      `json:"demo_url,omitempty"`
+	TechStack       StringArray `json:"tech_stack"`
+	Features        StringArray `json:"features"`
+	Requirements    StringArray `json:"requirements"`
+	RequiresLicense bool        `json:"requires_license"`
+}
+
+func (c ProductRevisionContent) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *ProductRevisionContent) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return errors.New("unsupported type for ProductRevisionContent")
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ProductStatusLog records every workflow transition of a product together
+// with the reviewer's comment. ActorID is nil for scheduled publishing.
+type ProductStatusLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ProductID  uint      `gorm:"index;not null" json:"product_id"`
+	FromStatus string    `gorm:"size:20;not null" json:"from_status"`
+	ToStatus   string    `gorm:"size:20;not null" json:"to_status"`
+	Comment    string    `gorm:"type:text" json:"comment,omitempty"`
+	ActorID    *uint     `json:"actor_id,omitempty"`
+	Actor      *User     `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ProductWorkflowRequest struct {
+	Comment   string     `json:"comment"`
+	PublishAt *time.Time `json:"publish_at"`
+}
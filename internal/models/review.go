@@ -19,6 +19,7 @@ type Review struct {
 	IsVerifiedPurchase bool           `gorm:"default:false" json:"is_verified_purchase"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedBy          *uint          `gorm:"index" json:"-"` // Who moved the row to the trash
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SaleCampaign is a time-boxed discount on selected products, categories or the
+// whole store. It is applied when prices are read, never written onto products.
+type SaleCampaign struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Name          string         `gorm:"size:150;not null" json:"name"`
+	Description   string         `gorm:"type:text" json:"description,omitempty"`
+	DiscountType  string         `gorm:"size:20;not null" json:"discount_type"` // percentage, fixed
+	DiscountValue int64          `gorm:"not null" json:"discount_value"`        // Whole percent, or minor units of Currency
+	Currency      string         `gorm:"size:3" json:"currency,omitempty"`      // Fixed discounts only apply to products priced in this currency
+	Scope         string         `gorm:"size:20;not null" json:"scope"`         // store, category, product
+	Priority      int            `gorm:"not null;default:0" json:"priority"`
+	StartsAt      time.Time      `gorm:"not null;index" json:"starts_at"`
+	EndsAt        time.Time      `gorm:"not null;index" json:"ends_at"`
+	IsActive      bool           `gorm:"not null" json:"is_active"`
+	Products      []Product      `gorm:"many2many:sale_campaign_products" json:"products,omitempty"`
+	Categories    []Category     `gorm:"many2many:sale_campaign_categories" json:"categories,omitempty"`
+	CreatedBy     uint           `json:"created_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type SaleCampaignRequest struct {
+	Name          string    `json:"name" binding:"required,max=150"`
+	Description   string    `json:"description"`
+	DiscountType  string    `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	DiscountValue int64     `json:"discount_value" binding:"required,gt=0"`
+	Currency      string    `json:"currency"` // required for fixed discounts, defaults to BaseCurrency
+	Scope         string    `json:"scope" binding:"required,oneof=store category product"`
+	ProductIDs    []uint    `json:"product_ids"`
+	CategoryIDs   []uint    `json:"category_ids"`
+	Priority      int       `json:"priority"`
+	StartsAt      time.Time `json:"starts_at" binding:"required"`
+	EndsAt        time.Time `json:"ends_at" binding:"required"`
+	IsActive      *bool     `json:"is_active"`
+}
+
+// ProductSale describes the campaign currently discounting a product
+type ProductSale struct {
+	CampaignID    uint      `json:"campaign_id"`
+	Name          string    `json:"name"`
+	DiscountType  string    `json:"discount_type"`
+	DiscountValue int64     `json:"discount_value"`
+	EndsAt        time.Time `json:"ends_at"`
+}
+
+// ProductPriceHistory records every change of the price a buyer pays for a
+// product's base license. A row is in effect until the next one is recorded.
+type ProductPriceHistory struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ProductID      uint      `gorm:"index:idx_price_history_product_time;not null" json:"product_id"`
+	Currency       string    `gorm:"size:3;not null;default:'IDR'" json:"currency"`
+	Price          int64     `gorm:"not null" json:"price"`
+	RegularPrice   int64     `gorm:"not null" json:"regular_price"`
+	SaleCampaignID *uint     `json:"sale_campaign_id,omitempty"`
+	RecordedAt     time.Time `gorm:"index:idx_price_history_product_time;not null" json:"recorded_at"`
+}
+
+type ProductPriceHistoryResponse struct {
+	Currency          string                `json:"currency"`
+	CurrentPrice      int64                 `json:"current_price"`
+	LowestPrice30Days *int64                `json:"lowest_price_30d"`
+	History           []ProductPriceHistory `json:"history"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SlugRedirect remembers a slug an entity used to have so old links can be
+// redirected to the current one.
+type SlugRedirect struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EntityType string    `gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_type_slug" json:"entity_type"` // product, category, bundle
+	OldSlug    string    `gorm:"size:255;not null;uniqueIndex:idx_slug_redirects_type_slug" json:"old_slug"`
+	EntityID   uint      `gorm:"index;not null" json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// StringArray is a list of strings stored in a jsonb column as a JSON array.
+// It always serializes as an array, never as null.
+type StringArray []string
+
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(a))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (a *StringArray) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = StringArray{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for StringArray")
+	}
+
+	// Rows written before the columns held real arrays may contain other JSON values
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		*a = StringArray{}
+		return nil
+	}
+	*a = values
+	return nil
+}
+
+func (a StringArray) MarshalJSON() ([]byte, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(a))
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TaxRule charges tax on sales to buyers in a country, optionally narrowed to
+// a region and a product type. Each order line is taxed by the most specific
+// active rule that matches it.
+type TaxRule struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Name          string         `gorm:"size:100;not null" json:"name"`                                                           // Shown on the order, e.g. PPN
+	Country       string         `gorm:"size:2;not null;uniqueIndex:idx_tax_rules_scope,where:deleted_at IS NULL" json:"country"` // ISO 3166-1 alpha-2
+	Region        string         `gorm:"size:100;not null;default:'';uniqueIndex:idx_tax_rules_scope,where:deleted_at IS NULL" json:"region,omitempty"`
+	ProductType   string         `gorm:"size:50;not null;default:'';uniqueIndex:idx_tax_rules_scope,where:deleted_at IS NULL" json:"product_type,omitempty"` // source_code, pdf, template, other, bundle; empty for all
+	Rate          float64        `gorm:"type:numeric(7,4);not null" json:"rate"`                                                                             // Percent
+	Inclusive     bool           `gorm:"not null;default:false" json:"inclusive"`                                                                            // Prices already include the tax
+	ReverseCharge bool           `gorm:"not null;default:false" json:"reverse_charge"`                                                                       // Business buyers with a tax ID are not charged
+	IsActive      bool           `gorm:"not null" json:"is_active"`
+	CreatedBy     uint           `json:"created_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type TaxRuleRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Country       string   `json:"country" binding:"required,len=2,alpha"`
+	Region        string   `json:"region" binding:"max=100"`
+	ProductType   string   `json:"product_type" binding:"omitempty,oneof=source_code pdf template other bundle"`
+	Rate          *float64 `json:"rate" binding:"required,min=0,max=100"` // 0 exempts the region or product type
+	Inclusive     bool     `json:"inclusive"`
+	ReverseCharge bool     `json:"reverse_charge"`
+	IsActive      *bool    `json:"is_active"`
+}
+
+// OrderTax is the tax an order was charged under one rule. Rule details are
+// snapshots so later rule changes never alter placed orders.
+type OrderTax struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	OrderID       uint      `gorm:"index;not null" json:"order_id"`
+	Order         *Order    `json:"order,omitempty"`
+	TaxRuleID     *uint     `gorm:"index" json:"tax_rule_id,omitempty"`
+	Name          string    `gorm:"size:100;not null" json:"name"`
+	Country       string    `gorm:"size:2;not null" json:"country"`
+	Region        string    `gorm:"size:100" json:"region,omitempty"`
+	Rate          float64   `gorm:"type:numeric(7,4);not null" json:"rate"`
+	Inclusive     bool      `gorm:"not null" json:"inclusive"`
+	ReverseCharge bool      `gorm:"not null" json:"reverse_charge"` // Not charged, the business buyer accounts for it
+	TaxableAmount int64     `gorm:"not null" json:"taxable_amount"` // Net of tax, minor units of the order's Currency
+	TaxAmount     int64     `gorm:"not null" json:"tax_amount"`     // Minor units of the order's Currency
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BillingDetailsRequest sets where the buyer is taxed and, for businesses,
+// the name and tax ID printed on their orders
+type BillingDetailsRequest struct {
+	Country      string `json:"country" binding:"omitempty,len=2,alpha"`
+	Region       string `json:"region" binding:"max=100"`
+	BusinessName string `json:"business_name" binding:"max=255"`
+	TaxID        string `json:"tax_id" binding:"max=50"`
+}
+
+// TaxIDVerificationRequest records whether an admin checked the user's tax ID
+// against their billing country
+type TaxIDVerificationRequest struct {
+	Verified *bool `json:"verified" binding:"required"`
+}
+
+// TaxReportLine is the tax collected under one rule in a period, in BaseCurrency
+type TaxReportLine struct {
+	Country       string  `json:"country"`
+	Region        string  `json:"region,omitempty"`
+	Name          string  `json:"name"`
+	Rate          float64 `json:"rate"`
+	Inclusive     bool    `json:"inclusive"`
+	ReverseCharge bool    `json:"reverse_charge"`
+	Orders        int64   `json:"orders"`
+	TaxableAmount int64   `json:"taxable_amount"`
+	TaxAmount     int64   `json:"tax_amount"`
+}
@@ -13,7 +13,9 @@ type Transaction struct {
 	UserID            uint           `json:"user_id"`
 	User              *User          `json:"user,omitempty"`
 	TransactionNumber string         `gorm:"size:50;uniqueIndex;not null" json:"transaction_number"`
-	Amount            float64        `gorm:"not null" json:"amount"`
+	Amount            int64          `gorm:"not null" json:"amount"`               // Minor units of Currency
+	TaxAmount         int64          `gorm:"not null;default:0" json:"tax_amount"` // Tax contained in Amount
+	Currency          string         `gorm:"size:3;not null;default:'IDR'" json:"currency"`
 	PaymentMethod     string         `gorm:"size:50;not null" json:"payment_method"`  // credit_card, bank_transfer, ewallet, crypto
 	PaymentGateway    string         `gorm:"size:50;not null" json:"payment_gateway"` // midtrans, stripe, xendit
 	PaymentGatewayRef string         `gorm:"size:255" json:"payment_gateway_ref"`
@@ -29,7 +31,9 @@ type TransactionResponse struct {
 	ID                uint       `json:"id"`
 	TransactionNumber string     `json:"transaction_number"`
 	OrderNumber       string     `json:"order_number"`
-	Amount            float64    `json:"amount"`
+	Amount            int64      `json:"amount"`
+	TaxAmount         int64      `json:"tax_amount"`
+	Currency          string     `json:"currency"`
 	PaymentMethod     string     `json:"payment_method"`
 	PaymentGateway    string     `json:"payment_gateway"`
 	Status            string     `json:"status"`
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ProductTranslation holds a product's content in a locale other than the
+// default one, whose content lives on the product itself. Empty fields fall
+// back along the locale chain.
+type ProductTranslation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ProductID   uint      `gorm:"uniqueIndex:idx_product_translation_locale;not null" json:"product_id"`
+	Locale      string    `gorm:"size:10;uniqueIndex:idx_product_translation_locale;not null" json:"locale"`
+	Title       string    `gorm:"size:255" json:"title"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryTranslation holds a category's content in a non-default locale
+type CategoryTranslation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CategoryID  uint      `gorm:"uniqueIndex:idx_category_translation_locale;not null" json:"category_id"`
+	Locale      string    `gorm:"size:10;uniqueIndex:idx_category_translation_locale;not null" json:"locale"`
+	Name        string    `gorm:"size:100" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ProductTranslationRequest struct {
+	Title       string `json:"title" binding:"max=255"`
+	Description string `json:"description"`
+}
+
+type CategoryTranslationRequest struct {
+	Name        string `json:"name" binding:"max=100"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TrashItem is a soft-deleted row as listed in the admin trash
+type TrashItem struct {
+	ID            uint      `json:"id"`
+	Label         string    `json:"label"`                // Title, name or review excerpt
+	Identifier    string    `json:"identifier,omitempty"` // Slug or email that must stay unique
+	DeletedAt     time.Time `json:"deleted_at"`
+	DeletedBy     *uint     `json:"deleted_by,omitempty"`
+	DeletedByName string    `json:"deleted_by_name,omitempty"`
+	PurgeAt       time.Time `gorm:"-" json:"purge_at"` // When the retention period ends
+}
+
+type TrashRestoreRequest struct {
+	Email string `json:"email" binding:"omitempty,email"` // New address for a user whose email was taken meanwhile
+}
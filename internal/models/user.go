@@ -7,18 +7,25 @@ import (
 )
 
 type User struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	Email      string         `gorm:"size:100;uniqueIndex;not null" json:"email"`
-	Password   string         `gorm:"size:255" json:"-"` // Nullable for OAuth users
-	Name       string         `gorm:"size:100;not null" json:"name"`
-	Role       string         `gorm:"size:20;default:'user'" json:"role"`      // user, admin
-	Provider   string         `gorm:"size:20;default:'local'" json:"provider"` // local, google, github
-	ProviderID string         `gorm:"size:255" json:"provider_id,omitempty"`
-	AvatarURL  string         `gorm:"size:500" json:"avatar_url,omitempty"`
-	IsVerified bool           `gorm:"default:false" json:"is_verified"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	Email         string         `gorm:"size:100;not null;uniqueIndex:idx_users_email_active,where:deleted_at IS NULL" json:"email"`
+	Password      string         `gorm:"size:255" json:"-"` // Nullable for OAuth users
+	Name          string         `gorm:"size:100;not null" json:"name"`
+	Role          string         `gorm:"size:20;default:'user'" json:"role"`      // user, admin
+	Provider      string         `gorm:"size:20;default:'local'" json:"provider"` // local, google, github
+	ProviderID    string         `gorm:"size:255" json:"provider_id,omitempty"`
+	AvatarURL     string         `gorm:"size:500" json:"avatar_url,omitempty"`
+	IsVerified    bool           `gorm:"default:false" json:"is_verified"`
+	Currency      string         `gorm:"size:3" json:"currency,omitempty"` // Preferred display currency
+	Country       string         `gorm:"size:2" json:"country,omitempty"`  // Billing country, ISO 3166-1 alpha-2
+	Region        string         `gorm:"size:100" json:"region,omitempty"`
+	BusinessName  string         `gorm:"size:255" json:"business_name,omitempty"`
+	TaxID         string         `gorm:"size:50" json:"tax_id,omitempty"`               // Business tax ID, e.g. NPWP
+	TaxIDVerified bool           `gorm:"not null;default:false" json:"tax_id_verified"` // Checked by an admin for Country; reset when either changes
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedBy     *uint          `gorm:"index" json:"-"` // Who moved the row to the trash
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type UserCreateRequest struct {
@@ -28,8 +35,9 @@ type UserCreateRequest struct {
 }
 
 type UserUpdateRequest struct {
-	Name  string `json:"name" binding:"required"`
-	Email string `json:"email" binding:"required,email"`
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Currency string `json:"currency" binding:"omitempty,len=3"`
 }
 
 type UserLoginRequest struct {
@@ -38,14 +46,20 @@ type UserLoginRequest struct {
 }
 
 type UserResponse struct {
-	ID         uint      `json:"id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	Role       string    `json:"role"`
-	Provider   string    `json:"provider"`
-	AvatarURL  string    `json:"avatar_url,omitempty"`
-	IsVerified bool      `json:"is_verified"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	Provider      string    `json:"provider"`
+	AvatarURL     string    `json:"avatar_url,omitempty"`
+	IsVerified    bool      `json:"is_verified"`
+	Currency      string    `json:"currency,omitempty"`
+	Country       string    `json:"country,omitempty"`
+	Region        string    `json:"region,omitempty"`
+	BusinessName  string    `json:"business_name,omitempty"`
+	TaxID         string    `json:"tax_id,omitempty"`
+	TaxIDVerified bool      `json:"tax_id_verified"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type AuthResponse struct {
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"fmt"
+	"gin-quickstart/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AnalyticsRepository interface {
+	RecordProductViews(day time.Time, counts map[uint]int) error
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// RecordProductViews adds buffered view counts to products.views_count and to the
+// daily product_view metric in one transaction, so both totals stay consistent.
+func (r *analyticsRepository) RecordProductViews(day time.Time, counts map[uint]int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for productID, count := range counts {
+			err := tx.Model(&models.Product{}).
+				Where("id = ?", productID).
+				UpdateColumn("views_count", gorm.Expr("views_count + ?", count)).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Exec(`INSERT INTO analytics (date, metric_type, metric_value, metadata, created_at)
+				VALUES (?, 'product_view', ?, ?, ?)
+				ON CONFLICT (date, metric_type, (metadata->>'product_id'))
+				DO UPDATE SET metric_value = analytics.metric_value + EXCLUDED.metric_value`,
+				day, count, fmt.Sprintf(`{"product_id":%d}`, productID), time.Now()).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetupAnalyticsIndexes creates the unique index used to upsert daily per-product metrics.
+func SetupAnalyticsIndexes(db *gorm.DB) error {
+	return db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_analytics_daily_product_metric
+		ON analytics (date, metric_type, (metadata->>'product_id'))`).Error
+}
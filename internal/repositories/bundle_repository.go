@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BundleRepository interface {
+	Create(bundle *models.Bundle) error
+	GetAll(page, limit int, search string, activeOnly bool) ([]models.Bundle, int64, error)
+	GetByID(id uint) (*models.Bundle, error)
+	GetBySlug(slug string) (*models.Bundle, error)
+	UpdateWithSlug(bundle *models.Bundle, oldSlug string) error
+	Delete(id uint) error
+}
+
+type bundleRepository struct {
+	db *gorm.DB
+}
+
+func NewBundleRepository(db *gorm.DB) BundleRepository {
+	return &bundleRepository{db: db}
+}
+
+func (r *bundleRepository) Create(bundle *models.Bundle) error {
+	return r.db.Create(bundle).Error
+}
+
+func (r *bundleRepository) GetAll(page, limit int, search string, activeOnly bool) ([]models.Bundle, int64, error) {
+	var bundles []models.Bundle
+	var total int64
+
+	query := r.db.Model(&models.Bundle{})
+
+	if activeOnly {
+		query = query.Where("bundles.is_active = ?", true)
+	}
+
+	if search != "" {
+		query = query.Where("bundles.search_vector @@ websearch_to_tsquery(?, ?)", searchConfig, search)
+	}
+
+	query.Count(&total)
+
+	if search != "" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(bundles.search_vector, websearch_to_tsquery(?, ?)) DESC, bundles.created_at DESC",
+			Vars: []interface{}{searchConfig, search},
+		}})
+	} else {
+		query = query.Order("bundles.created_at DESC")
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Products").Offset(offset).Limit(limit).Find(&bundles).Error
+
+	return bundles, total, err
+}
+
+func (r *bundleRepository) GetByID(id uint) (*models.Bundle, error) {
+	var bundle models.Bundle
+	err := r.db.Preload("Products").Preload("Products.Category").First(&bundle, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (r *bundleRepository) GetBySlug(slug string) (*models.Bundle, error) {
+	var bundle models.Bundle
+	err := r.db.Preload("Products").Preload("Products.Category").Where("slug = ?", slug).First(&bundle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// UpdateWithSlug saves the bundle with its products and keeps its old slug
+// redirecting to it after a rename
+func (r *bundleRepository) UpdateWithSlug(bundle *models.Bundle, oldSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Products").Save(bundle).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(bundle).Association("Products").Replace(bundle.Products); err != nil {
+			return err
+		}
+		return changeSlug(tx, "bundle", oldSlug, bundle.Slug, bundle.ID)
+	})
+}
+
+func (r *bundleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Bundle{}, id).Error
+}
+
+// SetupBundleSearch creates the search_vector column, GIN index and sync trigger for bundles.
+func SetupBundleSearch(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE bundles ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+		`CREATE INDEX IF NOT EXISTS idx_bundles_search_vector ON bundles USING GIN (search_vector)`,
+		`CREATE OR REPLACE FUNCTION bundles_search_vector_update() RETURNS trigger AS $$
+		BEGIN
+			NEW.search_vector :=
+				setweight(to_tsvector('` + searchConfig + `', coalesce(NEW.title, '')), 'A') ||
+				setweight(to_tsvector('` + searchConfig + `', coalesce(NEW.description, '')), 'C');
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS bundles_search_vector_trigger ON bundles`,
+		`CREATE TRIGGER bundles_search_vector_trigger BEFORE INSERT OR UPDATE ON bundles
+			FOR EACH ROW EXECUTE FUNCTION bundles_search_vector_update()`,
+		`UPDATE bundles SET title = title WHERE search_vector IS NULL`,
+	}
+
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,18 +2,23 @@ package repositories
 
 import (
 	"gin-quickstart/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type CartRepository interface {
 	Create(cart *models.Cart) error
-	GetByUserID(userID uint) ([]models.Cart, error)
+	GetByOwner(owner models.CartOwner) ([]models.Cart, error)
 	GetByID(id uint) (*models.Cart, error)
-	GetByUserAndProduct(userID, productID uint) (*models.Cart, error)
+	GetByOwnerAndProduct(owner models.CartOwner, productID uint, licenseTierID *uint) (*models.Cart, error)
 	Update(cart *models.Cart) error
 	Delete(id uint) error
-	DeleteByUserID(userID uint) error
+	DeleteByOwner(owner models.CartOwner) error
+	CreateGuestCart(cart *models.GuestCart) error
+	TouchGuestCart(id string, expiresAt time.Time) (bool, error)
+	MergeGuestCart(guestCartID string, userID uint) error
+	DeleteExpiredGuestCarts(now time.Time) (int64, error)
 }
 
 type cartRepository struct {
@@ -24,28 +29,42 @@ func NewCartRepository(db *gorm.DB) CartRepository {
 	return &cartRepository{db: db}
 }
 
+// cartsOf narrows a query to the owner's cart rows
+func cartsOf(db *gorm.DB, owner models.CartOwner) *gorm.DB {
+	if owner.UserID != 0 {
+		return db.Where("user_id = ?", owner.UserID)
+	}
+	return db.Where("guest_cart_id = ?", owner.GuestCartID)
+}
+
 func (r *cartRepository) Create(cart *models.Cart) error {
 	return r.db.Create(cart).Error
 }
 
-func (r *cartRepository) GetByUserID(userID uint) ([]models.Cart, error) {
+func (r *cartRepository) GetByOwner(owner models.CartOwner) ([]models.Cart, error) {
 	var carts []models.Cart
-	err := r.db.Preload("Product").Preload("Product.Category").Where("user_id = ?", userID).Find(&carts).Error
+	err := cartsOf(r.db, owner).Preload("Product").Preload("Product.Category").Preload("LicenseTier").Find(&carts).Error
 	return carts, err
 }
 
 func (r *cartRepository) GetByID(id uint) (*models.Cart, error) {
 	var cart models.Cart
-	err := r.db.Preload("Product").First(&cart, id).Error
+	err := r.db.Preload("Product").Preload("LicenseTier").First(&cart, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &cart, nil
 }
 
-func (r *cartRepository) GetByUserAndProduct(userID, productID uint) (*models.Cart, error) {
+func (r *cartRepository) GetByOwnerAndProduct(owner models.CartOwner, productID uint, licenseTierID *uint) (*models.Cart, error) {
 	var cart models.Cart
-	err := r.db.Where("user_id = ? AND product_id = ?", userID, productID).First(&cart).Error
+	query := cartsOf(r.db, owner).Where("product_id = ?", productID)
+	if licenseTierID != nil {
+		query = query.Where("license_tier_id = ?", *licenseTierID)
+	} else {
+		query = query.Where("license_tier_id IS NULL")
+	}
+	err := query.First(&cart).Error
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +79,78 @@ func (r *cartRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Cart{}, id).Error
 }
 
-func (r *cartRepository) DeleteByUserID(userID uint) error {
-	return r.db.Where("user_id = ?", userID).Delete(&models.Cart{}).Error
+func (r *cartRepository) DeleteByOwner(owner models.CartOwner) error {
+	return cartsOf(r.db, owner).Delete(&models.Cart{}).Error
+}
+
+func (r *cartRepository) CreateGuestCart(cart *models.GuestCart) error {
+	return r.db.Create(cart).Error
+}
+
+// TouchGuestCart moves the expiry of a guest cart that has not expired yet
+// and reports whether there was one
+func (r *cartRepository) TouchGuestCart(id string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.GuestCart{}).
+		Where("id = ? AND expires_at > ?", id, time.Now()).
+		Update("expires_at", expiresAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MergeGuestCart moves a guest cart into the user's cart and removes it.
+// Products the user already has in their cart are not added twice; the
+// user's line keeps its license tier and takes the larger quantity. Of guest
+// lines for the same product in different tiers only the latest one moves.
+func (r *cartRepository) MergeGuestCart(guestCartID string, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE carts SET quantity = GREATEST(carts.quantity, guest.quantity), updated_at = NOW()
+			FROM (
+				SELECT product_id, MAX(quantity) AS quantity FROM carts
+				WHERE guest_cart_id = ? AND deleted_at IS NULL
+				GROUP BY product_id
+			) AS guest
+			WHERE carts.user_id = ? AND carts.deleted_at IS NULL AND carts.product_id = guest.product_id`,
+			guestCartID, userID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`UPDATE carts SET user_id = ?, guest_cart_id = NULL, updated_at = NOW()
+			WHERE guest_cart_id = ? AND deleted_at IS NULL AND NOT EXISTS (
+				SELECT 1 FROM carts mine
+				WHERE mine.user_id = ? AND mine.product_id = carts.product_id AND mine.deleted_at IS NULL
+			) AND id = (
+				SELECT latest.id FROM carts latest
+				WHERE latest.guest_cart_id = carts.guest_cart_id AND latest.product_id = carts.product_id AND latest.deleted_at IS NULL
+				ORDER BY latest.updated_at DESC, latest.id DESC
+				LIMIT 1
+			)`, userID, guestCartID, userID).Error
+		if err != nil {
+			return err
+		}
+
+		// Whatever is left was already in the user's cart or superseded by a later tier
+		if err := tx.Exec("DELETE FROM carts WHERE guest_cart_id = ?", guestCartID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM guest_carts WHERE id = ?", guestCartID).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteExpiredGuestCarts removes guest carts that expired before now, with their items
+func (r *cartRepository) DeleteExpiredGuestCarts(now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&models.GuestCart{}).Select("id").Where("expires_at <= ?", now)
+		if err := tx.Unscoped().Where("guest_cart_id IN (?)", expired).Delete(&models.Cart{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("expires_at <= ?", now).Delete(&models.GuestCart{})
+		count = result.RowsAffected
+		return result.Error
+	})
+	return count, err
 }
@@ -2,17 +2,58 @@ package repositories
 
 import (
 	"gin-quickstart/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// categorySubtreeSQL selects the ids of a category and all its descendants
+var categorySubtreeSQL = categorySubtreeOf("?")
+
+// categorySubtreeOf is categorySubtreeSQL for the category whose id is the SQL
+// expression idSQL, such as a column of an outer query
+func categorySubtreeOf(idSQL string) string {
+	return `WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE id = ` + idSQL + ` AND deleted_at IS NULL
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+	) SELECT id FROM subtree`
+}
+
+// inCategorySubtrees matches rows whose column holds one of the categories or
+// one of their descendants
+func inCategorySubtrees(db *gorm.DB, column string, categoryIDs []uint) *gorm.DB {
+	query := db.Where(column+" IN ("+categorySubtreeSQL+")", categoryIDs[0])
+	for _, categoryID := range categoryIDs[1:] {
+		query = query.Or(column+" IN ("+categorySubtreeSQL+")", categoryID)
+	}
+	return query
+}
+
+// categoryTreeSQL is categorySubtreeSQL with trashed categories included.
+// Cycle checks use it so a restored category cannot close a loop.
+const categoryTreeSQL = `WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE id = ?
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	) SELECT id FROM subtree`
+
 type CategoryRepository interface {
 	Create(category *models.Category) error
 	GetAll() ([]models.Category, error)
 	GetByID(id uint) (*models.Category, error)
 	GetBySlug(slug string) (*models.Category, error)
+	GetChildren(parentID *uint) ([]models.Category, error)
+	GetAncestors(ids []uint) ([]models.Category, error)
+	NextOrder(parentID *uint) (int, error)
 	Update(category *models.Category) error
-	Delete(id uint) error
+	UpdateWithSlug(category *models.Category, oldSlug string) error
+	Move(id uint, parentID *uint) (bool, error)
+	Reorder(ids []uint) error
+	GetProducts(id uint, limit int) ([]models.CategoryProduct, int64, error)
+	SubtreeProductCount(id uint) (int, error)
+	Delete(id, deletedBy uint) (bool, error)
+	DeleteReassigning(id, targetID, deletedBy uint) (bool, error)
 }
 
 type categoryRepository struct {
@@ -29,7 +70,7 @@ func (r *categoryRepository) Create(category *models.Category) error {
 
 func (r *categoryRepository) GetAll() ([]models.Category, error) {
 	var categories []models.Category
-	err := r.db.Order("created_at DESC").Find(&categories).Error
+	err := r.db.Order(`"order" ASC, name ASC`).Find(&categories).Error
 	return categories, err
 }
 
@@ -51,10 +92,222 @@ func (r *categoryRepository) GetBySlug(slug string) (*models.Category, error) {
 	return &category, nil
 }
 
+func siblings(db *gorm.DB, parentID *uint) *gorm.DB {
+	if parentID == nil {
+		return db.Where("parent_id IS NULL")
+	}
+	return db.Where("parent_id = ?", *parentID)
+}
+
+func (r *categoryRepository) GetChildren(parentID *uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := siblings(r.db, parentID).Order(`"order" ASC, name ASC`).Find(&categories).Error
+	return categories, err
+}
+
+// GetAncestors returns the categories with the given ids and every category
+// above them, up to the top level
+func (r *categoryRepository) GetAncestors(ids []uint) ([]models.Category, error) {
+	var categories []models.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := r.db.Raw(`WITH RECURSIVE ancestors AS (
+			SELECT * FROM categories WHERE id IN ? AND deleted_at IS NULL
+			UNION
+			SELECT c.* FROM categories c JOIN ancestors a ON c.id = a.parent_id WHERE c.deleted_at IS NULL
+		) SELECT * FROM ancestors`, ids).
+		Scan(&categories).Error
+	return categories, err
+}
+
+// nextOrder returns the order that places a category after its last sibling
+func nextOrder(db *gorm.DB, parentID *uint) (int, error) {
+	var order int
+	err := siblings(db.Model(&models.Category{}), parentID).
+		Select(`COALESCE(MAX("order") + 1, 0)`).
+		Scan(&order).Error
+	return order, err
+}
+
+func (r *categoryRepository) NextOrder(parentID *uint) (int, error) {
+	return nextOrder(r.db, parentID)
+}
+
 func (r *categoryRepository) Update(category *models.Category) error {
-	return r.db.Save(category).Error
+	// product_count belongs to the trigger, a stale copy must not overwrite it
+	return r.db.Omit("product_count").Save(category).Error
 }
 
-func (r *categoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Category{}, id).Error
+// UpdateWithSlug saves the category and keeps its old slug redirecting to it after a rename
+func (r *categoryRepository) UpdateWithSlug(category *models.Category, oldSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("product_count").Save(category).Error; err != nil {
+			return err
+		}
+		return changeSlug(tx, "category", oldSlug, category.Slug, category.ID)
+	})
+}
+
+// Move puts the category and its subtree last under a new parent. It reports
+// false without moving anything when the parent is the category itself or one
+// of its descendants, trashed ones included. Moves are serialized so two concurrent moves cannot
+// build a cycle together.
+func (r *categoryRepository) Move(id uint, parentID *uint) (bool, error) {
+	moved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('categories_tree'))").Error; err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			var cycle bool
+			err := tx.Raw("SELECT ? IN ("+categoryTreeSQL+")", *parentID, id).Scan(&cycle).Error
+			if err != nil || cycle {
+				return err
+			}
+		}
+
+		order, err := nextOrder(tx, parentID)
+		if err != nil {
+			return err
+		}
+
+		moved = true
+		return tx.Model(&models.Category{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"parent_id": parentID,
+			"order":     order,
+		}).Error
+	})
+	return moved, err
+}
+
+// Reorder sets the order of the given categories to their position in ids
+func (r *categoryRepository) Reorder(ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&models.Category{}).Where("id = ?", id).Update("order", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetProducts returns the first products directly in the category, whatever
+// their status, and how many there are
+func (r *categoryRepository) GetProducts(id uint, limit int) ([]models.CategoryProduct, int64, error) {
+	query := r.db.Model(&models.Product{}).Where("category_id = ?", id)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []models.CategoryProduct
+	err := query.Select("id, title, slug, status").Order("id ASC").Limit(limit).Scan(&products).Error
+	return products, total, err
+}
+
+// SubtreeProductCount sums the product counts of the category and its descendants
+func (r *categoryRepository) SubtreeProductCount(id uint) (int, error) {
+	var count int
+	err := r.db.Raw("SELECT COALESCE(SUM(product_count), 0) FROM categories WHERE id IN ("+categorySubtreeSQL+")", id).
+		Scan(&count).Error
+	return count, err
+}
+
+// Delete moves an empty category to the trash. It reports false and leaves the
+// category alone while products or subcategories still belong to it.
+func (r *categoryRepository) Delete(id, deletedBy uint) (bool, error) {
+	result := r.db.Model(&models.Category{}).
+		Where("id = ?", id).
+		Where("NOT EXISTS (SELECT 1 FROM products WHERE category_id = ? AND deleted_at IS NULL)", id).
+		Where("NOT EXISTS (SELECT 1 FROM categories c WHERE c.parent_id = ? AND c.deleted_at IS NULL)", id).
+		Updates(map[string]interface{}{
+			"deleted_by": deletedBy,
+			"deleted_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteReassigning moves the category's products, trashed ones included, and
+// its subcategories to the target category, then moves it to the trash. It
+// reports false without changing anything when the target is the category
+// itself or one of its descendants, trashed ones included.
+func (r *categoryRepository) DeleteReassigning(id, targetID, deletedBy uint) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Serialized with Move, which also changes parents
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('categories_tree'))").Error; err != nil {
+			return err
+		}
+
+		var cycle bool
+		err := tx.Raw("SELECT ? IN ("+categoryTreeSQL+")", targetID, id).Scan(&cycle).Error
+		if err != nil || cycle {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE products SET category_id = ? WHERE category_id = ?", targetID, id).Error; err != nil {
+			return err
+		}
+
+		// Subcategories keep their order, after the target's own children
+		order, err := nextOrder(tx, &targetID)
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`UPDATE categories SET parent_id = ?, "order" = "order" + ? WHERE parent_id = ? AND deleted_at IS NULL`, targetID, order, id).Error
+		if err != nil {
+			return err
+		}
+
+		if err := softDelete(tx, &models.Category{}, id, deletedBy); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// SetupCategoryProductCounts installs the trigger that keeps
+// categories.product_count equal to the number of published, not deleted
+// products in each category, and recounts every category once.
+func SetupCategoryProductCounts(db *gorm.DB) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION categories_product_count_update() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				IF OLD.status = 'published' AND OLD.deleted_at IS NULL THEN
+					UPDATE categories SET product_count = product_count - 1 WHERE id = OLD.category_id;
+				END IF;
+			END IF;
+			IF TG_OP <> 'DELETE' THEN
+				IF NEW.status = 'published' AND NEW.deleted_at IS NULL THEN
+					UPDATE categories SET product_count = product_count + 1 WHERE id = NEW.category_id;
+				END IF;
+			END IF;
+			RETURN NULL;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS categories_product_count_trigger ON products`,
+		`CREATE TRIGGER categories_product_count_trigger
+			AFTER INSERT OR DELETE OR UPDATE OF category_id, status, deleted_at ON products
+			FOR EACH ROW EXECUTE FUNCTION categories_product_count_update()`,
+		// Recount in case products changed while the trigger was missing
+		`UPDATE categories c SET product_count = (
+			SELECT COUNT(*) FROM products p
+			WHERE p.category_id = c.id AND p.status = 'published' AND p.deleted_at IS NULL
+		)`,
+	}
+
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
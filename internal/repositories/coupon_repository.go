@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponRepository interface {
+	Create(coupon *models.Coupon) error
+	GetByID(id uint) (*models.Coupon, error)
+	GetByCode(code string) (*models.Coupon, error)
+	CodeTaken(code string, excludeID uint) (bool, error)
+	GetAll(page, limit int, search string) ([]models.Coupon, int64, error)
+	Update(coupon *models.Coupon) error
+	Delete(id uint) error
+	EligibleProducts(coupon *models.Coupon, productIDs []uint) (map[uint]bool, error)
+	CountUserRedemptions(couponID, userID uint) (int64, error)
+	GetRedemptions(couponID uint, page, limit int) ([]models.CouponRedemption, int64, error)
+	GetRedemptionsBetween(from, to time.Time) ([]models.CouponRedemption, error)
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db: db}
+}
+
+func (r *couponRepository) Create(coupon *models.Coupon) error {
+	return r.db.Create(coupon).Error
+}
+
+func (r *couponRepository) GetByID(id uint) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.Preload("Products").Preload("Categories").First(&coupon, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepository) GetByCode(code string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.Where("code = ?", code).First(&coupon).Error
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// CodeTaken reports whether another coupon, deleted ones included, uses the code
+func (r *couponRepository) CodeTaken(code string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Coupon{}).
+		Where("code = ? AND id <> ?", code, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *couponRepository) GetAll(page, limit int, search string) ([]models.Coupon, int64, error) {
+	var coupons []models.Coupon
+	var total int64
+
+	query := r.db.Model(&models.Coupon{})
+	if search != "" {
+		query = query.Where("code ILIKE ?", "%"+search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Products").
+		Preload("Categories").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&coupons).Error
+	return coupons, total, err
+}
+
+// Update saves the coupon and replaces its product and category targets.
+// The usage counter is left alone, redemptions maintain it.
+func (r *couponRepository) Update(coupon *models.Coupon) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations, "used_count").Save(coupon).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(coupon).Association("Products").Replace(coupon.Products); err != nil {
+			return err
+		}
+		return tx.Model(coupon).Association("Categories").Replace(coupon.Categories)
+	})
+}
+
+func (r *couponRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Coupon{}, id).Error
+}
+
+// EligibleProducts returns which of the products fall within the coupon's scope
+func (r *couponRepository) EligibleProducts(coupon *models.Coupon, productIDs []uint) (map[uint]bool, error) {
+	eligible := make(map[uint]bool, len(productIDs))
+	if len(productIDs) == 0 {
+		return eligible, nil
+	}
+
+	var ids []uint
+	var err error
+	switch coupon.Scope {
+	case "store":
+		ids = productIDs
+	case "category":
+		// A category coupon covers the subcategories of its categories too
+		var categoryIDs []uint
+		err = r.db.Table("coupon_categories").Where("coupon_id = ?", coupon.ID).Pluck("category_id", &categoryIDs).Error
+		if err != nil || len(categoryIDs) == 0 {
+			break
+		}
+
+		err = r.db.Model(&models.Product{}).
+			Where("id IN ?", productIDs).
+			Where(inCategorySubtrees(r.db, "category_id", categoryIDs)).
+			Pluck("id", &ids).Error
+	case "product":
+		err = r.db.Table("coupon_products").
+			Where("coupon_id = ? AND product_id IN ?", coupon.ID, productIDs).
+			Pluck("product_id", &ids).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		eligible[id] = true
+	}
+	return eligible, nil
+}
+
+func (r *couponRepository) CountUserRedemptions(couponID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", couponID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *couponRepository) GetRedemptions(couponID uint, page, limit int) ([]models.CouponRedemption, int64, error) {
+	var redemptions []models.CouponRedemption
+	var total int64
+
+	query := r.db.Model(&models.CouponRedemption{}).Where("coupon_id = ?", couponID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("User").
+		Preload("Order").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&redemptions).Error
+	return redemptions, total, err
+}
+
+// GetRedemptionsBetween lists the redemptions made in [from, to) with their coupon and order
+func (r *couponRepository) GetRedemptionsBetween(from, to time.Time) ([]models.CouponRedemption, error) {
+	var redemptions []models.CouponRedemption
+	err := r.db.Preload("Coupon", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Order").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("id ASC").
+		Find(&redemptions).Error
+	return redemptions, err
+}
@@ -28,7 +28,7 @@ func (r *downloadRepository) Create(download *models.Download) error {
 
 func (r *downloadRepository) GetByID(id uint) (*models.Download, error) {
 	var download models.Download
-	err := r.db.Preload("User").Preload("Product").First(&download, id).Error
+	err := r.db.Preload("User").Preload("Product").Preload("LicenseTier").First(&download, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (r *downloadRepository) GetByUserID(userID uint, page, limit int) ([]models
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	err := query.Preload("Product").Preload("Product.Category").
+	err := query.Preload("Product").Preload("Product.Category").Preload("LicenseTier").
 		Offset(offset).Limit(limit).
 		Order("created_at DESC").
 		Find(&downloads).Error
@@ -53,7 +53,7 @@ func (r *downloadRepository) GetByUserID(userID uint, page, limit int) ([]models
 
 func (r *downloadRepository) GetByUserAndProduct(userID, productID uint) ([]models.Download, error) {
 	var downloads []models.Download
-	err := r.db.Where("user_id = ? AND product_id = ?", userID, productID).
+	err := r.db.Preload("LicenseTier").Where("user_id = ? AND product_id = ?", userID, productID).
 		Order("created_at DESC").
 		Find(&downloads).Error
 	return downloads, err
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepository interface {
+	Create(rate *models.ExchangeRate) error
+	GetByID(id uint) (*models.ExchangeRate, error)
+	GetByCurrency(currency string, page, limit int) ([]models.ExchangeRate, int64, error)
+	GetEffective(currency string, at time.Time) (*models.ExchangeRate, error)
+	GetAllEffective(at time.Time) ([]models.ExchangeRate, error)
+	Delete(id uint) error
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) Create(rate *models.ExchangeRate) error {
+	return r.db.Create(rate).Error
+}
+
+func (r *exchangeRateRepository) GetByID(id uint) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.First(&rate, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// GetByCurrency lists rates newest first, optionally for a single currency
+func (r *exchangeRateRepository) GetByCurrency(currency string, page, limit int) ([]models.ExchangeRate, int64, error) {
+	var rates []models.ExchangeRate
+	var total int64
+
+	query := r.db.Model(&models.ExchangeRate{})
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("effective_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&rates).Error
+	return rates, total, err
+}
+
+// GetEffective returns the rate of a currency in effect at the given time
+func (r *exchangeRateRepository) GetEffective(currency string, at time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.Where("currency = ? AND effective_at <= ?", currency, at).
+		Order("effective_at DESC, id DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// GetAllEffective returns the rate in effect at the given time for every currency that has one
+func (r *exchangeRateRepository) GetAllEffective(at time.Time) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Raw(`SELECT DISTINCT ON (currency) *
+		FROM exchange_rates
+		WHERE deleted_at IS NULL AND effective_at <= ?
+		ORDER BY currency, effective_at DESC, id DESC`, at).Scan(&rates).Error
+	return rates, err
+}
+
+func (r *exchangeRateRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ExchangeRate{}, id).Error
+}
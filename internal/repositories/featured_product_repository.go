@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FeaturedProductRepository interface {
+	Create(slot *models.FeaturedProduct) error
+	GetByID(id uint) (*models.FeaturedProduct, error)
+	GetAll() ([]models.FeaturedProduct, error)
+	Update(slot *models.FeaturedProduct) error
+	Delete(id uint) error
+	CountOverlappingInCategory(categoryID uint, startsAt, endsAt *time.Time, excludeID uint) (int64, error)
+	CountOverlappingForProduct(productID uint, startsAt, endsAt *time.Time, excludeID uint) (int64, error)
+	GetLiveProducts(limit, perCategory int, at time.Time) ([]models.Product, error)
+}
+
+type featuredProductRepository struct {
+	db *gorm.DB
+}
+
+func NewFeaturedProductRepository(db *gorm.DB) FeaturedProductRepository {
+	return &featuredProductRepository{db: db}
+}
+
+func (r *featuredProductRepository) Create(slot *models.FeaturedProduct) error {
+	return r.db.Create(slot).Error
+}
+
+func (r *featuredProductRepository) GetByID(id uint) (*models.FeaturedProduct, error) {
+	var slot models.FeaturedProduct
+	err := r.db.Preload("Product").First(&slot, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *featuredProductRepository) GetAll() ([]models.FeaturedProduct, error) {
+	var slots []models.FeaturedProduct
+	err := r.db.Preload("Product").Preload("Product.Category").
+		Order("position ASC, id ASC").
+		Find(&slots).Error
+	return slots, err
+}
+
+func (r *featuredProductRepository) Update(slot *models.FeaturedProduct) error {
+	return r.db.Save(slot).Error
+}
+
+func (r *featuredProductRepository) Delete(id uint) error {
+	return r.db.Delete(&models.FeaturedProduct{}, id).Error
+}
+
+// CountOverlappingInCategory counts slots for products in the category whose
+// schedule overlaps the given window. Nil bounds are treated as open-ended.
+func (r *featuredProductRepository) CountOverlappingInCategory(categoryID uint, startsAt, endsAt *time.Time, excludeID uint) (int64, error) {
+	var count int64
+
+	query := r.db.Model(&models.FeaturedProduct{}).
+		Joins("JOIN products ON products.id = featured_products.product_id AND products.deleted_at IS NULL").
+		Where("products.category_id = ?", categoryID).
+		Where("featured_products.id <> ?", excludeID)
+
+	err := overlappingSlots(query, startsAt, endsAt).Count(&count).Error
+	return count, err
+}
+
+// CountOverlappingForProduct counts the product's other slots whose schedule
+// overlaps the given window. Nil bounds are treated as open-ended.
+func (r *featuredProductRepository) CountOverlappingForProduct(productID uint, startsAt, endsAt *time.Time, excludeID uint) (int64, error) {
+	var count int64
+
+	query := r.db.Model(&models.FeaturedProduct{}).
+		Where("featured_products.product_id = ?", productID).
+		Where("featured_products.id <> ?", excludeID)
+
+	err := overlappingSlots(query, startsAt, endsAt).Count(&count).Error
+	return count, err
+}
+
+// overlappingSlots keeps the slots whose schedule overlaps the window
+func overlappingSlots(query *gorm.DB, startsAt, endsAt *time.Time) *gorm.DB {
+	if startsAt != nil {
+		query = query.Where("featured_products.ends_at IS NULL OR featured_products.ends_at > ?", *startsAt)
+	}
+	if endsAt != nil {
+		query = query.Where("featured_products.starts_at IS NULL OR featured_products.starts_at < ?", *endsAt)
+	}
+	return query
+}
+
+// GetLiveProducts returns the products of slots live at the given time, in slot
+// position order, keeping at most perCategory products from each category. A
+// product with several live slots is listed once, at its first slot.
+func (r *featuredProductRepository) GetLiveProducts(limit, perCategory int, at time.Time) ([]models.Product, error) {
+	var ids []uint
+
+	live := r.db.Model(&models.FeaturedProduct{}).
+		Select("DISTINCT ON (featured_products.product_id) featured_products.product_id, featured_products.position, featured_products.id, products.category_id").
+		Joins("JOIN products ON products.id = featured_products.product_id AND products.deleted_at IS NULL").
+		Where("products.status = ?", "published").
+		Where("featured_products.starts_at IS NULL OR featured_products.starts_at <= ?", at).
+		Where("featured_products.ends_at IS NULL OR featured_products.ends_at > ?", at).
+		Order("featured_products.product_id, featured_products.position, featured_products.id")
+
+	ranked := r.db.Table("(?) AS live", live).
+		Select("product_id, position, id, " +
+			"ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY position, id) AS category_rank")
+
+	err := r.db.Table("(?) AS ranked", ranked).
+		Where("category_rank <= ?", perCategory).
+		Order("position ASC, id ASC").
+		Limit(limit).
+		Pluck("product_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return []models.Product{}, nil
+	}
+
+	var products []models.Product
+	if err := r.db.Preload("Category").Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	// Restore slot order
+	byID := make(map[uint]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	ordered := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			ordered = append(ordered, p)
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LicenseKeyRepository interface {
+	Issue(license *models.LicenseKey, replaceActive bool, sign func(*models.LicenseKey) string) (bool, error)
+	GetByID(id uint) (*models.LicenseKey, error)
+	GetByKey(key string) (*models.LicenseKey, error)
+	GetByUserID(userID uint) ([]models.LicenseKey, error)
+	Update(license *models.LicenseKey) error
+	GetActivation(licenseID uint, fingerprint string) (*models.LicenseActivation, error)
+	CountActivations(licenseID uint) (int64, error)
+	CreateActivation(activation *models.LicenseActivation, limit int) (bool, error)
+	TouchActivation(id uint, at time.Time) error
+	DeleteActivation(licenseID, activationID uint) (bool, error)
+}
+
+type licenseKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewLicenseKeyRepository(db *gorm.DB) LicenseKeyRepository {
+	return &licenseKeyRepository{db: db}
+}
+
+// Issue creates the license and stores its signed key. The key embeds the
+// license ID, so the row is inserted first under a placeholder key. It returns
+// false when the order line already has a key for the product. With
+// replaceActive the user's other active keys for the product are retired,
+// e.g. after a license upgrade.
+func (r *licenseKeyRepository) Issue(license *models.LicenseKey, replaceActive bool, sign func(*models.LicenseKey) string) (bool, error) {
+	issued := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		license.Key = "pending-" + uuid.NewString()
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_item_id"}, {Name: "product_id"}},
+			DoNothing: true,
+		}).Create(license)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if replaceActive {
+			err := tx.Model(&models.LicenseKey{}).
+				Where("user_id = ? AND product_id = ? AND status = ? AND id <> ?", license.UserID, license.ProductID, "active", license.ID).
+				Update("status", "replaced").Error
+			if err != nil {
+				return err
+			}
+		}
+
+		license.Key = sign(license)
+		if err := tx.Model(license).Update("key", license.Key).Error; err != nil {
+			return err
+		}
+		issued = true
+		return nil
+	})
+	return issued, err
+}
+
+func (r *licenseKeyRepository) GetByID(id uint) (*models.LicenseKey, error) {
+	var license models.LicenseKey
+	err := r.db.Preload("Activations").First(&license, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
+
+func (r *licenseKeyRepository) GetByKey(key string) (*models.LicenseKey, error) {
+	var license models.LicenseKey
+	err := r.db.Where("key = ?", key).First(&license).Error
+	if err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
+
+func (r *licenseKeyRepository) GetByUserID(userID uint) ([]models.LicenseKey, error) {
+	var licenses []models.LicenseKey
+	err := r.db.Preload("Product").
+		Preload("LicenseTier").
+		Preload("Activations", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&licenses).Error
+	return licenses, err
+}
+
+func (r *licenseKeyRepository) Update(license *models.LicenseKey) error {
+	return r.db.Omit("Activations", "Product", "LicenseTier").Save(license).Error
+}
+
+func (r *licenseKeyRepository) GetActivation(licenseID uint, fingerprint string) (*models.LicenseActivation, error) {
+	var activation models.LicenseActivation
+	err := r.db.Where("license_key_id = ? AND fingerprint = ?", licenseID, fingerprint).First(&activation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &activation, nil
+}
+
+func (r *licenseKeyRepository) CountActivations(licenseID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LicenseActivation{}).Where("license_key_id = ?", licenseID).Count(&count).Error
+	return count, err
+}
+
+// CreateActivation adds the activation unless the license already has limit
+// activations. The license row is locked so concurrent activations cannot
+// both take the last slot.
+func (r *licenseKeyRepository) CreateActivation(activation *models.LicenseActivation, limit int) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var license models.LicenseKey
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&license, activation.LicenseKeyID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.LicenseActivation{}).Where("license_key_id = ?", license.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(limit) {
+			return nil
+		}
+
+		created = true
+		return tx.Create(activation).Error
+	})
+	return created, err
+}
+
+func (r *licenseKeyRepository) TouchActivation(id uint, at time.Time) error {
+	return r.db.Model(&models.LicenseActivation{}).Where("id = ?", id).Update("last_validated_at", at).Error
+}
+
+// DeleteActivation removes an activation of the license and reports whether one existed
+func (r *licenseKeyRepository) DeleteActivation(licenseID, activationID uint) (bool, error) {
+	result := r.db.Where("license_key_id = ?", licenseID).Delete(&models.LicenseActivation{}, activationID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetupLicenseKeys links keys issued before per-line issuance to their order
+// line, so issuing an order's missing keys again does not duplicate them.
+// Only the first key of each order and product is linked.
+func SetupLicenseKeys(db *gorm.DB) error {
+	return db.Exec(`UPDATE license_keys lk
+		SET order_item_id = (
+			SELECT MIN(i.id) FROM order_items i
+			WHERE i.order_id = lk.order_id
+				AND (i.product_id = lk.product_id OR EXISTS (
+					SELECT 1 FROM order_item_products oip
+					WHERE oip.order_item_id = i.id AND oip.product_id = lk.product_id)))
+		WHERE lk.order_item_id IS NULL
+			AND lk.id = (SELECT MIN(k.id) FROM license_keys k
+				WHERE k.order_id = lk.order_id AND k.product_id = lk.product_id)`).Error
+}
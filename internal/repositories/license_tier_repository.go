@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type LicenseTierRepository interface {
+	Create(tier *models.LicenseTier) error
+	GetByID(id uint) (*models.LicenseTier, error)
+	GetByProductID(productID uint) ([]models.LicenseTier, error)
+	GetByProductAndName(productID uint, name string) (*models.LicenseTier, error)
+	Update(tier *models.LicenseTier) error
+	Delete(id uint) error
+	GetOwnedTiers(userID, productID uint) ([]models.LicenseTier, error)
+}
+
+type licenseTierRepository struct {
+	db *gorm.DB
+}
+
+func NewLicenseTierRepository(db *gorm.DB) LicenseTierRepository {
+	return &licenseTierRepository{db: db}
+}
+
+func (r *licenseTierRepository) Create(tier *models.LicenseTier) error {
+	return r.db.Create(tier).Error
+}
+
+func (r *licenseTierRepository) GetByID(id uint) (*models.LicenseTier, error) {
+	var tier models.LicenseTier
+	err := r.db.First(&tier, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func (r *licenseTierRepository) GetByProductID(productID uint) ([]models.LicenseTier, error) {
+	var tiers []models.LicenseTier
+	err := r.db.Where("product_id = ?", productID).Order("price ASC").Find(&tiers).Error
+	return tiers, err
+}
+
+func (r *licenseTierRepository) GetByProductAndName(productID uint, name string) (*models.LicenseTier, error) {
+	var tier models.LicenseTier
+	err := r.db.Where("product_id = ? AND name = ?", productID, name).First(&tier).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func (r *licenseTierRepository) Update(tier *models.LicenseTier) error {
+	return r.db.Save(tier).Error
+}
+
+func (r *licenseTierRepository) Delete(id uint) error {
+	return r.db.Delete(&models.LicenseTier{}, id).Error
+}
+
+// GetOwnedTiers returns the license tiers of the user's paid order items for a product
+func (r *licenseTierRepository) GetOwnedTiers(userID, productID uint) ([]models.LicenseTier, error) {
+	var tiers []models.LicenseTier
+	err := r.db.Unscoped().
+		Where("id IN (?)", r.db.Model(&models.OrderItem{}).
+			Select("order_items.license_tier_id").
+			Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+			Where("orders.user_id = ? AND orders.payment_status = ?", userID, "paid").
+			Where("order_items.product_id = ? AND order_items.license_tier_id IS NOT NULL", productID)).
+		Find(&tiers).Error
+	return tiers, err
+}
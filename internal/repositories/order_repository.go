@@ -1,20 +1,30 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
 	"gin-quickstart/internal/models"
+	"sort"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository interface {
-	Create(order *models.Order) error
+	Place(order *models.Order, transaction *models.Transaction, cartIDs []uint) (bool, error)
 	GetByID(id uint) (*models.Order, error)
 	GetByUserID(userID uint, page, limit int) ([]models.Order, int64, error)
 	GetAll(page, limit int, status string) ([]models.Order, int64, error)
 	Update(order *models.Order) error
 	Delete(id uint) error
 	GetByOrderNumber(orderNumber string) (*models.Order, error)
-	HasUserPurchasedProduct(userID, productID uint) (bool, error)
+	HasPaidAccess(userID, productID uint) (bool, error)
+	GetPaidProductOrders(userID, productID uint) ([]models.Order, error)
+	HasOrders(userID uint) (bool, error)
+	Cancel(order *models.Order) error
+	ExpirePending(before time.Time) (int64, error)
 }
 
 type orderRepository struct {
@@ -25,13 +35,181 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
 
-func (r *orderRepository) Create(order *models.Order) error {
-	return r.db.Create(order).Error
+// Place stores a new order with its items, coupon redemption and payment
+// transaction, all or nothing. A user's placements are serialized so coupon
+// limits and first-purchase rules hold under concurrent checkouts. With
+// cartIDs the checked out cart rows are removed too; Place reports false
+// without creating anything when some of them were removed in the meantime,
+// so a cart checked out twice at once becomes a single order.
+func (r *orderRepository) Place(order *models.Order, transaction *models.Transaction, cartIDs []uint) (bool, error) {
+	placed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('orders_user'), ?::int)", order.UserID).Error; err != nil {
+			return err
+		}
+
+		if len(cartIDs) > 0 {
+			var locked []uint
+			err := tx.Model(&models.Cart{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id IN ? AND user_id = ?", cartIDs, order.UserID).
+				Pluck("id", &locked).Error
+			if err != nil || len(locked) != len(cartIDs) {
+				return err
+			}
+		}
+
+		if order.CouponID != nil {
+			if err := reserveCoupon(tx, order); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		if order.CouponID != nil {
+			redemption := &models.CouponRedemption{
+				CouponID:       *order.CouponID,
+				UserID:         order.UserID,
+				OrderID:        order.ID,
+				DiscountAmount: order.DiscountAmount,
+				Currency:       order.Currency,
+			}
+			if err := tx.Create(redemption).Error; err != nil {
+				return err
+			}
+		}
+
+		transaction.OrderID = order.ID
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+
+		if len(cartIDs) > 0 {
+			if err := tx.Where("id IN ?", cartIDs).Delete(&models.Cart{}).Error; err != nil {
+				return err
+			}
+		}
+
+		placed = true
+		return nil
+	})
+	return placed, err
+}
+
+// reserveCoupon takes one use of the order's coupon, checking it is still
+// valid and within its limits on the locked coupon row
+func reserveCoupon(tx *gorm.DB, order *models.Order) error {
+	var coupon models.Coupon
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, *order.CouponID).Error
+	if err != nil || !coupon.IsActive {
+		return errors.New("coupon not found")
+	}
+
+	now := time.Now()
+	if (coupon.StartsAt != nil && now.Before(*coupon.StartsAt)) || (coupon.EndsAt != nil && !now.Before(*coupon.EndsAt)) {
+		return errors.New("coupon is not valid at this time")
+	}
+
+	if coupon.UsageLimit != nil && coupon.UsedCount >= *coupon.UsageLimit {
+		return errors.New("coupon usage limit has been reached")
+	}
+
+	if coupon.PerUserLimit != nil {
+		var used int64
+		err := tx.Model(&models.CouponRedemption{}).
+			Where("coupon_id = ? AND user_id = ?", coupon.ID, order.UserID).
+			Count(&used).Error
+		if err != nil {
+			return err
+		}
+		if used >= int64(*coupon.PerUserLimit) {
+			return errors.New("you have already used this coupon")
+		}
+	}
+
+	if coupon.FirstPurchaseOnly {
+		var ordered int64
+		err := tx.Model(&models.Order{}).
+			Where("user_id = ? AND status <> ?", order.UserID, "cancelled").
+			Count(&ordered).Error
+		if err != nil {
+			return err
+		}
+		if ordered > 0 {
+			return errors.New("coupon is only valid on your first purchase")
+		}
+	}
+
+	return tx.Model(&coupon).UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+// Cancel saves the cancelled order and gives back its coupon use in one transaction
+func (r *orderRepository) Cancel(order *models.Order) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		return releaseCoupons(tx, []uint{order.ID})
+	})
+}
+
+// ExpirePending cancels orders still waiting for a payment proof since before
+// the cutoff and gives back their coupon uses. Orders with a proof under
+// review are left to the admins.
+func (r *orderRepository) ExpirePending(before time.Time) (int64, error) {
+	var expired []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var orders []models.Order
+		err := tx.Model(&orders).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("status = ? AND payment_status = ? AND created_at < ?", "pending", "pending", before).
+			Updates(map[string]interface{}{"status": "cancelled", "payment_status": "expired"}).Error
+		if err != nil || len(orders) == 0 {
+			return err
+		}
+
+		for _, order := range orders {
+			expired = append(expired, order.ID)
+		}
+		return releaseCoupons(tx, expired)
+	})
+	return int64(len(expired)), err
+}
+
+// releaseCoupons removes the coupon redemptions of the orders and gives their
+// uses back. Only deleted redemptions are counted, so releasing twice is harmless.
+func releaseCoupons(tx *gorm.DB, orderIDs []uint) error {
+	var redemptions []models.CouponRedemption
+	err := tx.Clauses(clause.Returning{}).Where("order_id IN ?", orderIDs).Delete(&redemptions).Error
+	if err != nil {
+		return err
+	}
+
+	for _, redemption := range redemptions {
+		err := tx.Model(&models.Coupon{}).Where("id = ? AND used_count > 0", redemption.CouponID).
+			UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasOrders reports whether the user placed an order that was not cancelled
+func (r *orderRepository) HasOrders(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Order{}).
+		Where("user_id = ? AND status <> ?", userID, "cancelled").
+		Count(&count).Error
+	return count > 0, err
 }
 
 func (r *orderRepository) GetByID(id uint) (*models.Order, error) {
 	var order models.Order
-	err := r.db.Preload("User").Preload("Product").Preload("Product.Category").First(&order, id).Error
+	err := r.db.Preload("User").Preload("Product").Preload("Product.Category").Preload("LicenseTier").Preload("Bundle").Preload("Bundle.Products").
+		Preload("Items").Preload("Items.Product").Preload("Items.LicenseTier").Preload("Items.Bundle").Preload("Items.BundleProducts.Product").Preload("Taxes").
+		First(&order, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +224,8 @@ func (r *orderRepository) GetByUserID(userID uint, page, limit int) ([]models.Or
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	err := query.Preload("Product").Preload("Product.Category").
+	err := query.Preload("Product").Preload("Product.Category").Preload("LicenseTier").Preload("Bundle").
+		Preload("Items").Preload("Items.Product").Preload("Items.LicenseTier").Preload("Items.Bundle").Preload("Taxes").
 		Offset(offset).Limit(limit).
 		Order("created_at DESC").
 		Find(&orders).Error
@@ -67,7 +246,8 @@ func (r *orderRepository) GetAll(page, limit int, status string) ([]models.Order
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	err := query.Preload("User").Preload("Product").Preload("Product.Category").
+	err := query.Preload("User").Preload("Product").Preload("Product.Category").Preload("LicenseTier").Preload("Bundle").
+		Preload("Items").Preload("Items.Product").Preload("Items.LicenseTier").Preload("Items.Bundle").Preload("Taxes").
 		Offset(offset).Limit(limit).
 		Order("created_at DESC").
 		Find(&orders).Error
@@ -85,17 +265,79 @@ func (r *orderRepository) Delete(id uint) error {
 
 func (r *orderRepository) GetByOrderNumber(orderNumber string) (*models.Order, error) {
 	var order models.Order
-	err := r.db.Preload("User").Preload("Product").Where("order_number = ?", orderNumber).First(&order).Error
+	err := r.db.Preload("User").Preload("Product").Preload("LicenseTier").Preload("Items").Where("order_number = ?", orderNumber).First(&order).Error
 	if err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
-func (r *orderRepository) HasUserPurchasedProduct(userID, productID uint) (bool, error) {
+// HasPaidAccess reports whether the user has a paid order with an item for
+// the product, either bought directly or in a bundle as it was at checkout.
+func (r *orderRepository) HasPaidAccess(userID, productID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Order{}).
-		Where("user_id = ? AND product_id = ? AND order_status = ?", userID, productID, "completed").
+	err := r.db.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.user_id = ? AND orders.payment_status = ?", userID, "paid").
+		Where("order_items.product_id = ? OR EXISTS (SELECT 1 FROM order_item_products oip WHERE oip.order_item_id = order_items.id AND oip.product_id = ?)", productID, productID).
 		Count(&count).Error
 	return count > 0, err
 }
+
+// GetPaidProductOrders returns the user's paid orders that bought the product
+// directly, license upgrades included, oldest first. Only the product's lines
+// are loaded, with their license tiers; bundle lines are left out.
+func (r *orderRepository) GetPaidProductOrders(userID, productID uint) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.
+		Where("user_id = ? AND payment_status = ?", userID, "paid").
+		Where("EXISTS (SELECT 1 FROM order_items oi WHERE oi.order_id = orders.id AND oi.product_id = ?)", productID).
+		Preload("Items", "product_id = ?", productID).
+		Preload("Items.LicenseTier", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("orders.id").
+		Find(&orders).Error
+	return orders, err
+}
+
+// SetupOrderItems gives orders placed before order items existed the item
+// they bought, so every order can be read through its items. The unit price
+// is recovered in the list currency from the locked exchange rate. Bundle
+// lines bought before their contents were recorded get the bundle's current
+// products, the best record left of what they granted.
+func SetupOrderItems(db *gorm.DB) error {
+	codes := make([]string, 0, len(models.Currencies))
+	for code := range models.Currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	digits := make([]string, len(codes))
+	for i, code := range codes {
+		digits[i] = fmt.Sprintf("('%s', %d)", code, models.Currencies[code])
+	}
+	currencies := "(VALUES " + strings.Join(digits, ", ") + ")"
+
+	err := db.Exec(`INSERT INTO order_items
+			(order_id, product_id, bundle_id, license_tier_id, title, quantity, list_currency, unit_price, exchange_rate, amount, created_at)
+		SELECT o.id, o.product_id, o.bundle_id, o.license_tier_id,
+			COALESCE(p.title, b.title, ''), GREATEST(o.quantity, 1), o.list_currency,
+			COALESCE(ROUND(o.total_amount / NULLIF(o.exchange_rate, 0)
+				* power(10, COALESCE(lc.digits, 0) - COALESCE(oc.digits, 0)) / GREATEST(o.quantity, 1)), 0),
+			o.exchange_rate, o.total_amount, o.created_at
+		FROM orders o
+		LEFT JOIN products p ON p.id = o.product_id
+		LEFT JOIN bundles b ON b.id = o.bundle_id
+		LEFT JOIN ` + currencies + ` AS lc(code, digits) ON lc.code = o.list_currency
+		LEFT JOIN ` + currencies + ` AS oc(code, digits) ON oc.code = o.currency
+		WHERE (o.product_id IS NOT NULL OR o.bundle_id IS NOT NULL)
+			AND NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id)`).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(`INSERT INTO order_item_products (order_item_id, product_id)
+		SELECT i.id, bi.product_id
+		FROM order_items i
+		JOIN bundle_items bi ON bi.bundle_id = i.bundle_id
+		WHERE NOT EXISTS (SELECT 1 FROM order_item_products oip WHERE oip.order_item_id = i.id)`).Error
+}
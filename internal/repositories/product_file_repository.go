@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ProductFileRepository interface {
+	ReplaceDeliverable(productID uint, fileURL string, files []models.ProductFile) error
+	GetByProductID(productID uint) ([]models.ProductFile, error)
+	GetByProductAndPath(productID uint, path string) (*models.ProductFile, error)
+	SetPreviewable(productID uint, paths []string, previewable bool) (int64, error)
+}
+
+type productFileRepository struct {
+	db *gorm.DB
+}
+
+func NewProductFileRepository(db *gorm.DB) ProductFileRepository {
+	return &productFileRepository{db: db}
+}
+
+// ReplaceDeliverable points the product at a new deliverable and swaps its
+// file index for the freshly indexed archive in one transaction, so the tree
+// always describes the stored file. Files that keep their path also keep
+// their previewable flag.
+func (r *productFileRepository) ReplaceDeliverable(productID uint, fileURL string, files []models.ProductFile) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("file_url", fileURL).Error; err != nil {
+			return err
+		}
+
+		var previewable []string
+		if err := tx.Model(&models.ProductFile{}).
+			Where("product_id = ? AND is_previewable = ?", productID, true).
+			Pluck("path", &previewable).Error; err != nil {
+			return err
+		}
+		keep := make(map[string]bool, len(previewable))
+		for _, p := range previewable {
+			keep[p] = true
+		}
+
+		if err := tx.Where("product_id = ?", productID).Delete(&models.ProductFile{}).Error; err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return nil
+		}
+
+		for i := range files {
+			files[i].ProductID = productID
+			files[i].IsPreviewable = keep[files[i].Path]
+		}
+		return tx.CreateInBatches(files, 500).Error
+	})
+}
+
+func (r *productFileRepository) GetByProductID(productID uint) ([]models.ProductFile, error) {
+	var files []models.ProductFile
+	err := r.db.Where("product_id = ?", productID).Order("path ASC").Find(&files).Error
+	return files, err
+}
+
+func (r *productFileRepository) GetByProductAndPath(productID uint, path string) (*models.ProductFile, error) {
+	var file models.ProductFile
+	err := r.db.Where("product_id = ? AND path = ?", productID, path).First(&file).Error
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *productFileRepository) SetPreviewable(productID uint, paths []string, previewable bool) (int64, error) {
+	result := r.db.Model(&models.ProductFile{}).
+		Where("product_id = ? AND path IN ?", productID, paths).
+		Update("is_previewable", previewable)
+	return result.RowsAffected, result.Error
+}
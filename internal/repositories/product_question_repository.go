@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductQuestionRepository interface {
+	Create(question *models.ProductQuestion) error
+	GetByID(id uint) (*models.ProductQuestion, error)
+	GetByProductID(productID uint, sort string, limit, offset int) ([]models.ProductQuestion, int64, error)
+	GetByStatus(status string, limit, offset int) ([]models.ProductQuestion, int64, error)
+	Update(question *models.ProductQuestion) error
+	Delete(id uint) error
+	CreateAnswer(answer *models.ProductAnswer) error
+	GetAnswerByID(id uint) (*models.ProductAnswer, error)
+	DeleteAnswer(answer *models.ProductAnswer) error
+	AddVote(questionID, userID uint) (bool, error)
+	RemoveVote(questionID, userID uint) (bool, error)
+}
+
+type productQuestionRepository struct {
+	db *gorm.DB
+}
+
+func NewProductQuestionRepository(db *gorm.DB) ProductQuestionRepository {
+	return &productQuestionRepository{db: db}
+}
+
+func (r *productQuestionRepository) Create(question *models.ProductQuestion) error {
+	return r.db.Create(question).Error
+}
+
+func (r *productQuestionRepository) GetByID(id uint) (*models.ProductQuestion, error) {
+	var question models.ProductQuestion
+	err := r.db.Preload("User").
+		Preload("Product").
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Answers.User").
+		First(&question, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// GetByProductID lists approved questions; sort is "top" (most upvoted) or "newest"
+func (r *productQuestionRepository) GetByProductID(productID uint, sort string, limit, offset int) ([]models.ProductQuestion, int64, error) {
+	var questions []models.ProductQuestion
+	var total int64
+
+	query := r.db.Model(&models.ProductQuestion{}).Where("product_id = ? AND status = ?", productID, "approved")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "upvotes_count DESC, answers_count DESC, created_at DESC"
+	if sort == "newest" {
+		order = "created_at DESC"
+	}
+
+	err := query.Preload("User").
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Answers.User").
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *productQuestionRepository) GetByStatus(status string, limit, offset int) ([]models.ProductQuestion, int64, error) {
+	var questions []models.ProductQuestion
+	var total int64
+
+	query := r.db.Model(&models.ProductQuestion{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Preload("Product").
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *productQuestionRepository) Update(question *models.ProductQuestion) error {
+	return r.db.Omit(clause.Associations).Save(question).Error
+}
+
+func (r *productQuestionRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("question_id = ?", id).Delete(&models.ProductAnswer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&models.ProductQuestionVote{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProductQuestion{}, id).Error
+	})
+}
+
+// CreateAnswer stores the answer and bumps the question's answer count in one transaction
+func (r *productQuestionRepository) CreateAnswer(answer *models.ProductAnswer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(answer).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ProductQuestion{}).
+			Where("id = ?", answer.QuestionID).
+			UpdateColumn("answers_count", gorm.Expr("answers_count + 1")).Error
+	})
+}
+
+func (r *productQuestionRepository) GetAnswerByID(id uint) (*models.ProductAnswer, error) {
+	var answer models.ProductAnswer
+	err := r.db.First(&answer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *productQuestionRepository) DeleteAnswer(answer *models.ProductAnswer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ProductAnswer{}, answer.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ProductQuestion{}).
+			Where("id = ? AND answers_count > 0", answer.QuestionID).
+			UpdateColumn("answers_count", gorm.Expr("answers_count - 1")).Error
+	})
+}
+
+// AddVote upvotes a question once per user; it reports false when the vote already existed
+func (r *productQuestionRepository) AddVote(questionID, userID uint) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.ProductQuestionVote{QuestionID: questionID, UserID: userID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		added = true
+		return tx.Model(&models.ProductQuestion{}).
+			Where("id = ?", questionID).
+			UpdateColumn("upvotes_count", gorm.Expr("upvotes_count + 1")).Error
+	})
+	return added, err
+}
+
+func (r *productQuestionRepository) RemoveVote(questionID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("question_id = ? AND user_id = ?", questionID, userID).
+			Delete(&models.ProductQuestionVote{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		removed = true
+		return tx.Model(&models.ProductQuestion{}).
+			Where("id = ? AND upvotes_count > 0", questionID).
+			UpdateColumn("upvotes_count", gorm.Expr("upvotes_count - 1")).Error
+	})
+	return removed, err
+}
@@ -1,20 +1,118 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
 	"gin-quickstart/internal/models"
+	"sort"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// searchConfig is the text search configuration used for product search.
+// "simple" is used because the catalog mixes Indonesian and English content.
+const searchConfig = "simple"
+
+// regularPriceSQL is the product's price outside of sale campaigns
+const regularPriceSQL = "CASE WHEN products.discount_price > 0 THEN products.discount_price ELSE products.price END"
+
+// campaignPriceSQL is the price under the winning active sale campaign, or NULL.
+// It mirrors applyCampaigns in the services package: highest priority first,
+// then lowest resulting price, then the oldest campaign. Category campaigns
+// cover the subcategories of their categories too.
+var campaignPriceSQL = `(SELECT CASE WHEN c.discount_type = 'percentage'
+		THEN (products.price * (100 - c.discount_value) + 50) / 100
+		ELSE GREATEST(products.price - c.discount_value, 0) END AS sale_price
+	FROM sale_campaigns c
+	WHERE c.deleted_at IS NULL AND c.is_active AND c.starts_at <= NOW() AND c.ends_at > NOW()
+		AND (c.discount_type = 'percentage' OR c.currency = products.currency)
+		AND (c.scope = 'store'
+			OR (c.scope = 'category' AND EXISTS (SELECT 1 FROM sale_campaign_categories scc WHERE scc.sale_campaign_id = c.id
+				AND products.category_id IN (` + categorySubtreeOf("scc.category_id") + `)))
+			OR (c.scope = 'product' AND EXISTS (SELECT 1 FROM sale_campaign_products scp WHERE scp.sale_campaign_id = c.id AND scp.product_id = products.id)))
+	ORDER BY c.priority DESC, sale_price ASC, c.id ASC
+	LIMIT 1)`
+
+// effectivePriceSQL is the price a buyer actually pays for a product, in the product's currency.
+var effectivePriceSQL = "LEAST(" + regularPriceSQL + ", COALESCE(" + campaignPriceSQL + ", " + regularPriceSQL + "))"
+
+// basePriceSQL is effectivePriceSQL in minor units of the base currency, so
+// products listed in different currencies can be filtered and sorted together.
+// It is NULL for products whose currency has no exchange rate yet.
+var basePriceSQL = "(" + effectivePriceSQL + " * " + baseRateSQL() + ")"
+
+// techStackSQL expands the tech_stack jsonb column, tolerating rows where it is not an array.
+const techStackSQL = "jsonb_array_elements_text(CASE WHEN jsonb_typeof(products.tech_stack) = 'array' THEN products.tech_stack ELSE '[]'::jsonb END)"
+
+// productSortOrders whitelists the sort keys accepted from clients
+var productSortOrders = map[string]string{
+	"newest":     "products.created_at DESC, products.id DESC",
+	"price_asc":  basePriceSQL + " ASC NULLS LAST, products.id DESC",
+	"price_desc": basePriceSQL + " DESC NULLS LAST, products.id DESC",
+	"popular":    "products.downloads_count DESC, products.id DESC",
+	"rating":     "products.rating_average DESC, products.id DESC",
+}
+
+// IsProductType reports whether t is one of the supported product types
+func IsProductType(t string) bool {
+	return productTypes[t]
+}
+
+var productStatuses = map[string]bool{
+	"draft":     true,
+	"in_review": true,
+	"scheduled": true,
+	"published": true,
+	"archived":  true,
+}
+
+var productTypes = map[string]bool{
+	"source_code": true,
+	"pdf":         true,
+	"template":    true,
+	"other":       true,
+}
+
+type priceBucket struct {
+	Key   string
+	Label string
+	Max   int64 // upper bound (exclusive) in the base currency, 0 means unbounded
+}
+
+var priceBuckets = []priceBucket{
+	{Key: "free", Label: "Free", Max: 1},
+	{Key: "under_50k", Label: "< 50K", Max: 50000},
+	{Key: "50k_100k", Label: "50K - 100K", Max: 100000},
+	{Key: "100k_250k", Label: "100K - 250K", Max: 250000},
+	{Key: "250k_500k", Label: "250K - 500K", Max: 500000},
+	{Key: "over_500k", Label: "> 500K"},
+}
+
 type ProductRepository interface {
 	Create(product *models.Product) error
-	GetAll(page, limit int, categoryID *uint, search string) ([]models.Product, int64, error)
+	GetAll(page, limit int, filter models.ProductFilter) ([]models.Product, int64, error)
 	GetByID(id uint) (*models.Product, error)
 	GetBySlug(slug string) (*models.Product, error)
 	Update(product *models.Product) error
-	Delete(id uint) error
-	GetFeatured(limit int) ([]models.Product, error)
-	GetByCategory(categoryID uint, page, limit int) ([]models.Product, int64, error)
+	UpdateWithSlug(product *models.Product, oldSlug string) error
+	Delete(id, deletedBy uint) error
+	GetByCategory(categoryID uint, includeSubcategories bool, page, limit int) ([]models.Product, int64, error)
+	GetFacets(filter models.ProductFilter) (*models.ProductFacets, error)
+	UpdateStatus(product *models.Product, log *models.ProductStatusLog) error
+	PublishDue(at time.Time) (int64, error)
+	GetStatusLogs(productID uint) ([]models.ProductStatusLog, error)
+	SearchTechStacks(prefix string, limit int) ([]models.FacetCount, error)
+	GetBySlugsWithDeleted(slugs []string) ([]models.Product, error)
+	Import(creates, updates []*models.Product, revisions []*models.ProductRevision) error
+	GetPendingRevision(productID uint) (*models.ProductRevision, error)
+	GetPendingRevisions(page, limit int) ([]models.ProductRevision, int64, error)
+	GetPendingRevisionsFor(productIDs []uint) ([]models.ProductRevision, error)
+	SaveRevision(revision *models.ProductRevision) error
+	ApplyRevision(product *models.Product, revision *models.ProductRevision, oldSlug string) error
+	EachInBatches(batchSize int, fn func(products []models.Product) error) error
 }
 
 type productRepository struct {
@@ -29,32 +127,135 @@ func (r *productRepository) Create(product *models.Product) error {
 	return r.db.Create(product).Error
 }
 
-func (r *productRepository) GetAll(page, limit int, categoryID *uint, search string) ([]models.Product, int64, error) {
+func (r *productRepository) GetAll(page, limit int, filter models.ProductFilter) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
-	query := r.db.Model(&models.Product{}).Preload("Category")
-
-	// Filter by category
-	if categoryID != nil {
-		query = query.Where("category_id = ?", *categoryID)
+	if err := ValidateProductFilter(&filter); err != nil {
+		return nil, 0, err
 	}
 
-	// Search by name or description
-	if search != "" {
-		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
-	}
+	query := r.filtered(filter).Preload("Category")
 
 	// Count total
 	query.Count(&total)
 
+	// Rank by relevance when searching, otherwise use the whitelisted sort order
+	if filter.Sort == "relevance" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(products.search_vector, websearch_to_tsquery(?, ?)) DESC, products.created_at DESC",
+			Vars: []interface{}{searchConfig, filter.Search},
+		}})
+	} else {
+		query = query.Order(productSortOrders[filter.Sort])
+	}
+
 	// Pagination
 	offset := (page - 1) * limit
-	err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&products).Error
+	err := query.Offset(offset).Limit(limit).Find(&products).Error
 
 	return products, total, err
 }
 
+// ValidateProductFilter checks client supplied filters and normalizes the sort order.
+// Only sort keys from productSortOrders (or "relevance" with a search term) are accepted.
+func ValidateProductFilter(filter *models.ProductFilter) error {
+	if filter.MinPrice != nil && *filter.MinPrice < 0 {
+		return errors.New("min_price must not be negative")
+	}
+	if filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return errors.New("max_price must not be negative")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return errors.New("min_price must not be greater than max_price")
+	}
+	if filter.MinRating != nil && (*filter.MinRating < 0 || *filter.MinRating > 5) {
+		return errors.New("min_rating must be between 0 and 5")
+	}
+	for _, t := range filter.Types {
+		if !productTypes[t] {
+			return fmt.Errorf("invalid product type: %s", t)
+		}
+	}
+	for _, st := range filter.Statuses {
+		if !productStatuses[st] {
+			return fmt.Errorf("invalid product status: %s", st)
+		}
+	}
+
+	switch {
+	case filter.Sort == "":
+		filter.Sort = "newest"
+		if filter.Search != "" {
+			filter.Sort = "relevance"
+		}
+	case filter.Sort == "relevance":
+		if filter.Search == "" {
+			return errors.New("sort by relevance requires a search term")
+		}
+	default:
+		if _, ok := productSortOrders[filter.Sort]; !ok {
+			return fmt.Errorf("invalid sort order: %s", filter.Sort)
+		}
+	}
+
+	return nil
+}
+
+// filtered builds the base product query shared by listings and facets
+func (r *productRepository) filtered(filter models.ProductFilter) *gorm.DB {
+	query := r.db.Model(&models.Product{})
+
+	// Public listings only ever see published products
+	if len(filter.Statuses) > 0 {
+		query = query.Where("products.status IN ?", filter.Statuses)
+	} else {
+		query = query.Where("products.status = ?", "published")
+	}
+
+	// Filter by category
+	if filter.CategoryID != nil {
+		if filter.IncludeSubcategories {
+			query = query.Where("products.category_id IN ("+categorySubtreeSQL+")", *filter.CategoryID)
+		} else {
+			query = query.Where("products.category_id = ?", *filter.CategoryID)
+		}
+	}
+
+	// Full-text search over title, description, tech stack and features
+	if filter.Search != "" {
+		query = query.Where("products.search_vector @@ websearch_to_tsquery(?, ?)", searchConfig, filter.Search)
+	}
+
+	// Price range uses the discounted or campaign price when there is one
+	if filter.MinPrice != nil {
+		query = query.Where(basePriceSQL+" >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where(basePriceSQL+" <= ?", *filter.MaxPrice)
+	}
+
+	if len(filter.Types) > 0 {
+		query = query.Where("products.type IN ?", filter.Types)
+	}
+
+	// Every requested tech stack tag must be present (case-insensitive)
+	for _, tag := range filter.TechStacks {
+		query = query.Where("EXISTS (SELECT 1 FROM "+techStackSQL+" AS tech(tag) WHERE lower(tech.tag) = lower(?))", tag)
+	}
+
+	if filter.MinRating != nil {
+		query = query.Where("products.rating_average >= ?", *filter.MinRating)
+	}
+
+	// On sale covers both the product's own discount and running campaigns
+	if filter.OnSale {
+		query = query.Where(effectivePriceSQL + " < products.price")
+	}
+
+	return query
+}
+
 func (r *productRepository) GetByID(id uint) (*models.Product, error) {
 	var product models.Product
 	err := r.db.Preload("Category").First(&product, id).Error
@@ -77,21 +278,30 @@ func (r *productRepository) Update(product *models.Product) error {
 	return r.db.Save(product).Error
 }
 
-func (r *productRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Product{}, id).Error
+// UpdateWithSlug saves the product and keeps its old slug redirecting to it after a rename
+func (r *productRepository) UpdateWithSlug(product *models.Product, oldSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(product).Error; err != nil {
+			return err
+		}
+		return changeSlug(tx, "product", oldSlug, product.Slug, product.ID)
+	})
 }
 
-func (r *productRepository) GetFeatured(limit int) ([]models.Product, error) {
-	var products []models.Product
-	err := r.db.Preload("Category").Where("is_featured = ?", true).Limit(limit).Order("created_at DESC").Find(&products).Error
-	return products, err
+func (r *productRepository) Delete(id, deletedBy uint) error {
+	return softDelete(r.db, &models.Product{}, id, deletedBy)
 }
 
-func (r *productRepository) GetByCategory(categoryID uint, page, limit int) ([]models.Product, int64, error) {
+func (r *productRepository) GetByCategory(categoryID uint, includeSubcategories bool, page, limit int) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
-	query := r.db.Model(&models.Product{}).Preload("Category").Where("category_id = ?", categoryID)
+	query := r.db.Model(&models.Product{}).Preload("Category").Where("status = ?", "published")
+	if includeSubcategories {
+		query = query.Where("category_id IN ("+categorySubtreeSQL+")", categoryID)
+	} else {
+		query = query.Where("category_id = ?", categoryID)
+	}
 
 	query.Count(&total)
 
@@ -100,3 +310,352 @@ func (r *productRepository) GetByCategory(categoryID uint, page, limit int) ([]m
 
 	return products, total, err
 }
+
+func (r *productRepository) GetFacets(filter models.ProductFilter) (*models.ProductFacets, error) {
+	facets := &models.ProductFacets{}
+
+	if err := ValidateProductFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	err := r.filtered(filter).
+		Select("CAST(products.category_id AS TEXT) AS value, MAX(categories.name) AS label, COUNT(*) AS count").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Group("products.category_id").
+		Order("count DESC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.filtered(filter).
+		Select("products.type AS value, COUNT(*) AS count").
+		Group("products.type").
+		Order("count DESC").
+		Scan(&facets.Types).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.filtered(filter).
+		Select("tech.tag AS value, COUNT(*) AS count").
+		Joins("CROSS JOIN LATERAL " + techStackSQL + " AS tech(tag)").
+		Group("tech.tag").
+		Order("count DESC, tech.tag").
+		Limit(30).
+		Scan(&facets.TechStacks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []models.FacetCount
+	err = r.filtered(filter).
+		Select(priceBucketSQL() + " AS value, COUNT(*) AS count").
+		Group("value").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep buckets in ascending price order, including empty ones
+	counts := make(map[string]int64, len(buckets))
+	for _, b := range buckets {
+		counts[b.Value] = b.Count
+	}
+	for _, b := range priceBuckets {
+		facets.PriceRanges = append(facets.PriceRanges, models.FacetCount{
+			Value: b.Key,
+			Label: b.Label,
+			Count: counts[b.Key],
+		})
+	}
+
+	return facets, nil
+}
+
+// UpdateStatus saves a workflow transition together with its log entry
+func (r *productRepository) UpdateStatus(product *models.Product, log *models.ProductStatusLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(product).Updates(map[string]interface{}{
+			"status":       product.Status,
+			"publish_at":   product.PublishAt,
+			"published_at": product.PublishedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
+
+// PublishDue publishes every scheduled product whose publish_at has passed
+func (r *productRepository) PublishDue(at time.Time) (int64, error) {
+	var published int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id", "publish_at").
+			Where("status = ? AND publish_at <= ?", "scheduled", at).
+			Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		logs := make([]models.ProductStatusLog, 0, len(due))
+		for _, product := range due {
+			err := tx.Model(&models.Product{}).Where("id = ?", product.ID).Updates(map[string]interface{}{
+				"status":       "published",
+				"published_at": product.PublishAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+			logs = append(logs, models.ProductStatusLog{
+				ProductID:  product.ID,
+				FromStatus: "scheduled",
+				ToStatus:   "published",
+				Comment:    "Published by scheduler",
+			})
+		}
+
+		published = int64(len(due))
+		return tx.Create(&logs).Error
+	})
+
+	return published, err
+}
+
+func (r *productRepository) GetStatusLogs(productID uint) ([]models.ProductStatusLog, error) {
+	var logs []models.ProductStatusLog
+	err := r.db.Preload("Actor").Where("product_id = ?", productID).Order("created_at DESC, id DESC").Find(&logs).Error
+	return logs, err
+}
+
+// SearchTechStacks suggests tech stack tags of published products starting with
+// prefix (case-insensitive), most used first.
+func (r *productRepository) SearchTechStacks(prefix string, limit int) ([]models.FacetCount, error) {
+	var tags []models.FacetCount
+
+	query := r.db.Model(&models.Product{}).
+		Select("MIN(tech.tag) AS value, COUNT(DISTINCT products.id) AS count").
+		Joins("CROSS JOIN LATERAL "+techStackSQL+" AS tech(tag)").
+		Where("products.status = ?", "published")
+
+	if prefix != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+		query = query.Where("tech.tag ILIKE ?", escaped+"%")
+	}
+
+	err := query.Group("lower(tech.tag)").
+		Order("count DESC, value").
+		Limit(limit).
+		Scan(&tags).Error
+
+	return tags, err
+}
+
+// GetBySlugsWithDeleted includes soft-deleted products, whose slugs are still
+// taken by the unique index.
+func (r *productRepository) GetBySlugsWithDeleted(slugs []string) ([]models.Product, error) {
+	var products []models.Product
+	if len(slugs) == 0 {
+		return products, nil
+	}
+	err := r.db.Unscoped().Where("slug IN ?", slugs).Find(&products).Error
+	return products, err
+}
+
+// Import creates and updates products and stores revisions of products
+// awaiting review, all in a single transaction
+func (r *productRepository) Import(creates, updates []*models.Product, revisions []*models.ProductRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, product := range creates {
+			if err := tx.Create(product).Error; err != nil {
+				return fmt.Errorf("create %s: %w", product.Slug, err)
+			}
+		}
+		for _, product := range updates {
+			if err := tx.Omit("Category").Save(product).Error; err != nil {
+				return fmt.Errorf("update %s: %w", product.Slug, err)
+			}
+		}
+		for _, revision := range revisions {
+			if err := tx.Omit("Product").Save(revision).Error; err != nil {
+				return fmt.Errorf("revise product %d: %w", revision.ProductID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *productRepository) GetPendingRevision(productID uint) (*models.ProductRevision, error) {
+	var revision models.ProductRevision
+	err := r.db.Where("product_id = ? AND status = ?", productID, "pending").First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetPendingRevisions lists the revisions waiting for review, oldest first
+func (r *productRepository) GetPendingRevisions(page, limit int) ([]models.ProductRevision, int64, error) {
+	var revisions []models.ProductRevision
+	var total int64
+
+	query := r.db.Model(&models.ProductRevision{}).Where("status = ?", "pending")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Product").Order("updated_at ASC, id ASC").Offset(offset).Limit(limit).Find(&revisions).Error
+	return revisions, total, err
+}
+
+func (r *productRepository) GetPendingRevisionsFor(productIDs []uint) ([]models.ProductRevision, error) {
+	var revisions []models.ProductRevision
+	if len(productIDs) == 0 {
+		return revisions, nil
+	}
+	err := r.db.Where("product_id IN ? AND status = ?", productIDs, "pending").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *productRepository) SaveRevision(revision *models.ProductRevision) error {
+	return r.db.Omit("Product").Save(revision).Error
+}
+
+// ApplyRevision saves the product with the approved content together with
+// the reviewed revision and the redirect from its old slug
+func (r *productRepository) ApplyRevision(product *models.Product, revision *models.ProductRevision, oldSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category").Save(product).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Product").Save(revision).Error; err != nil {
+			return err
+		}
+		return changeSlug(tx, "product", oldSlug, product.Slug, product.ID)
+	})
+}
+
+// EachInBatches walks every product in ID order without loading the whole catalog
+func (r *productRepository) EachInBatches(batchSize int, fn func(products []models.Product) error) error {
+	var batch []models.Product
+	return r.db.Preload("Category").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// priceBucketSQL returns a CASE expression mapping a product to its price bucket key
+func priceBucketSQL() string {
+	var sb strings.Builder
+	// Products without an exchange rate can't be bucketed and are left out of the counts
+	sb.WriteString("CASE WHEN " + basePriceSQL + " IS NULL THEN 'unpriced'")
+	for _, b := range priceBuckets {
+		if b.Max > 0 {
+			fmt.Fprintf(&sb, " WHEN %s < %d THEN '%s'", basePriceSQL, b.Max, b.Key)
+		} else {
+			fmt.Fprintf(&sb, " ELSE '%s'", b.Key)
+		}
+	}
+	sb.WriteString(" END")
+	return sb.String()
+}
+
+// baseRateSQL returns an expression for the factor converting minor units of a
+// product's currency into minor units of the base currency, using the rate in effect now
+func baseRateSQL() string {
+	var sb strings.Builder
+	sb.WriteString("CASE WHEN products.currency = '" + models.BaseCurrency + "' THEN 1 ELSE")
+	sb.WriteString(" (SELECT er.rate FROM exchange_rates er")
+	sb.WriteString(" WHERE er.currency = products.currency AND er.deleted_at IS NULL AND er.effective_at <= NOW()")
+	sb.WriteString(" ORDER BY er.effective_at DESC, er.id DESC LIMIT 1)")
+
+	// Scale by the difference in minor unit digits
+	sb.WriteString(" * POWER(10, CASE products.currency")
+	codes := make([]string, 0, len(models.Currencies))
+	for code := range models.Currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(&sb, " WHEN '%s' THEN %d", code, models.Currencies[models.BaseCurrency]-models.Currencies[code])
+	}
+	sb.WriteString(" ELSE 0 END) END")
+	return sb.String()
+}
+
+// SetupProductSearch creates the search_vector column, its GIN index and the
+// triggers that keep it in sync with title, description, tech stack, features
+// and the translations of the product.
+func SetupProductSearch(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+		`CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)`,
+		`CREATE OR REPLACE FUNCTION jsonb_text_array_join(val jsonb) RETURNS text AS $$
+			SELECT coalesce(string_agg(elem, ' '), '')
+			FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(val) = 'array' THEN val ELSE '[]'::jsonb END) AS elem
+		$$ LANGUAGE sql IMMUTABLE`,
+		`CREATE OR REPLACE FUNCTION products_search_vector_update() RETURNS trigger AS $$
+		BEGIN
+			NEW.search_vector :=
+				setweight(to_tsvector('` + searchConfig + `', coalesce(NEW.title, '')), 'A') ||
+				setweight(to_tsvector('` + searchConfig + `', jsonb_text_array_join(NEW.tech_stack)), 'B') ||
+				setweight(to_tsvector('` + searchConfig + `', coalesce(NEW.description, '')), 'C') ||
+				setweight(to_tsvector('` + searchConfig + `', jsonb_text_array_join(NEW.features)), 'D') ||
+				-- Translated content is searchable in every locale
+				coalesce((
+					SELECT setweight(to_tsvector('` + searchConfig + `', string_agg(t.title, ' ')), 'A') ||
+						setweight(to_tsvector('` + searchConfig + `', string_agg(coalesce(t.description, ''), ' ')), 'C')
+					FROM product_translations t WHERE t.product_id = NEW.id
+				), ''::tsvector);
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS products_search_vector_trigger ON products`,
+		`CREATE TRIGGER products_search_vector_trigger BEFORE INSERT OR UPDATE ON products
+			FOR EACH ROW EXECUTE FUNCTION products_search_vector_update()`,
+		// Reindex the product when one of its translations changes
+		`CREATE OR REPLACE FUNCTION product_translations_search_update() RETURNS trigger AS $$
+		BEGIN
+			UPDATE products SET title = title WHERE id = coalesce(NEW.product_id, OLD.product_id);
+			RETURN NULL;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS product_translations_search_trigger ON product_translations`,
+		`CREATE TRIGGER product_translations_search_trigger AFTER INSERT OR UPDATE OR DELETE ON product_translations
+			FOR EACH ROW EXECUTE FUNCTION product_translations_search_update()`,
+		// Backfill rows created before the trigger existed
+		`UPDATE products SET title = title WHERE search_vector IS NULL`,
+	}
+
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetupProductWorkflow moves products from the old is_active flag to the
+// status workflow: active products become published, inactive ones archived.
+// The flag is kept as is_active_legacy so the migration runs once and can be
+// checked or undone; drop that column by hand once it is no longer needed.
+func SetupProductWorkflow(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("products", "is_active") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE products SET
+			status = CASE WHEN is_active THEN 'published' ELSE 'archived' END,
+			published_at = CASE WHEN is_active THEN created_at END`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE products RENAME COLUMN is_active TO is_active_legacy").Error
+	})
+}
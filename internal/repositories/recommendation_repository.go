@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// purchasesSQL lists (user_id, product_id) pairs from paid order items, including bundle contents
+const purchasesSQL = `
+	SELECT orders.user_id, order_items.product_id FROM orders
+	JOIN order_items ON order_items.order_id = orders.id
+	WHERE orders.payment_status = 'paid' AND order_items.product_id IS NOT NULL AND orders.deleted_at IS NULL
+	UNION
+	SELECT orders.user_id, order_item_products.product_id FROM orders
+	JOIN order_items ON order_items.order_id = orders.id
+	JOIN order_item_products ON order_item_products.order_item_id = order_items.id
+	WHERE orders.payment_status = 'paid' AND orders.deleted_at IS NULL`
+
+// coPurchaseSQL scores product pairs by how many buyers bought both
+const coPurchaseSQL = `
+	WITH purchases AS (` + purchasesSQL + `),
+	pairs AS (
+		SELECT a.product_id, b.product_id AS related_product_id, COUNT(*) AS score
+		FROM purchases a
+		JOIN purchases b ON b.user_id = a.user_id AND b.product_id <> a.product_id
+		GROUP BY a.product_id, b.product_id
+	),
+	ranked AS (
+		SELECT pairs.*, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY score DESC, related_product_id) AS position
+		FROM pairs
+	)
+	INSERT INTO product_relations (product_id, related_product_id, source, score, updated_at)
+	SELECT product_id, related_product_id, 'co_purchase', score, NOW() FROM ranked WHERE position <= ?`
+
+// similaritySQL scores published product pairs by shared category (1 point)
+// and shared tech stack tags (0.5 point each). Existing co-purchase edges win.
+const similaritySQL = `
+	WITH tags AS (
+		SELECT products.id, lower(tech.tag) AS tag
+		FROM products CROSS JOIN LATERAL ` + techStackSQL + ` AS tech(tag)
+		WHERE products.status = 'published' AND products.deleted_at IS NULL
+	),
+	shared AS (
+		SELECT a.id AS product_id, b.id AS related_product_id, COUNT(DISTINCT a.tag) AS tags
+		FROM tags a
+		JOIN tags b ON b.tag = a.tag AND b.id <> a.id
+		GROUP BY a.id, b.id
+	),
+	candidates AS (
+		SELECT p.id AS product_id, q.id AS related_product_id,
+			(CASE WHEN p.category_id = q.category_id THEN 1 ELSE 0 END) + COALESCE(shared.tags, 0) * 0.5 AS score
+		FROM products p
+		JOIN products q ON q.id <> p.id AND q.status = 'published' AND q.deleted_at IS NULL
+		LEFT JOIN shared ON shared.product_id = p.id AND shared.related_product_id = q.id
+		WHERE p.deleted_at IS NULL AND (p.category_id = q.category_id OR shared.tags IS NOT NULL)
+	),
+	ranked AS (
+		SELECT candidates.*, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY score DESC, related_product_id DESC) AS position
+		FROM candidates
+	)
+	INSERT INTO product_relations (product_id, related_product_id, source, score, updated_at)
+	SELECT product_id, related_product_id, 'similar', score, NOW() FROM ranked WHERE position <= ?
+	ON CONFLICT (product_id, related_product_id) DO NOTHING`
+
+type RecommendationRepository interface {
+	RebuildRelations(perProduct int) error
+	GetRelated(productID uint, limit int) ([]models.Product, error)
+	GetForUser(userID uint, limit int) ([]models.Product, error)
+	GetPopularForUser(userID uint, limit int) ([]models.Product, error)
+}
+
+type recommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepository {
+	return &recommendationRepository{db: db}
+}
+
+// RebuildRelations recomputes the whole relation table in one transaction,
+// keeping at most perProduct edges of each source per product.
+func (r *recommendationRepository) RebuildRelations(perProduct int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_relations").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(coPurchaseSQL, perProduct).Error; err != nil {
+			return err
+		}
+		return tx.Exec(similaritySQL, perProduct).Error
+	})
+}
+
+// GetRelated returns published related products, co-purchases first
+func (r *recommendationRepository) GetRelated(productID uint, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Preload("Category").
+		Joins("JOIN product_relations ON product_relations.related_product_id = products.id").
+		Where("product_relations.product_id = ? AND products.status = ?", productID, "published").
+		Order("product_relations.source = 'co_purchase' DESC, product_relations.score DESC, products.id DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// GetForUser recommends published products related to what the user bought or
+// wishlisted, excluding anything they already own or have on their wishlist.
+func (r *recommendationRepository) GetForUser(userID uint, limit int) ([]models.Product, error) {
+	seeds := r.db.Raw(`
+		SELECT product_id FROM (`+purchasesSQL+`) AS purchases WHERE user_id = ?
+		UNION
+		SELECT product_id FROM wishlists WHERE user_id = ? AND deleted_at IS NULL`, userID, userID)
+
+	scored := r.db.Table("product_relations").
+		Select("related_product_id, SUM(CASE WHEN source = 'co_purchase' THEN score * 2 ELSE score END) AS total_score").
+		Where("product_id IN (?) AND related_product_id NOT IN (?)", seeds, seeds).
+		Group("related_product_id")
+
+	var products []models.Product
+	err := r.db.Preload("Category").
+		Joins("JOIN (?) AS scored ON scored.related_product_id = products.id", scored).
+		Where("products.status = ?", "published").
+		Order("scored.total_score DESC, products.downloads_count DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// GetPopularForUser is the cold-start fallback: best sellers the user does not own yet
+func (r *recommendationRepository) GetPopularForUser(userID uint, limit int) ([]models.Product, error) {
+	owned := r.db.Raw(`SELECT product_id FROM (`+purchasesSQL+`) AS purchases WHERE user_id = ?`, userID)
+
+	var products []models.Product
+	err := r.db.Preload("Category").
+		Where("products.status = ? AND products.id NOT IN (?)", "published", owned).
+		Order("products.downloads_count DESC, products.rating_average DESC, products.id DESC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
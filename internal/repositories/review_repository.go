@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"fmt"
 	"gin-quickstart/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReviewRepository interface {
@@ -12,10 +14,38 @@ type ReviewRepository interface {
 	GetByProductID(productID uint, limit, offset int) ([]*models.Review, int64, error)
 	GetByUserID(userID uint) ([]*models.Review, error)
 	Update(review *models.Review) error
-	Delete(id uint) error
+	Delete(id, deletedBy uint) error
+	Restore(id uint) error
 	GetAverageRating(productID uint) (float64, error)
+	RebuildProductRatings() error
 }
 
+// ratingAggregateSQL recomputes rating_average, rating_count and the star
+// histogram. The %s placeholder takes an optional WHERE on the products scanned.
+const ratingAggregateSQL = `UPDATE products SET
+	rating_average = stats.average,
+	rating_count = stats.total,
+	rating_1_count = stats.c1,
+	rating_2_count = stats.c2,
+	rating_3_count = stats.c3,
+	rating_4_count = stats.c4,
+	rating_5_count = stats.c5
+FROM (
+	SELECT p.id AS product_id,
+		COALESCE(ROUND(AVG(r.rating)::numeric, 2), 0) AS average,
+		COUNT(r.id) AS total,
+		COUNT(r.id) FILTER (WHERE r.rating = 1) AS c1,
+		COUNT(r.id) FILTER (WHERE r.rating = 2) AS c2,
+		COUNT(r.id) FILTER (WHERE r.rating = 3) AS c3,
+		COUNT(r.id) FILTER (WHERE r.rating = 4) AS c4,
+		COUNT(r.id) FILTER (WHERE r.rating = 5) AS c5
+	FROM products p
+	LEFT JOIN reviews r ON r.product_id = p.id AND r.deleted_at IS NULL
+	%s
+	GROUP BY p.id
+) AS stats
+WHERE products.id = stats.product_id`
+
 type reviewRepository struct {
 	db *gorm.DB
 }
@@ -25,7 +55,9 @@ func NewReviewRepository(db *gorm.DB) ReviewRepository {
 }
 
 func (r *reviewRepository) Create(review *models.Review) error {
-	return r.db.Create(review).Error
+	return r.withRatingUpdate(review.ProductID, func(tx *gorm.DB) error {
+		return tx.Create(review).Error
+	})
 }
 
 func (r *reviewRepository) GetByID(id uint) (*models.Review, error) {
@@ -66,11 +98,54 @@ func (r *reviewRepository) GetByUserID(userID uint) ([]*models.Review, error) {
 }
 
 func (r *reviewRepository) Update(review *models.Review) error {
-	return r.db.Save(review).Error
+	return r.withRatingUpdate(review.ProductID, func(tx *gorm.DB) error {
+		return tx.Save(review).Error
+	})
 }
 
-func (r *reviewRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Review{}, id).Error
+func (r *reviewRepository) Delete(id, deletedBy uint) error {
+	var review models.Review
+	if err := r.db.Select("id", "product_id").First(&review, id).Error; err != nil {
+		return err
+	}
+
+	return r.withRatingUpdate(review.ProductID, func(tx *gorm.DB) error {
+		return softDelete(tx, &models.Review{}, id, deletedBy)
+	})
+}
+
+// Restore brings a review back from the trash and counts it in the ratings again
+func (r *reviewRepository) Restore(id uint) error {
+	var review models.Review
+	if err := r.db.Unscoped().Select("id", "product_id").First(&review, id).Error; err != nil {
+		return err
+	}
+
+	return r.withRatingUpdate(review.ProductID, func(tx *gorm.DB) error {
+		return restoreRow(tx, "reviews", id, nil)
+	})
+}
+
+// withRatingUpdate runs fn and recomputes the product's rating aggregates in the
+// same transaction. The product row is locked first so concurrent review
+// changes for the same product are applied one after another. Trashed
+// products count too, so their reviews can still be moderated.
+func (r *reviewRepository) withRatingUpdate(productID uint, fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Product
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&locked, productID).Error
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return tx.Exec(fmt.Sprintf(ratingAggregateSQL, "WHERE p.id = ?"), productID).Error
+	})
 }
 
 func (r *reviewRepository) GetAverageRating(productID uint) (float64, error) {
@@ -81,3 +156,8 @@ func (r *reviewRepository) GetAverageRating(productID uint) (float64, error) {
 		Scan(&avg).Error
 	return avg, err
 }
+
+// RebuildProductRatings recomputes rating aggregates for every product
+func (r *reviewRepository) RebuildProductRatings() error {
+	return r.db.Exec(fmt.Sprintf(ratingAggregateSQL, "")).Error
+}
@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SaleCampaignRepository interface {
+	Create(campaign *models.SaleCampaign) error
+	GetByID(id uint) (*models.SaleCampaign, error)
+	GetAll(page, limit int, state string, at time.Time) ([]models.SaleCampaign, int64, error)
+	GetActive(at time.Time) ([]models.SaleCampaign, error)
+	Update(campaign *models.SaleCampaign) error
+	Delete(id uint) error
+	GetLatestPrice(productID uint) (*models.ProductPriceHistory, error)
+	GetLatestPrices() (map[uint]models.ProductPriceHistory, error)
+	RecordPrices(entries []models.ProductPriceHistory) error
+	GetPriceHistory(productID uint, since time.Time) ([]models.ProductPriceHistory, error)
+	GetLowestPrice(productID uint, currency string, from, to time.Time) (*int64, error)
+}
+
+type saleCampaignRepository struct {
+	db *gorm.DB
+}
+
+func NewSaleCampaignRepository(db *gorm.DB) SaleCampaignRepository {
+	return &saleCampaignRepository{db: db}
+}
+
+func (r *saleCampaignRepository) Create(campaign *models.SaleCampaign) error {
+	return r.db.Create(campaign).Error
+}
+
+func (r *saleCampaignRepository) GetByID(id uint) (*models.SaleCampaign, error) {
+	var campaign models.SaleCampaign
+	err := r.db.Preload("Products").Preload("Categories").First(&campaign, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// GetAll lists campaigns, optionally narrowed to a state relative to at: active, upcoming or ended
+func (r *saleCampaignRepository) GetAll(page, limit int, state string, at time.Time) ([]models.SaleCampaign, int64, error) {
+	var campaigns []models.SaleCampaign
+	var total int64
+
+	query := r.db.Model(&models.SaleCampaign{})
+	switch state {
+	case "active":
+		query = query.Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, at, at)
+	case "upcoming":
+		query = query.Where("is_active = ? AND starts_at > ?", true, at)
+	case "ended":
+		query = query.Where("ends_at <= ?", at)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Products").
+		Preload("Categories").
+		Order("starts_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&campaigns).Error
+	return campaigns, total, err
+}
+
+// GetActive returns the campaigns running at the given time with their targets
+// loaded. The categories of a category campaign include their subcategories.
+func (r *saleCampaignRepository) GetActive(at time.Time) ([]models.SaleCampaign, error) {
+	var campaigns []models.SaleCampaign
+	err := r.db.
+		Preload("Products", func(db *gorm.DB) *gorm.DB { return db.Select("products.id") }).
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Select("categories.id") }).
+		Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, at, at).
+		Order("priority DESC, id ASC").
+		Find(&campaigns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range campaigns {
+		campaign := &campaigns[i]
+		if campaign.Scope != "category" || len(campaign.Categories) == 0 {
+			continue
+		}
+		ids := make([]uint, len(campaign.Categories))
+		for j, category := range campaign.Categories {
+			ids[j] = category.ID
+		}
+		campaign.Categories = nil
+		err := r.db.Model(&models.Category{}).
+			Select("categories.id").
+			Where(inCategorySubtrees(r.db, "categories.id", ids)).
+			Find(&campaign.Categories).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return campaigns, nil
+}
+
+// Update saves the campaign and replaces its product and category targets
+func (r *saleCampaignRepository) Update(campaign *models.SaleCampaign) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(campaign).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(campaign).Association("Products").Replace(campaign.Products); err != nil {
+			return err
+		}
+		return tx.Model(campaign).Association("Categories").Replace(campaign.Categories)
+	})
+}
+
+func (r *saleCampaignRepository) Delete(id uint) error {
+	return r.db.Delete(&models.SaleCampaign{}, id).Error
+}
+
+// GetLatestPrices returns the most recently recorded price of every product
+func (r *saleCampaignRepository) GetLatestPrices() (map[uint]models.ProductPriceHistory, error) {
+	var rows []models.ProductPriceHistory
+	err := r.db.Raw(`SELECT DISTINCT ON (product_id) product_id, currency, price
+		FROM product_price_histories
+		ORDER BY product_id, recorded_at DESC, id DESC`).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[uint]models.ProductPriceHistory, len(rows))
+	for _, row := range rows {
+		prices[row.ProductID] = row
+	}
+	return prices, nil
+}
+
+func (r *saleCampaignRepository) RecordPrices(entries []models.ProductPriceHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(entries, 500).Error
+}
+
+// GetPriceHistory returns the price changes since the given time, plus the
+// change that was already in effect at that time.
+func (r *saleCampaignRepository) GetPriceHistory(productID uint, since time.Time) ([]models.ProductPriceHistory, error) {
+	var history []models.ProductPriceHistory
+	err := r.db.
+		Where("product_id = ?", productID).
+		Where("recorded_at >= ? OR id = (?)", since, r.inEffectAt(productID, since)).
+		Order("recorded_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
+
+// GetLowestPrice returns the lowest price in effect at any point in [from, to).
+// Prices recorded in another currency are not comparable and are skipped.
+func (r *saleCampaignRepository) GetLowestPrice(productID uint, currency string, from, to time.Time) (*int64, error) {
+	var lowest *int64
+	err := r.db.Model(&models.ProductPriceHistory{}).
+		Select("MIN(price)").
+		Where("product_id = ? AND currency = ?", productID, currency).
+		Where("(recorded_at >= ? AND recorded_at < ?) OR id = (?)", from, to, r.inEffectAt(productID, from)).
+		Scan(&lowest).Error
+	return lowest, err
+}
+
+// inEffectAt is a subquery for the history row that was current at the given time
+func (r *saleCampaignRepository) inEffectAt(productID uint, at time.Time) *gorm.DB {
+	return r.db.Model(&models.ProductPriceHistory{}).
+		Select("id").
+		Where("product_id = ? AND recorded_at < ?", productID, at).
+		Order("recorded_at DESC, id DESC").
+		Limit(1)
+}
+
+func (r *saleCampaignRepository) GetLatestPrice(productID uint) (*models.ProductPriceHistory, error) {
+	var entry models.ProductPriceHistory
+	err := r.db.Where("product_id = ?", productID).
+		Order("recorded_at DESC, id DESC").
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"fmt"
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slugTables maps redirect entity types to the table holding their current slugs
+var slugTables = map[string]string{
+	"product":  "products",
+	"category": "categories",
+	"bundle":   "bundles",
+}
+
+type SlugRedirectRepository interface {
+	Resolve(entityType, slug string) (uint, error)
+	IsTaken(entityType, slug string, excludeID uint) (bool, error)
+}
+
+type slugRedirectRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugRedirectRepository(db *gorm.DB) SlugRedirectRepository {
+	return &slugRedirectRepository{db: db}
+}
+
+func (r *slugRedirectRepository) Resolve(entityType, slug string) (uint, error) {
+	var redirect models.SlugRedirect
+	err := r.db.Where("entity_type = ? AND old_slug = ?", entityType, slug).First(&redirect).Error
+	if err != nil {
+		return 0, err
+	}
+	return redirect.EntityID, nil
+}
+
+// IsTaken reports whether another entity uses the slug now (including soft-deleted
+// rows, which still hold the unique index) or used it before.
+func (r *slugRedirectRepository) IsTaken(entityType, slug string, excludeID uint) (bool, error) {
+	table, ok := slugTables[entityType]
+	if !ok {
+		return false, fmt.Errorf("unknown slug entity type: %s", entityType)
+	}
+
+	var count int64
+	err := r.db.Table(table).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.Model(&models.SlugRedirect{}).
+		Where("entity_type = ? AND old_slug = ? AND entity_id <> ?", entityType, slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// changeSlug records an entity's move to a new slug within tx: oldSlug then
+// redirects to the entity, taking over any older redirect with the same slug,
+// and the entity's redirect for a slug it is using again is dropped.
+func changeSlug(tx *gorm.DB, entityType, oldSlug, newSlug string, entityID uint) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+
+	if oldSlug != "" {
+		redirect := &models.SlugRedirect{
+			EntityType: entityType,
+			OldSlug:    oldSlug,
+			EntityID:   entityID,
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}, {Name: "old_slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+		}).Create(redirect).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Where("entity_type = ? AND old_slug = ? AND entity_id = ?", entityType, newSlug, entityID).
+		Delete(&models.SlugRedirect{}).Error
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	CreateRule(rule *models.TaxRule) error
+	GetRuleByID(id uint) (*models.TaxRule, error)
+	GetRules(country string) ([]models.TaxRule, error)
+	GetActiveRules(country string) ([]models.TaxRule, error)
+	RuleScopeTaken(rule *models.TaxRule) (bool, error)
+	UpdateRule(rule *models.TaxRule) error
+	DeleteRule(id uint) error
+	ProductTypes(productIDs []uint) (map[uint]string, error)
+	GetPaidOrderTaxesBetween(from, to time.Time) ([]models.OrderTax, error)
+}
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) TaxRepository {
+	return &taxRepository{db: db}
+}
+
+func (r *taxRepository) CreateRule(rule *models.TaxRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *taxRepository) GetRuleByID(id uint) (*models.TaxRule, error) {
+	var rule models.TaxRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetRules lists the rules, of one country when given
+func (r *taxRepository) GetRules(country string) ([]models.TaxRule, error) {
+	var rules []models.TaxRule
+	query := r.db.Model(&models.TaxRule{})
+	if country != "" {
+		query = query.Where("country = ?", country)
+	}
+	err := query.Order("country ASC, region ASC, product_type ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *taxRepository) GetActiveRules(country string) ([]models.TaxRule, error) {
+	var rules []models.TaxRule
+	err := r.db.Where("country = ? AND is_active = ?", country, true).Find(&rules).Error
+	return rules, err
+}
+
+// RuleScopeTaken reports whether another rule covers the same country, region
+// and product type
+func (r *taxRepository) RuleScopeTaken(rule *models.TaxRule) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TaxRule{}).
+		Where("country = ? AND LOWER(region) = LOWER(?) AND product_type = ? AND id <> ?", rule.Country, rule.Region, rule.ProductType, rule.ID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *taxRepository) UpdateRule(rule *models.TaxRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *taxRepository) DeleteRule(id uint) error {
+	return r.db.Delete(&models.TaxRule{}, id).Error
+}
+
+// ProductTypes maps the products, deleted ones included, to their type
+func (r *taxRepository) ProductTypes(productIDs []uint) (map[uint]string, error) {
+	types := make(map[uint]string, len(productIDs))
+	if len(productIDs) == 0 {
+		return types, nil
+	}
+
+	var rows []struct {
+		ID   uint
+		Type string
+	}
+	err := r.db.Unscoped().Model(&models.Product{}).
+		Select("id, type").
+		Where("id IN ?", productIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		types[row.ID] = row.Type
+	}
+	return types, nil
+}
+
+// GetPaidOrderTaxesBetween lists the tax lines of paid orders placed in
+// [from, to), with their order
+func (r *taxRepository) GetPaidOrderTaxesBetween(from, to time.Time) ([]models.OrderTax, error) {
+	var taxes []models.OrderTax
+	err := r.db.Preload("Order").
+		Joins("JOIN orders o ON o.id = order_taxes.order_id").
+		Where("o.payment_status = ? AND o.deleted_at IS NULL", "paid").
+		Where("o.created_at >= ? AND o.created_at < ?", from, to).
+		Order("order_taxes.id ASC").
+		Find(&taxes).Error
+	return taxes, err
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"gin-quickstart/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TranslationRepository interface {
+	GetProductTranslations(productID uint) ([]models.ProductTranslation, error)
+	FindProductTranslations(productIDs []uint, locales []string) ([]models.ProductTranslation, error)
+	SaveProductTranslation(translation *models.ProductTranslation) error
+	DeleteProductTranslation(productID uint, locale string) (bool, error)
+	GetCategoryTranslations(categoryID uint) ([]models.CategoryTranslation, error)
+	FindCategoryTranslations(categoryIDs []uint, locales []string) ([]models.CategoryTranslation, error)
+	SaveCategoryTranslation(translation *models.CategoryTranslation) error
+	DeleteCategoryTranslation(categoryID uint, locale string) (bool, error)
+}
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+func (r *translationRepository) GetProductTranslations(productID uint) ([]models.ProductTranslation, error) {
+	var translations []models.ProductTranslation
+	err := r.db.Where("product_id = ?", productID).Order("locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) FindProductTranslations(productIDs []uint, locales []string) ([]models.ProductTranslation, error) {
+	var translations []models.ProductTranslation
+	if len(productIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("product_id IN ? AND locale IN ?", productIDs, locales).Find(&translations).Error
+	return translations, err
+}
+
+// SaveProductTranslation creates or replaces the product's translation for its locale
+func (r *translationRepository) SaveProductTranslation(translation *models.ProductTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *translationRepository) DeleteProductTranslation(productID uint, locale string) (bool, error) {
+	result := r.db.Where("product_id = ? AND locale = ?", productID, locale).Delete(&models.ProductTranslation{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *translationRepository) GetCategoryTranslations(categoryID uint) ([]models.CategoryTranslation, error) {
+	var translations []models.CategoryTranslation
+	err := r.db.Where("category_id = ?", categoryID).Order("locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) FindCategoryTranslations(categoryIDs []uint, locales []string) ([]models.CategoryTranslation, error) {
+	var translations []models.CategoryTranslation
+	if len(categoryIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+	err := r.db.Where("category_id IN ? AND locale IN ?", categoryIDs, locales).Find(&translations).Error
+	return translations, err
+}
+
+// SaveCategoryTranslation creates or replaces the category's translation for its locale
+func (r *translationRepository) SaveCategoryTranslation(translation *models.CategoryTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *translationRepository) DeleteCategoryTranslation(categoryID uint, locale string) (bool, error) {
+	result := r.db.Where("category_id = ? AND locale = ?", categoryID, locale).Delete(&models.CategoryTranslation{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"fmt"
+	"gin-quickstart/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// trashEntity describes how a soft-deletable table is shown, restored and purged
+type trashEntity struct {
+	table      string
+	label      string   // SQL expression naming the row in listings
+	identifier string   // SQL expression for the value that must stay unique, if any
+	parent     string   // table the row belongs to, if restoring it needs that row alive
+	parentKey  string   // column referencing parent
+	blockers   []string // "table.column" references that keep the row from being purged
+	cascade    []string // statements removing rows that only exist for the purged one
+}
+
+var trashEntities = map[string]trashEntity{
+	"products": {
+		table:      "products",
+		label:      "t.title",
+		identifier: "t.slug",
+		parent:     "categories",
+		parentKey:  "category_id",
+		blockers:   []string{"orders.product_id", "order_items.product_id", "downloads.product_id", "license_keys.product_id", "bundle_items.product_id", "order_item_products.product_id"},
+		cascade: []string{
+			"DELETE FROM carts WHERE product_id = ?",
+			"DELETE FROM wishlists WHERE product_id = ?",
+			"DELETE FROM reviews WHERE product_id = ?",
+			"DELETE FROM product_question_votes WHERE question_id IN (SELECT id FROM product_questions WHERE product_id = ?)",
+			"DELETE FROM product_answers WHERE question_id IN (SELECT id FROM product_questions WHERE product_id = ?)",
+			"DELETE FROM product_questions WHERE product_id = ?",
+			"DELETE FROM product_relations WHERE product_id = ? OR related_product_id = ?",
+			"DELETE FROM product_price_histories WHERE product_id = ?",
+			"DELETE FROM product_files WHERE product_id = ?",
+			"DELETE FROM product_status_logs WHERE product_id = ?",
+			"DELETE FROM product_revisions WHERE product_id = ?",
+			"DELETE FROM featured_products WHERE product_id = ?",
+			"DELETE FROM sale_campaign_products WHERE product_id = ?",
+			"DELETE FROM coupon_products WHERE product_id = ?",
+			"DELETE FROM license_tiers WHERE product_id = ?",
+			"DELETE FROM product_translations WHERE product_id = ?",
+			"DELETE FROM slug_redirects WHERE entity_type = 'product' AND entity_id = ?",
+		},
+	},
+	"categories": {
+		table:      "categories",
+		label:      "t.name",
+		identifier: "t.slug",
+		parent:     "categories",
+		parentKey:  "parent_id",
+		blockers:   []string{"products.category_id", "categories.parent_id"},
+		cascade: []string{
+			"DELETE FROM sale_campaign_categories WHERE category_id = ?",
+			"DELETE FROM coupon_categories WHERE category_id = ?",
+			"DELETE FROM category_translations WHERE category_id = ?",
+			"DELETE FROM slug_redirects WHERE entity_type = 'category' AND entity_id = ?",
+		},
+	},
+	"reviews": {
+		table:     "reviews",
+		label:     "LEFT(t.comment, 100)",
+		parent:    "products",
+		parentKey: "product_id",
+	},
+	"users": {
+		table:      "users",
+		label:      "t.name",
+		identifier: "t.email",
+		blockers: []string{
+			"orders.user_id", "custom_orders.user_id", "transactions.user_id", "downloads.user_id",
+			"license_keys.user_id", "products.created_by", "reviews.user_id",
+			"product_questions.user_id", "product_answers.user_id",
+		},
+		cascade: []string{
+			"DELETE FROM carts WHERE user_id = ?",
+			"DELETE FROM wishlists WHERE user_id = ?",
+			"DELETE FROM notifications WHERE user_id = ?",
+			"DELETE FROM product_question_votes WHERE user_id = ?",
+		},
+	},
+}
+
+type TrashRepository interface {
+	List(entityType string, page, limit int) ([]models.TrashItem, int64, error)
+	GetDeleted(entityType string, id uint) (*models.TrashItem, error)
+	GetDeletedProduct(id uint) (*models.Product, error)
+	HasDeletedParent(entityType string, id uint) (bool, error)
+	Restore(entityType string, id uint, updates map[string]interface{}) error
+	RestoreWithSlug(entityType, slugType string, id uint, oldSlug, slug string) error
+	IsReferenced(entityType string, id uint) (bool, error)
+	Purge(entityType string, id uint) error
+	GetExpired(entityType string, before time.Time, offset, limit int) ([]uint, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+func trashEntityFor(entityType string) (trashEntity, error) {
+	entity, ok := trashEntities[entityType]
+	if !ok {
+		return entity, fmt.Errorf("unknown trash type: %s", entityType)
+	}
+	return entity, nil
+}
+
+// softDelete moves a row to the trash, recording who deleted it
+func softDelete(db *gorm.DB, model interface{}, id, deletedBy uint) error {
+	return db.Model(model).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_by": deletedBy,
+		"deleted_at": time.Now(),
+	}).Error
+}
+
+// restoreRow takes a row out of the trash, applying any extra column updates
+func restoreRow(db *gorm.DB, table string, id uint, updates map[string]interface{}) error {
+	values := map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+	}
+	for column, value := range updates {
+		values[column] = value
+	}
+
+	result := db.Table(table).Where("id = ? AND deleted_at IS NOT NULL", id).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *trashRepository) query(entity trashEntity) *gorm.DB {
+	identifier := "''"
+	if entity.identifier != "" {
+		identifier = entity.identifier
+	}
+
+	return r.db.Table(entity.table + " AS t").
+		Select("t.id, " + entity.label + " AS label, " + identifier + " AS identifier, t.deleted_at, t.deleted_by, u.name AS deleted_by_name").
+		Joins("LEFT JOIN users u ON u.id = t.deleted_by").
+		Where("t.deleted_at IS NOT NULL")
+}
+
+// List returns the trashed rows of one type, most recently deleted first
+func (r *trashRepository) List(entityType string, page, limit int) ([]models.TrashItem, int64, error) {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.db.Table(entity.table).Where("deleted_at IS NOT NULL").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []models.TrashItem
+	offset := (page - 1) * limit
+	err = r.query(entity).
+		Order("t.deleted_at DESC, t.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&items).Error
+	return items, total, err
+}
+
+func (r *trashRepository) GetDeleted(entityType string, id uint) (*models.TrashItem, error) {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.TrashItem
+	if err := r.query(entity).Where("t.id = ?", id).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &items[0], nil
+}
+
+func (r *trashRepository) GetDeletedProduct(id uint) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// HasDeletedParent reports whether the row belongs to a row that is itself in the trash
+func (r *trashRepository) HasDeletedParent(entityType string, id uint) (bool, error) {
+	entity, err := trashEntityFor(entityType)
+	if err != nil || entity.parent == "" {
+		return false, err
+	}
+
+	var deleted bool
+	err = r.db.Raw(fmt.Sprintf(`SELECT EXISTS (
+		SELECT 1 FROM %s t JOIN %s p ON p.id = t.%s
+		WHERE t.id = ? AND p.deleted_at IS NOT NULL)`, entity.table, entity.parent, entity.parentKey), id).
+		Scan(&deleted).Error
+	return deleted, err
+}
+
+func (r *trashRepository) Restore(entityType string, id uint, updates map[string]interface{}) error {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return err
+	}
+	return restoreRow(r.db, entity.table, id, updates)
+}
+
+// RestoreWithSlug restores a row under slug, keeping oldSlug redirecting to it when they differ
+func (r *trashRepository) RestoreWithSlug(entityType, slugType string, id uint, oldSlug, slug string) error {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if slug != oldSlug {
+		updates["slug"] = slug
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := restoreRow(tx, entity.table, id, updates); err != nil {
+			return err
+		}
+		return changeSlug(tx, slugType, oldSlug, slug, id)
+	})
+}
+
+// IsReferenced reports whether records worth keeping, such as orders, still
+// point at the row. Soft-deleted references count too.
+func (r *trashRepository) IsReferenced(entityType string, id uint) (bool, error) {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return false, err
+	}
+
+	for _, blocker := range entity.blockers {
+		table, column, _ := strings.Cut(blocker, ".")
+		var referenced bool
+		err := r.db.Raw(fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s = ?)", table, column), id).
+			Scan(&referenced).Error
+		if err != nil || referenced {
+			return referenced, err
+		}
+	}
+	return false, nil
+}
+
+// Purge permanently deletes a trashed row together with the rows that only
+// exist for it. Callers check IsReferenced first.
+func (r *trashRepository) Purge(entityType string, id uint) error {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range entity.cascade {
+			args := make([]interface{}, strings.Count(statement, "?"))
+			for i := range args {
+				args[i] = id
+			}
+			if err := tx.Exec(statement, args...).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec("DELETE FROM "+entity.table+" WHERE id = ? AND deleted_at IS NOT NULL", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// GetExpired returns trashed rows deleted before the given time, oldest first
+func (r *trashRepository) GetExpired(entityType string, before time.Time, offset, limit int) ([]uint, error) {
+	entity, err := trashEntityFor(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	err = r.db.Table(entity.table).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
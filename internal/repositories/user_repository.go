@@ -12,7 +12,7 @@ type UserRepository interface {
 	FindByID(id uint) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	Update(user *models.User) error
-	Delete(id uint) error
+	Delete(id, deletedBy uint) error
 	GetAllUsers(page, limit int) ([]models.User, int64, error)
 }
 
@@ -56,8 +56,14 @@ func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&models.User{}, id).Error
+// SetupUserEmailIndex drops the original unique index on email, which also
+// covered deleted accounts. AutoMigrate creates one over live accounts only.
+func SetupUserEmailIndex(db *gorm.DB) error {
+	return db.Exec(`DROP INDEX IF EXISTS idx_users_email`).Error
+}
+
+func (r *userRepository) Delete(id, deletedBy uint) error {
+	return softDelete(r.db, &models.User{}, id, deletedBy)
 }
 
 func (r *userRepository) GetAllUsers(page, limit int) ([]models.User, int64, error) {
@@ -25,6 +25,21 @@ func SetupRoutes(
 	customOrderHandler *handlers.CustomOrderHandler,
 	notificationHandler *handlers.NotificationHandler,
 	analyticsHandler *handlers.AnalyticsHandler,
+	featuredHandler *handlers.FeaturedProductHandler,
+	bundleHandler *handlers.BundleHandler,
+	licenseTierHandler *handlers.LicenseTierHandler,
+	workflowHandler *handlers.ProductWorkflowHandler,
+	recommendationHandler *handlers.RecommendationHandler,
+	importHandler *handlers.ProductImportHandler,
+	productFileHandler *handlers.ProductFileHandler,
+	questionHandler *handlers.ProductQuestionHandler,
+	campaignHandler *handlers.SaleCampaignHandler,
+	couponHandler *handlers.CouponHandler,
+	taxHandler *handlers.TaxHandler,
+	currencyHandler *handlers.CurrencyHandler,
+	licenseKeyHandler *handlers.LicenseKeyHandler,
+	trashHandler *handlers.TrashHandler,
+	translationHandler *handlers.TranslationHandler,
 	apiLogRepo repositories.APILogRepository,
 ) {
 	// Global Middleware
@@ -50,6 +65,7 @@ func SetupRoutes(
 
 	// API v1 routes
 	v1 := r.Group("/api/v1")
+	v1.Use(middleware.Locale(cfg))
 	{
 		// Authentication routes (public)
 		auth := v1.Group("/auth")
@@ -68,14 +84,19 @@ func SetupRoutes(
 		{
 			user.GET("/profile", userHandler.GetProfile)
 			user.PUT("/profile", userHandler.UpdateProfile)
+			user.PUT("/billing", userHandler.UpdateBilling)
 			user.PUT("/password", userHandler.ChangePassword)
 			user.DELETE("/account", userHandler.DeleteAccount)
+			user.GET("/recommendations", recommendationHandler.GetUserRecommendations)
+			user.GET("/licenses", licenseKeyHandler.GetMyLicenses)
+			user.DELETE("/licenses/:id/activations/:activation_id", licenseKeyHandler.RemoveActivation)
 		}
 
 		// Category routes
 		categories := v1.Group("/categories")
 		{
 			categories.GET("", categoryHandler.GetAllCategories)
+			categories.GET("/tree", categoryHandler.GetCategoryTree)
 			categories.GET("/:id", categoryHandler.GetCategoryByID)
 			categories.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
 
@@ -85,19 +106,31 @@ func SetupRoutes(
 			categoriesAdmin.Use(middleware.AdminMiddleware())
 			{
 				categoriesAdmin.POST("", categoryHandler.CreateCategory)
+				categoriesAdmin.PUT("/reorder", categoryHandler.ReorderCategories)
 				categoriesAdmin.PUT("/:id", categoryHandler.UpdateCategory)
+				categoriesAdmin.PUT("/:id/move", categoryHandler.MoveCategory)
 				categoriesAdmin.DELETE("/:id", categoryHandler.DeleteCategory)
+				categoriesAdmin.GET("/:id/translations", translationHandler.GetCategoryTranslations)
+				categoriesAdmin.PUT("/:id/translations/:locale", translationHandler.SetCategoryTranslation)
+				categoriesAdmin.DELETE("/:id/translations/:locale", translationHandler.DeleteCategoryTranslation)
 			}
 		}
 
 		// Product routes
 		products := v1.Group("/products")
 		{
-			products.GET("", productHandler.GetAllProducts)
-			products.GET("/featured", productHandler.GetFeaturedProducts)
-			products.GET("/:id", productHandler.GetProductByID)
-			products.GET("/slug/:slug", productHandler.GetProductBySlug)
-			products.GET("/category/:category_id", productHandler.GetProductsByCategory)
+			products.GET("", middleware.OptionalAuthMiddleware(cfg), productHandler.GetAllProducts)
+			products.GET("/featured", middleware.OptionalAuthMiddleware(cfg), featuredHandler.GetFeaturedProducts)
+			products.GET("/:id", middleware.OptionalAuthMiddleware(cfg), productHandler.GetProductByID)
+			products.GET("/slug/:slug", middleware.OptionalAuthMiddleware(cfg), productHandler.GetProductBySlug)
+			products.GET("/category/:category_id", middleware.OptionalAuthMiddleware(cfg), productHandler.GetProductsByCategory)
+			products.GET("/:id/licenses", licenseTierHandler.GetProductLicenses)
+			products.GET("/:id/related", middleware.OptionalAuthMiddleware(cfg), recommendationHandler.GetRelatedProducts)
+			products.GET("/:id/tree", productFileHandler.GetProductTree)
+			products.GET("/:id/tree/file", productFileHandler.GetProductFile)
+			products.GET("/:id/price-history", campaignHandler.GetPriceHistory)
+			products.GET("/:id/questions", questionHandler.GetProductQuestions)
+			products.POST("/:id/questions", middleware.AuthMiddleware(cfg), questionHandler.AskQuestion)
 
 			// Admin only
 			productsAdmin := products.Group("")
@@ -107,18 +140,76 @@ func SetupRoutes(
 				productsAdmin.POST("", productHandler.CreateProduct)
 				productsAdmin.PUT("/:id", productHandler.UpdateProduct)
 				productsAdmin.DELETE("/:id", productHandler.DeleteProduct)
+				productsAdmin.GET("/:id/translations", translationHandler.GetProductTranslations)
+				productsAdmin.PUT("/:id/translations/:locale", translationHandler.SetProductTranslation)
+				productsAdmin.DELETE("/:id/translations/:locale", translationHandler.DeleteProductTranslation)
+				productsAdmin.POST("/:id/licenses", licenseTierHandler.CreateLicense)
+				productsAdmin.PUT("/:id/licenses/:tier_id", licenseTierHandler.UpdateLicense)
+				productsAdmin.DELETE("/:id/licenses/:tier_id", licenseTierHandler.DeleteLicense)
+				productsAdmin.POST("/:id/deliverable", productFileHandler.UploadDeliverable)
+				productsAdmin.PUT("/:id/tree/preview", productFileHandler.SetPreviewableFiles)
 			}
 		}
 
-		// Cart routes (protected)
+		// Sale campaigns (public)
+		sales := v1.Group("/sales")
+		{
+			sales.GET("", campaignHandler.GetActiveSales)
+		}
+
+		// Supported content locales (public)
+		v1.GET("/locales", translationHandler.GetLocales)
+
+		// Supported currencies and current exchange rates (public)
+		currencies := v1.Group("/currencies")
+		{
+			currencies.GET("", currencyHandler.GetCurrencies)
+		}
+
+		// License key validation and activation (public, called by shipped software)
+		licenses := v1.Group("/licenses")
+		{
+			licenses.GET("/public-key", licenseKeyHandler.GetPublicKey)
+			licenses.POST("/validate", licenseKeyHandler.ValidateLicense)
+			licenses.POST("/activate", licenseKeyHandler.ActivateLicense)
+			licenses.POST("/deactivate", licenseKeyHandler.DeactivateLicense)
+		}
+
+		// Tech stack browsing
+		techStacks := v1.Group("/tech-stacks")
+		{
+			techStacks.GET("", productHandler.GetTechStacks)
+			techStacks.GET("/:tag/products", middleware.OptionalAuthMiddleware(cfg), productHandler.GetProductsByTechStack)
+		}
+
+		// Bundle routes
+		bundles := v1.Group("/bundles")
+		{
+			bundles.GET("", bundleHandler.GetAllBundles)
+			bundles.GET("/:id", middleware.OptionalAuthMiddleware(cfg), bundleHandler.GetBundleByID)
+			bundles.GET("/slug/:slug", middleware.OptionalAuthMiddleware(cfg), bundleHandler.GetBundleBySlug)
+
+			// Admin only
+			bundlesAdmin := bundles.Group("")
+			bundlesAdmin.Use(middleware.AuthMiddleware(cfg))
+			bundlesAdmin.Use(middleware.AdminMiddleware())
+			{
+				bundlesAdmin.POST("", bundleHandler.CreateBundle)
+				bundlesAdmin.PUT("/:id", bundleHandler.UpdateBundle)
+				bundlesAdmin.DELETE("/:id", bundleHandler.DeleteBundle)
+			}
+		}
+
+		// Cart routes (guests use a cart token, checkout needs an account)
 		cart := v1.Group("/cart")
-		cart.Use(middleware.AuthMiddleware(cfg))
+		cart.Use(middleware.GuestOrAuthMiddleware(cfg))
 		{
 			cart.POST("", cartHandler.AddToCart)
-			cart.GET("", cartHandler.GetUserCart)
+			cart.GET("", cartHandler.GetCart)
 			cart.PUT("/:id", cartHandler.UpdateCartItem)
 			cart.DELETE("/:id", cartHandler.RemoveFromCart)
 			cart.DELETE("/clear", cartHandler.ClearCart)
+			cart.POST("/checkout", middleware.AuthMiddleware(cfg), orderHandler.CheckoutCart)
 		}
 
 		// Wishlist routes (protected)
@@ -136,6 +227,7 @@ func SetupRoutes(
 		orders.Use(middleware.AuthMiddleware(cfg))
 		{
 			orders.POST("", orderHandler.CreateOrder)
+			orders.POST("/upgrade", orderHandler.UpgradeLicense)
 			orders.GET("", orderHandler.GetUserOrders)
 			orders.GET("/:id", orderHandler.GetOrderByID)
 			orders.POST("/:id/payment-proof", orderHandler.UploadPaymentProof)
@@ -177,6 +269,16 @@ func SetupRoutes(
 			customOrders.PUT("/:id/cancel", customOrderHandler.CancelCustomOrder)
 		}
 
+		// Product Q&A routes (protected)
+		questions := v1.Group("/questions")
+		questions.Use(middleware.AuthMiddleware(cfg))
+		{
+			questions.POST("/:id/answers", questionHandler.AnswerQuestion)
+			questions.POST("/:id/vote", questionHandler.UpvoteQuestion)
+			questions.DELETE("/:id/vote", questionHandler.RemoveUpvote)
+			questions.DELETE("/:id", questionHandler.DeleteQuestion)
+		}
+
 		// Notification routes (protected)
 		notifications := v1.Group("/notifications")
 		notifications.Use(middleware.AuthMiddleware(cfg))
@@ -197,20 +299,89 @@ func SetupRoutes(
 			admin.GET("/users", userHandler.GetAllUsers)
 			admin.GET("/users/:id", userHandler.GetUserByID)
 			admin.PUT("/users/:id", userHandler.UpdateUser)
+			admin.PUT("/users/:id/tax-id", userHandler.VerifyTaxID)
 			admin.DELETE("/users/:id", userHandler.DeleteUser)
 
 			// Orders management
 			admin.GET("/orders", orderHandler.GetAllOrders)
 			admin.POST("/orders/:id/approve", orderHandler.ApprovePayment)
 			admin.POST("/orders/:id/reject", orderHandler.RejectPayment)
+			admin.POST("/orders/:id/licenses", licenseKeyHandler.IssueOrderLicenses)
+
+			// License keys
+			admin.POST("/licenses/:id/revoke", licenseKeyHandler.RevokeLicense)
+
+			// Trash: deleted products, categories, reviews and users
+			admin.GET("/trash/:type", trashHandler.GetTrash)
+			admin.POST("/trash/:type/:id/restore", trashHandler.RestoreItem)
+			admin.DELETE("/trash/:type/:id", trashHandler.PurgeItem)
 
 			// Custom Orders management
 			admin.GET("/custom-orders", customOrderHandler.AdminGetAllCustomOrders)
 			admin.PUT("/custom-orders/:id/process", customOrderHandler.AdminProcessCustomOrder)
 
+			// Product publishing workflow
+			admin.GET("/products", workflowHandler.GetProducts)
+			admin.GET("/products/:id", workflowHandler.GetProduct)
+			admin.POST("/products/:id/submit", workflowHandler.SubmitForReview)
+			admin.POST("/products/:id/approve", workflowHandler.Approve)
+			admin.POST("/products/:id/reject", workflowHandler.Reject)
+			admin.POST("/products/:id/archive", workflowHandler.Archive)
+			admin.POST("/products/:id/draft", workflowHandler.RevertToDraft)
+			admin.GET("/products/:id/revision", workflowHandler.GetPendingRevision)
+			admin.POST("/products/:id/revision/approve", workflowHandler.ApproveRevision)
+			admin.POST("/products/:id/revision/reject", workflowHandler.RejectRevision)
+			admin.GET("/product-revisions", workflowHandler.GetPendingRevisions)
+
+			// Bulk catalog import/export
+			admin.POST("/products/import", importHandler.ImportProducts)
+			admin.GET("/products/export", importHandler.ExportProducts)
+
+			// Sale campaigns
+			admin.GET("/campaigns", campaignHandler.GetCampaigns)
+			admin.GET("/campaigns/:id", campaignHandler.GetCampaign)
+			admin.POST("/campaigns", campaignHandler.CreateCampaign)
+			admin.PUT("/campaigns/:id", campaignHandler.UpdateCampaign)
+			admin.DELETE("/campaigns/:id", campaignHandler.DeleteCampaign)
+
+			// Coupons
+			admin.GET("/coupons", couponHandler.GetCoupons)
+			admin.GET("/coupons/usage", couponHandler.GetUsageReport)
+			admin.GET("/coupons/:id", couponHandler.GetCoupon)
+			admin.POST("/coupons", couponHandler.CreateCoupon)
+			admin.PUT("/coupons/:id", couponHandler.UpdateCoupon)
+			admin.DELETE("/coupons/:id", couponHandler.DeleteCoupon)
+			admin.GET("/coupons/:id/redemptions", couponHandler.GetRedemptions)
+
+			// Tax rules and report
+			admin.GET("/tax-rules", taxHandler.GetRules)
+			admin.GET("/tax-rules/:id", taxHandler.GetRule)
+			admin.POST("/tax-rules", taxHandler.CreateRule)
+			admin.PUT("/tax-rules/:id", taxHandler.UpdateRule)
+			admin.DELETE("/tax-rules/:id", taxHandler.DeleteRule)
+			admin.GET("/tax-report", taxHandler.GetTaxReport)
+
+			// Exchange rates
+			admin.GET("/exchange-rates", currencyHandler.GetRates)
+			admin.POST("/exchange-rates", currencyHandler.CreateRate)
+			admin.DELETE("/exchange-rates/:id", currencyHandler.DeleteRate)
+
+			// Featured products curation
+			admin.GET("/featured", featuredHandler.GetSlots)
+			admin.POST("/featured", featuredHandler.CreateSlot)
+			admin.PUT("/featured/:id", featuredHandler.UpdateSlot)
+			admin.DELETE("/featured/:id", featuredHandler.DeleteSlot)
+
 			// Reviews moderation
 			admin.DELETE("/reviews/:id", reviewHandler.AdminDeleteReview)
 
+			// Product Q&A moderation
+			admin.GET("/questions", questionHandler.AdminGetQuestions)
+			admin.POST("/questions/:id/approve", questionHandler.ApproveQuestion)
+			admin.POST("/questions/:id/reject", questionHandler.RejectQuestion)
+			admin.DELETE("/questions/:id", questionHandler.AdminDeleteQuestion)
+			admin.DELETE("/answers/:id", questionHandler.AdminDeleteAnswer)
+
 			// Analytics & Dashboard
 			admin.GET("/analytics/dashboard", analyticsHandler.GetDashboardStats)
 			admin.GET("/analytics/revenue", analyticsHandler.GetRevenueStats)
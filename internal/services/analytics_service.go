@@ -43,21 +43,22 @@ func (s *analyticsService) GetDashboardStats() (map[string]interface{}, error) {
 	stats["total_users"] = len(users)
 
 	// Total products
-	products, _, _ := s.productRepo.GetAll(1, 100000, nil, "")
+	products, _, _ := s.productRepo.GetAll(1, 100000, models.ProductFilter{})
 	stats["total_products"] = len(products)
 
 	// Total orders
 	orders, totalOrders, _ := s.orderRepo.GetAll(1, 100000, "")
 	stats["total_orders"] = totalOrders
 
-	// Calculate total revenue
-	var totalRevenue float64
+	// Calculate total revenue, net of tax, in the base currency at the rate each order was charged at
+	var totalRevenue int64
 	for _, order := range orders {
 		if order.Status == "completed" {
-			totalRevenue += order.TotalAmount
+			totalRevenue += baseAmount(&order, order.FinalAmount-order.TaxAmount)
 		}
 	}
 	stats["total_revenue"] = totalRevenue
+	stats["currency"] = models.BaseCurrency
 
 	// Orders by status
 	ordersByStatus := make(map[string]int)
@@ -87,32 +88,34 @@ func (s *analyticsService) GetRevenueStats(startDate, endDate time.Time) (map[st
 		return nil, err
 	}
 
-	var totalRevenue float64
+	var totalRevenue int64
 	var completedOrders int
-	revenueByDate := make(map[string]float64)
+	revenueByDate := make(map[string]int64)
 
 	for _, order := range orders {
 		if order.Status == "completed" &&
 			order.CreatedAt.After(startDate) &&
 			order.CreatedAt.Before(endDate) {
 
-			totalRevenue += order.TotalAmount
+			amount := baseAmount(&order, order.FinalAmount-order.TaxAmount)
+			totalRevenue += amount
 			completedOrders++
 
 			// Group by date
 			dateKey := order.CreatedAt.Format("2006-01-02")
-			revenueByDate[dateKey] += order.TotalAmount
+			revenueByDate[dateKey] += amount
 		}
 	}
 
 	stats["total_revenue"] = totalRevenue
+	stats["currency"] = models.BaseCurrency
 	stats["completed_orders"] = completedOrders
 	stats["revenue_by_date"] = revenueByDate
 	stats["start_date"] = startDate.Format("2006-01-02")
 	stats["end_date"] = endDate.Format("2006-01-02")
 
 	if completedOrders > 0 {
-		stats["average_order_value"] = totalRevenue / float64(completedOrders)
+		stats["average_order_value"] = totalRevenue / int64(completedOrders)
 	} else {
 		stats["average_order_value"] = 0
 	}
@@ -131,16 +134,18 @@ func (s *analyticsService) GetTopProducts(limit int) ([]*models.Product, error)
 		return nil, err
 	}
 
-	// Count sales per product
+	// Count units sold per product
 	productSales := make(map[uint]int)
 	for _, order := range orders {
-		if order.ProductID != nil {
-			productSales[*order.ProductID]++
+		for _, item := range order.Items {
+			if item.ProductID != nil {
+				productSales[*item.ProductID] += item.Quantity
+			}
 		}
 	}
 
 	// Get products and sort by sales
-	products, _, err := s.productRepo.GetAll(1, 100000, nil, "")
+	products, _, err := s.productRepo.GetAll(1, 100000, models.ProductFilter{})
 	if err != nil {
 		return nil, err
 	}
@@ -241,3 +246,9 @@ func (s *analyticsService) GetOrderStats() (map[string]interface{}, error) {
 
 	return stats, nil
 }
+
+// baseAmount converts an amount of the order's currency to the base currency
+// using the rate locked in when the order was placed
+func baseAmount(order *models.Order, amount int64) int64 {
+	return convertAmount(amount, order.Currency, models.BaseCurrency, order.BaseRate)
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/pkg/utils"
+	"mime/multipart"
+)
+
+type BundleService interface {
+	CreateBundle(req models.BundleRequest, files []*multipart.FileHeader, createdBy uint) (*models.Bundle, error)
+	GetAllBundles(page, limit int, search string) ([]models.Bundle, int64, error)
+	GetBundleByID(id uint, includeInactive bool) (*models.Bundle, error)
+	GetBundleBySlug(slug string, includeInactive bool) (*models.Bundle, error)
+	ResolveSlugRedirect(oldSlug string) (string, error)
+	UpdateBundle(id uint, req models.BundleRequest, files []*multipart.FileHeader) (*models.Bundle, error)
+	DeleteBundle(id uint) error
+}
+
+type bundleService struct {
+	bundleRepo  repositories.BundleRepository
+	productRepo repositories.ProductRepository
+	slugRepo    repositories.SlugRedirectRepository
+}
+
+func NewBundleService(bundleRepo repositories.BundleRepository, productRepo repositories.ProductRepository, slugRepo repositories.SlugRedirectRepository) BundleService {
+	return &bundleService{
+		bundleRepo:  bundleRepo,
+		productRepo: productRepo,
+		slugRepo:    slugRepo,
+	}
+}
+
+func (s *bundleService) CreateBundle(req models.BundleRequest, files []*multipart.FileHeader, createdBy uint) (*models.Bundle, error) {
+	if req.Title == "" {
+		return nil, errors.New("bundle title is required")
+	}
+
+	slug, err := uniqueSlug(s.slugRepo, "bundle", generateSlug(req.Title), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.loadProducts(req.ProductIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &models.Bundle{
+		Title:         req.Title,
+		Slug:          slug,
+		Description:   req.Description,
+		Price:         req.Price,
+		PreviewImages: "[]",
+		IsActive:      true,
+		Products:      products,
+		CreatedBy:     createdBy,
+	}
+	if req.IsActive != nil {
+		bundle.IsActive = *req.IsActive
+	}
+
+	if err := validateBundlePrice(bundle); err != nil {
+		return nil, err
+	}
+
+	images, err := uploadBundleImages(files)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) > 0 {
+		bundle.PreviewImages = images
+	}
+
+	if err := s.bundleRepo.Create(bundle); err != nil {
+		deleteBundleImages(images)
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (s *bundleService) GetAllBundles(page, limit int, search string) ([]models.Bundle, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	bundles, total, err := s.bundleRepo.GetAll(page, limit, search, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range bundles {
+		bundles[i].Products = publishedProducts(bundles[i].Products)
+		bundles[i].OriginalPrice = sumProductPrices(bundles[i].Products)
+	}
+
+	return bundles, total, nil
+}
+
+// GetBundleByID returns the bundle; inactive bundles are only visible when includeInactive is set (admins)
+func (s *bundleService) GetBundleByID(id uint, includeInactive bool) (*models.Bundle, error) {
+	bundle, err := s.bundleRepo.GetByID(id)
+	if err != nil || (!bundle.IsActive && !includeInactive) {
+		return nil, errors.New("bundle not found")
+	}
+	bundle.Products = publishedProducts(bundle.Products)
+	bundle.OriginalPrice = sumProductPrices(bundle.Products)
+	return bundle, nil
+}
+
+func (s *bundleService) GetBundleBySlug(slug string, includeInactive bool) (*models.Bundle, error) {
+	bundle, err := s.bundleRepo.GetBySlug(slug)
+	if err != nil || (!bundle.IsActive && !includeInactive) {
+		return nil, errors.New("bundle not found")
+	}
+	bundle.Products = publishedProducts(bundle.Products)
+	bundle.OriginalPrice = sumProductPrices(bundle.Products)
+	return bundle, nil
+}
+
+// ResolveSlugRedirect returns the current slug of an active bundle that used to have oldSlug
+func (s *bundleService) ResolveSlugRedirect(oldSlug string) (string, error) {
+	id, err := s.slugRepo.Resolve("bundle", oldSlug)
+	if err != nil {
+		return "", errors.New("bundle not found")
+	}
+
+	bundle, err := s.bundleRepo.GetByID(id)
+	if err != nil || !bundle.IsActive {
+		return "", errors.New("bundle not found")
+	}
+	return bundle.Slug, nil
+}
+
+func (s *bundleService) UpdateBundle(id uint, req models.BundleRequest, files []*multipart.FileHeader) (*models.Bundle, error) {
+	bundle, err := s.bundleRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("bundle not found")
+	}
+
+	oldSlug := bundle.Slug
+	if req.Title != "" {
+		slug, err := uniqueSlug(s.slugRepo, "bundle", generateSlug(req.Title), bundle.ID)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Title = req.Title
+		bundle.Slug = slug
+	}
+	if req.Description != "" {
+		bundle.Description = req.Description
+	}
+	if req.Price > 0 {
+		bundle.Price = req.Price
+	}
+	if req.IsActive != nil {
+		bundle.IsActive = *req.IsActive
+	}
+	if len(req.ProductIDs) > 0 {
+		products, err := s.loadProducts(req.ProductIDs)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Products = products
+	}
+
+	if err := validateBundlePrice(bundle); err != nil {
+		return nil, err
+	}
+
+	images, err := uploadBundleImages(files)
+	if err != nil {
+		return nil, err
+	}
+	oldImages := bundle.PreviewImages
+	if len(images) > 0 {
+		bundle.PreviewImages = images
+	}
+
+	// Keep old links working after a rename
+	if err := s.bundleRepo.UpdateWithSlug(bundle, oldSlug); err != nil {
+		deleteBundleImages(images)
+		return nil, err
+	}
+
+	if len(images) > 0 {
+		deleteBundleImages(oldImages)
+	}
+
+	return bundle, nil
+}
+
+func (s *bundleService) DeleteBundle(id uint) error {
+	if _, err := s.bundleRepo.GetByID(id); err != nil {
+		return errors.New("bundle not found")
+	}
+	return s.bundleRepo.Delete(id)
+}
+
+// loadProducts resolves the bundle's product IDs, requiring at least two distinct published products
+func (s *bundleService) loadProducts(ids []uint) ([]models.Product, error) {
+	seen := make(map[uint]bool)
+	var products []models.Product
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		product, err := s.productRepo.GetByID(id)
+		if err != nil {
+			return nil, errors.New("product not found")
+		}
+		if product.Status != "published" {
+			return nil, errors.New("product " + product.Title + " is not available")
+		}
+		products = append(products, *product)
+	}
+
+	if len(products) < 2 {
+		return nil, errors.New("a bundle needs at least two products")
+	}
+
+	return products, nil
+}
+
+// validateBundlePrice checks the bundle is cheaper than buying its products
+// separately. The bundle is priced in the currency its products share.
+func validateBundlePrice(bundle *models.Bundle) error {
+	if bundle.Price <= 0 {
+		return errors.New("bundle price must be greater than 0")
+	}
+
+	bundle.Currency = bundle.Products[0].Currency
+	for _, p := range bundle.Products {
+		if p.Currency != bundle.Currency {
+			return errors.New("bundled products must be priced in the same currency")
+		}
+	}
+
+	bundle.OriginalPrice = sumProductPrices(bundle.Products)
+	if bundle.Price >= bundle.OriginalPrice {
+		return errors.New("bundle price must be lower than the combined product price")
+	}
+
+	return nil
+}
+
+// publishedProducts hides bundle items that are no longer published from public responses
+func publishedProducts(products []models.Product) []models.Product {
+	visible := make([]models.Product, 0, len(products))
+	for _, p := range products {
+		if p.Status == "published" {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
+
+// sumProductPrices adds up what the products cost when bought separately
+func sumProductPrices(products []models.Product) int64 {
+	var total int64
+	for _, p := range products {
+		if p.DiscountPrice != nil && *p.DiscountPrice > 0 {
+			total += *p.DiscountPrice
+		} else {
+			total += p.Price
+		}
+	}
+	return total
+}
+
+// uploadBundleImages stores the uploaded images and returns them as a JSON array
+func uploadBundleImages(files []*multipart.FileHeader) (string, error) {
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	var paths []string
+	for _, file := range files {
+		path, err := utils.UploadFile(file, "bundles")
+		if err != nil {
+			for _, p := range paths {
+				utils.DeleteFile(p)
+			}
+			return "", err
+		}
+		paths = append(paths, path)
+	}
+
+	data, _ := json.Marshal(paths)
+	return string(data), nil
+}
+
+func deleteBundleImages(images string) {
+	var paths []string
+	if json.Unmarshal([]byte(images), &paths) != nil {
+		return
+	}
+	for _, p := range paths {
+		utils.DeleteFile(p)
+	}
+}
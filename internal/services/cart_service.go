@@ -4,29 +4,141 @@ import (
 	"errors"
 	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/repositories"
+	"gin-quickstart/pkg/utils"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type CartService interface {
-	AddToCart(userID, productID uint, quantity int) (*models.Cart, error)
-	GetUserCart(userID uint) ([]models.Cart, float64, error)
-	UpdateCartItem(userID, cartID uint, quantity int) (*models.Cart, error)
-	RemoveFromCart(userID, cartID uint) error
-	ClearCart(userID uint) error
+	AddToCart(owner models.CartOwner, productID uint, licenseTierID *uint, quantity int) (*models.Cart, error)
+	GetCart(owner models.CartOwner, currency, couponCode string) (*models.CartSummary, error)
+	UpdateCartItem(owner models.CartOwner, cartID uint, quantity int) (*models.Cart, error)
+	RemoveFromCart(owner models.CartOwner, cartID uint) error
+	ClearCart(owner models.CartOwner) error
+	GuestCart(token string, create bool) (models.CartOwner, string, error)
+	MergeGuestCart(token string, userID uint) error
+	ExpireGuestCarts() error
 }
 
 type cartService struct {
-	cartRepo    repositories.CartRepository
-	productRepo repositories.ProductRepository
+	cartRepo     repositories.CartRepository
+	productRepo  repositories.ProductRepository
+	tierRepo     repositories.LicenseTierRepository
+	campaigns    SaleCampaignService
+	coupons      CouponService
+	taxes        TaxService
+	currencies   CurrencyService
+	tokenSecret  string
+	guestCartTTL time.Duration
+}
+
+func NewCartService(
+	cartRepo repositories.CartRepository,
+	productRepo repositories.ProductRepository,
+	tierRepo repositories.LicenseTierRepository,
+	campaigns SaleCampaignService,
+	coupons CouponService,
+	taxes TaxService,
+	currencies CurrencyService,
+	tokenSecret string,
+	guestCartTTL time.Duration,
+	cleanupInterval time.Duration,
+) CartService {
+	s := &cartService{
+		cartRepo:     cartRepo,
+		productRepo:  productRepo,
+		tierRepo:     tierRepo,
+		campaigns:    campaigns,
+		coupons:      coupons,
+		taxes:        taxes,
+		currencies:   currencies,
+		tokenSecret:  tokenSecret,
+		guestCartTTL: guestCartTTL,
+	}
+
+	// Remove guest carts abandoned for longer than the TTL
+	go s.run(cleanupInterval)
+
+	return s
+}
+
+func (s *cartService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.ExpireGuestCarts(); err != nil {
+			log.Println("Failed to expire guest carts:", err)
+		}
+	}
+}
+
+func (s *cartService) ExpireGuestCarts() error {
+	count, err := s.cartRepo.DeleteExpiredGuestCarts(time.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("Expired %d guest cart(s)", count)
+	}
+	return nil
+}
+
+// GuestCart resolves a visitor's cart token and extends the cart's life. When
+// the token is missing, invalid or its cart expired, create starts a new guest
+// cart; otherwise the owner is empty and holds no items. The returned token
+// identifies the cart and is empty when there is none.
+func (s *cartService) GuestCart(token string, create bool) (models.CartOwner, string, error) {
+	expiresAt := time.Now().Add(s.guestCartTTL)
+
+	if token != "" {
+		if id, err := utils.VerifyCartToken(token, s.tokenSecret); err == nil {
+			ok, err := s.cartRepo.TouchGuestCart(id, expiresAt)
+			if err != nil {
+				return models.CartOwner{}, "", err
+			}
+			if ok {
+				return models.CartOwner{GuestCartID: id}, utils.SignCartToken(id, s.tokenSecret), nil
+			}
+		}
+	}
+
+	if !create {
+		return models.CartOwner{}, "", nil
+	}
+
+	cart := &models.GuestCart{
+		ID:        uuid.NewString(),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.cartRepo.CreateGuestCart(cart); err != nil {
+		return models.CartOwner{}, "", err
+	}
+	return models.CartOwner{GuestCartID: cart.ID}, utils.SignCartToken(cart.ID, s.tokenSecret), nil
+}
+
+// MergeGuestCart moves the items of the guest cart the token identifies into
+// the user's cart, without duplicating products the user already has. Invalid
+// tokens and expired carts leave nothing to merge.
+func (s *cartService) MergeGuestCart(token string, userID uint) error {
+	owner, _, err := s.GuestCart(token, false)
+	if err != nil || owner.GuestCartID == "" {
+		return err
+	}
+	return s.cartRepo.MergeGuestCart(owner.GuestCartID, userID)
 }
 
-func NewCartService(cartRepo repositories.CartRepository, productRepo repositories.ProductRepository) CartService {
-	return &cartService{
-		cartRepo:    cartRepo,
-		productRepo: productRepo,
+// ownsCart reports whether the cart item belongs to the owner's cart
+func ownsCart(owner models.CartOwner, cart *models.Cart) bool {
+	if owner.UserID != 0 {
+		return cart.UserID != nil && *cart.UserID == owner.UserID
 	}
+	return owner.GuestCartID != "" && cart.GuestCartID != nil && *cart.GuestCartID == owner.GuestCartID
 }
 
-func (s *cartService) AddToCart(userID, productID uint, quantity int) (*models.Cart, error) {
+func (s *cartService) AddToCart(owner models.CartOwner, productID uint, licenseTierID *uint, quantity int) (*models.Cart, error) {
 	if quantity <= 0 {
 		return nil, errors.New("quantity must be greater than 0")
 	}
@@ -36,11 +148,25 @@ func (s *cartService) AddToCart(userID, productID uint, quantity int) (*models.C
 		return nil, errors.New("product not found")
 	}
 
-	if !product.IsActive {
+	if product.Status != "published" {
 		return nil, errors.New("product is not available")
 	}
 
-	existingCart, err := s.cartRepo.GetByUserAndProduct(userID, productID)
+	tier, err := resolveLicenseTier(s.tierRepo, product, licenseTierID)
+	if err != nil {
+		return nil, err
+	}
+	if tier != nil {
+		licenseTierID = &tier.ID
+	} else {
+		licenseTierID = nil
+	}
+
+	if owner.UserID == 0 && owner.GuestCartID == "" {
+		return nil, errors.New("cart not found")
+	}
+
+	existingCart, err := s.cartRepo.GetByOwnerAndProduct(owner, productID, licenseTierID)
 	if err == nil {
 		newQuantity := existingCart.Quantity + quantity
 		existingCart.Quantity = newQuantity
@@ -51,9 +177,14 @@ func (s *cartService) AddToCart(userID, productID uint, quantity int) (*models.C
 	}
 
 	cart := &models.Cart{
-		UserID:    userID,
-		ProductID: productID,
-		Quantity:  quantity,
+		ProductID:     productID,
+		LicenseTierID: licenseTierID,
+		Quantity:      quantity,
+	}
+	if owner.UserID != 0 {
+		cart.UserID = &owner.UserID
+	} else {
+		cart.GuestCartID = &owner.GuestCartID
 	}
 
 	if err := s.cartRepo.Create(cart); err != nil {
@@ -63,21 +194,80 @@ func (s *cartService) AddToCart(userID, productID uint, quantity int) (*models.C
 	return cart, nil
 }
 
-func (s *cartService) GetUserCart(userID uint) ([]models.Cart, float64, error) {
-	carts, err := s.cartRepo.GetByUserID(userID)
+// GetCart returns the cart totalled in the given currency. Without one the
+// total is in the currency the items share, or the base currency when they differ.
+// A coupon code previews the discount it would give at checkout.
+func (s *cartService) GetCart(owner models.CartOwner, currency, couponCode string) (*models.CartSummary, error) {
+	carts, err := s.cartRepo.GetByOwner(owner)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	var total float64
-	for _, cart := range carts {
-		total += cart.Product.Price * float64(cart.Quantity)
+	products := make([]*models.Product, len(carts))
+	for i := range carts {
+		products[i] = carts[i].Product
+	}
+	if err := s.campaigns.ApplySalePrices(products...); err != nil {
+		return nil, err
+	}
+	if err := s.currencies.ApplyDisplay(currency, products...); err != nil {
+		return nil, err
 	}
 
-	return carts, total, nil
+	if currency == "" {
+		currency = models.BaseCurrency
+		if len(carts) > 0 {
+			currency = carts[0].Product.Currency
+		}
+		for _, cart := range carts {
+			if cart.Product.Currency != currency {
+				currency = models.BaseCurrency
+				break
+			}
+		}
+	}
+
+	now := time.Now()
+	order := &models.Order{
+		UserID:   owner.UserID,
+		Currency: currency,
+		Items:    make([]models.OrderItem, len(carts)),
+	}
+	for i, cart := range carts {
+		rate, err := s.currencies.Rate(cart.Product.Currency, currency, now)
+		if err != nil {
+			return nil, err
+		}
+		amount := unitPrice(cart.Product, cart.LicenseTier) * int64(cart.Quantity)
+		order.Items[i] = models.OrderItem{
+			ProductID: &carts[i].ProductID,
+			Amount:    convertAmount(amount, cart.Product.Currency, currency, rate),
+		}
+		order.TotalAmount += order.Items[i].Amount
+	}
+	order.FinalAmount = order.TotalAmount
+
+	summary := &models.CartSummary{
+		Items:    carts,
+		Currency: currency,
+		Total:    order.TotalAmount,
+	}
+	if couponCode != "" && len(carts) > 0 {
+		summary.CouponCode = normalizeCouponCode(couponCode)
+		if err := s.coupons.ApplyCoupon(order, couponCode); err != nil {
+			summary.CouponError = err.Error()
+		}
+	}
+	if err := s.taxes.ApplyTax(order); err != nil {
+		return nil, err
+	}
+	summary.Discount = order.DiscountAmount
+	summary.Tax = order.TaxAmount
+	summary.FinalTotal = order.FinalAmount
+	return summary, nil
 }
 
-func (s *cartService) UpdateCartItem(userID, cartID uint, quantity int) (*models.Cart, error) {
+func (s *cartService) UpdateCartItem(owner models.CartOwner, cartID uint, quantity int) (*models.Cart, error) {
 	if quantity <= 0 {
 		return nil, errors.New("quantity must be greater than 0")
 	}
@@ -87,7 +277,7 @@ func (s *cartService) UpdateCartItem(userID, cartID uint, quantity int) (*models
 		return nil, errors.New("cart item not found")
 	}
 
-	if cart.UserID != userID {
+	if !ownsCart(owner, cart) {
 		return nil, errors.New("unauthorized")
 	}
 
@@ -104,19 +294,22 @@ func (s *cartService) UpdateCartItem(userID, cartID uint, quantity int) (*models
 	return cart, nil
 }
 
-func (s *cartService) RemoveFromCart(userID, cartID uint) error {
+func (s *cartService) RemoveFromCart(owner models.CartOwner, cartID uint) error {
 	cart, err := s.cartRepo.GetByID(cartID)
 	if err != nil {
 		return errors.New("cart item not found")
 	}
 
-	if cart.UserID != userID {
+	if !ownsCart(owner, cart) {
 		return errors.New("unauthorized")
 	}
 
 	return s.cartRepo.Delete(cartID)
 }
 
-func (s *cartService) ClearCart(userID uint) error {
-	return s.cartRepo.DeleteByUserID(userID)
+func (s *cartService) ClearCart(owner models.CartOwner) error {
+	if owner.UserID == 0 && owner.GuestCartID == "" {
+		return nil
+	}
+	return s.cartRepo.DeleteByOwner(owner)
 }
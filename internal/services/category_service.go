@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/repositories"
 	"strings"
@@ -9,52 +10,186 @@ import (
 )
 
 type CategoryService interface {
-	CreateCategory(name, description string) (*models.Category, error)
+	CreateCategory(req models.CategoryRequest) (*models.Category, error)
 	GetAllCategories() ([]models.Category, error)
+	GetCategoryTree() ([]models.Category, error)
 	GetCategoryByID(id uint) (*models.Category, error)
 	GetCategoryBySlug(slug string) (*models.Category, error)
-	UpdateCategory(id uint, name, description string) (*models.Category, error)
-	DeleteCategory(id uint) error
+	ResolveSlugRedirect(oldSlug string) (string, error)
+	UpdateCategory(id uint, req models.CategoryUpdateRequest) (*models.Category, error)
+	MoveCategory(id uint, req models.CategoryMoveRequest) (*models.Category, error)
+	ReorderCategories(req models.CategoryReorderRequest) ([]models.Category, error)
+	DeleteCategory(id, deletedBy uint, reassignTo *uint) (*models.CategoryDeleteConflict, error)
 }
 
 type categoryService struct {
 	categoryRepo repositories.CategoryRepository
+	slugRepo     repositories.SlugRedirectRepository
 }
 
-func NewCategoryService(categoryRepo repositories.CategoryRepository) CategoryService {
+func NewCategoryService(categoryRepo repositories.CategoryRepository, slugRepo repositories.SlugRedirectRepository) CategoryService {
 	return &categoryService{
 		categoryRepo: categoryRepo,
+		slugRepo:     slugRepo,
 	}
 }
 
-func (s *categoryService) CreateCategory(name, description string) (*models.Category, error) {
-	if name == "" {
+func (s *categoryService) CreateCategory(req models.CategoryRequest) (*models.Category, error) {
+	if req.Name == "" {
 		return nil, errors.New("category name is required")
 	}
 
-	slug := generateSlug(name)
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(*req.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+	}
 
-	// Check if slug already exists
-	existing, _ := s.categoryRepo.GetBySlug(slug)
-	if existing != nil {
-		return nil, errors.New("category with this name already exists")
+	slug, err := uniqueSlug(s.slugRepo, "category", generateSlug(req.Name), 0)
+	if err != nil {
+		return nil, err
 	}
 
 	category := &models.Category{
-		Name:        name,
+		Name:        req.Name,
 		Slug:        slug,
-		Description: description,
+		Description: req.Description,
+		Icon:        req.Icon,
+		ParentID:    req.ParentID,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	// New categories go after their siblings unless placed explicitly
+	if req.Order != nil {
+		category.Order = *req.Order
+	} else if category.Order, err = s.categoryRepo.NextOrder(req.ParentID); err != nil {
+		return nil, err
 	}
 
 	if err := s.categoryRepo.Create(category); err != nil {
 		return nil, err
 	}
 
+	// GORM writes the column default in place of a false value on create
+	if req.IsActive != nil && !*req.IsActive {
+		category.IsActive = false
+		if err := s.categoryRepo.Update(category); err != nil {
+			return nil, err
+		}
+	}
+
 	return category, nil
 }
 
 func (s *categoryService) GetAllCategories() ([]models.Category, error) {
-	return s.categoryRepo.GetAll()
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	setTotalProductCounts(categories)
+	return categories, nil
+}
+
+// GetCategoryTree returns the top-level categories with their descendants
+// nested in Children, siblings in display order
+func (s *categoryService) GetCategoryTree() ([]models.Category, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	setTotalProductCounts(categories)
+	return buildCategoryTree(categories), nil
+}
+
+// setTotalProductCounts adds up the product counts of each category's subtree
+// from the full category list
+func setTotalProductCounts(categories []models.Category) {
+	children := make(map[uint][]int)
+	for i, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], i)
+		}
+	}
+
+	totals := make(map[int]int, len(categories))
+	var total func(i int, depth int) int
+	total = func(i int, depth int) int {
+		if t, ok := totals[i]; ok {
+			return t
+		}
+		sum := categories[i].ProductCount
+		// Depth guards against a corrupt parent cycle
+		if depth < len(categories) {
+			for _, child := range children[categories[i].ID] {
+				sum += total(child, depth+1)
+			}
+		}
+		totals[i] = sum
+		return sum
+	}
+
+	for i := range categories {
+		t := total(i, 0)
+		categories[i].TotalProductCount = &t
+	}
+}
+
+// withTotalProductCount sets the product count of a single category's subtree
+func (s *categoryService) withTotalProductCount(category *models.Category) (*models.Category, error) {
+	total, err := s.categoryRepo.SubtreeProductCount(category.ID)
+	if err != nil {
+		return nil, err
+	}
+	category.TotalProductCount = &total
+	return category, nil
+}
+
+// buildCategoryTree nests an ordered flat list of categories under their parents
+func buildCategoryTree(categories []models.Category) []models.Category {
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	var attach func(nodes []models.Category) []models.Category
+	attach = func(nodes []models.Category) []models.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	tree := attach(roots)
+	if tree == nil {
+		tree = []models.Category{}
+	}
+	return tree
+}
+
+// categoryBreadcrumbs returns the path from the top level to the category,
+// itself included
+func categoryBreadcrumbs(byID map[uint]models.Category, id uint) []models.CategoryBreadcrumb {
+	var path []models.CategoryBreadcrumb
+	for steps := 0; steps <= len(byID); steps++ {
+		category, ok := byID[id]
+		if !ok {
+			break
+		}
+		path = append([]models.CategoryBreadcrumb{{ID: category.ID, Name: category.Name, Slug: category.Slug}}, path...)
+		if category.ParentID == nil {
+			break
+		}
+		id = *category.ParentID
+	}
+	return path
 }
 
 func (s *categoryService) GetCategoryByID(id uint) (*models.Category, error) {
@@ -62,7 +197,7 @@ func (s *categoryService) GetCategoryByID(id uint) (*models.Category, error) {
 	if err != nil {
 		return nil, errors.New("category not found")
 	}
-	return category, nil
+	return s.withTotalProductCount(category)
 }
 
 func (s *categoryService) GetCategoryBySlug(slug string) (*models.Category, error) {
@@ -70,38 +205,175 @@ func (s *categoryService) GetCategoryBySlug(slug string) (*models.Category, erro
 	if err != nil {
 		return nil, errors.New("category not found")
 	}
-	return category, nil
+	return s.withTotalProductCount(category)
+}
+
+// ResolveSlugRedirect returns the current slug of a category that used to have oldSlug
+func (s *categoryService) ResolveSlugRedirect(oldSlug string) (string, error) {
+	id, err := s.slugRepo.Resolve("category", oldSlug)
+	if err != nil {
+		return "", errors.New("category not found")
+	}
+
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return "", errors.New("category not found")
+	}
+	return category.Slug, nil
 }
 
-func (s *categoryService) UpdateCategory(id uint, name, description string) (*models.Category, error) {
+func (s *categoryService) UpdateCategory(id uint, req models.CategoryUpdateRequest) (*models.Category, error) {
 	category, err := s.categoryRepo.GetByID(id)
 	if err != nil {
 		return nil, errors.New("category not found")
 	}
 
-	if name != "" {
-		category.Name = name
-		category.Slug = generateSlug(name)
+	oldSlug := category.Slug
+	if req.Name != "" && req.Name != category.Name {
+		slug, err := uniqueSlug(s.slugRepo, "category", generateSlug(req.Name), category.ID)
+		if err != nil {
+			return nil, err
+		}
+		category.Name = req.Name
+		category.Slug = slug
 	}
 
-	if description != "" {
-		category.Description = description
+	if req.Description != "" {
+		category.Description = req.Description
+	}
+	if req.Icon != "" {
+		category.Icon = req.Icon
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
 	}
 
-	if err := s.categoryRepo.Update(category); err != nil {
+	// Keep old links working after a rename
+	if err := s.categoryRepo.UpdateWithSlug(category, oldSlug); err != nil {
 		return nil, err
 	}
 
 	return category, nil
 }
 
-func (s *categoryService) DeleteCategory(id uint) error {
+// MoveCategory moves a category with its whole subtree under another parent,
+// placing it after the new siblings
+func (s *categoryService) MoveCategory(id uint, req models.CategoryMoveRequest) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+
+	if req.ParentID != nil {
+		if *req.ParentID == id {
+			return nil, errors.New("a category cannot be its own parent")
+		}
+		if _, err := s.categoryRepo.GetByID(*req.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+	}
+
+	if sameParent(category.ParentID, req.ParentID) {
+		return category, nil
+	}
+
+	moved, err := s.categoryRepo.Move(id, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, errors.New("a category cannot be moved under one of its own descendants")
+	}
+
+	return s.categoryRepo.GetByID(id)
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// ReorderCategories sets the display order of the children of a parent. The
+// ids must list every child exactly once.
+func (s *categoryService) ReorderCategories(req models.CategoryReorderRequest) ([]models.Category, error) {
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(*req.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+	}
+
+	children, err := s.categoryRepo.GetChildren(req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint]bool, len(children))
+	for _, child := range children {
+		current[child.ID] = true
+	}
+
+	seen := make(map[uint]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if !current[id] {
+			return nil, fmt.Errorf("category %d is not a child of this parent", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("category %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(current) {
+		return nil, errors.New("ids must list every child of the parent")
+	}
+
+	if err := s.categoryRepo.Reorder(req.IDs); err != nil {
+		return nil, err
+	}
+	return s.categoryRepo.GetChildren(req.ParentID)
+}
+
+// DeleteCategory moves a category to the trash. Its products and subcategories
+// go to the reassignTo category; without one, a category that still has any is
+// not deleted and the returned conflict lists them.
+func (s *categoryService) DeleteCategory(id, deletedBy uint, reassignTo *uint) (*models.CategoryDeleteConflict, error) {
 	category, err := s.categoryRepo.GetByID(id)
 	if err != nil {
-		return errors.New("category not found")
+		return nil, errors.New("category not found")
 	}
 
-	return s.categoryRepo.Delete(category.ID)
+	if reassignTo != nil {
+		if *reassignTo == id {
+			return nil, errors.New("cannot reassign products to the category being deleted")
+		}
+		if _, err := s.categoryRepo.GetByID(*reassignTo); err != nil {
+			return nil, errors.New("reassignment category not found")
+		}
+
+		deleted, err := s.categoryRepo.DeleteReassigning(category.ID, *reassignTo, deletedBy)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			return nil, errors.New("cannot reassign to a subcategory of the category being deleted")
+		}
+		return nil, nil
+	}
+
+	deleted, err := s.categoryRepo.Delete(category.ID, deletedBy)
+	if err != nil || deleted {
+		return nil, err
+	}
+
+	conflict := &models.CategoryDeleteConflict{}
+	if conflict.Products, conflict.ProductCount, err = s.categoryRepo.GetProducts(category.ID, models.CategoryConflictLimit); err != nil {
+		return nil, err
+	}
+	if conflict.Subcategories, err = s.categoryRepo.GetChildren(&category.ID); err != nil {
+		return nil, err
+	}
+	return conflict, nil
 }
 
 // generateSlug creates URL-friendly slug from name
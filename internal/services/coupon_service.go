@@ -0,0 +1,345 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type CouponService interface {
+	CreateCoupon(req models.CouponRequest, createdBy uint) (*models.Coupon, error)
+	GetCoupons(page, limit int, search string) ([]models.Coupon, int64, error)
+	GetCoupon(id uint) (*models.Coupon, error)
+	UpdateCoupon(id uint, req models.CouponRequest) (*models.Coupon, error)
+	DeleteCoupon(id uint) error
+	GetRedemptions(id uint, page, limit int) ([]models.CouponRedemption, int64, error)
+	GetUsageReport(startDate, endDate time.Time) ([]models.CouponUsage, error)
+	ApplyCoupon(order *models.Order, code string) error
+}
+
+type couponService struct {
+	couponRepo   repositories.CouponRepository
+	orderRepo    repositories.OrderRepository
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+	currencies   CurrencyService
+}
+
+func NewCouponService(
+	couponRepo repositories.CouponRepository,
+	orderRepo repositories.OrderRepository,
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	currencies CurrencyService,
+) CouponService {
+	return &couponService{
+		couponRepo:   couponRepo,
+		orderRepo:    orderRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		currencies:   currencies,
+	}
+}
+
+// normalizeCouponCode makes codes case-insensitive
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (s *couponService) CreateCoupon(req models.CouponRequest, createdBy uint) (*models.Coupon, error) {
+	coupon := &models.Coupon{
+		IsActive:  true,
+		CreatedBy: createdBy,
+	}
+	if err := s.applyRequest(coupon, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Create(coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+func (s *couponService) GetCoupons(page, limit int, search string) ([]models.Coupon, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.couponRepo.GetAll(page, limit, strings.TrimSpace(search))
+}
+
+func (s *couponService) GetCoupon(id uint) (*models.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("coupon not found")
+	}
+	return coupon, nil
+}
+
+func (s *couponService) UpdateCoupon(id uint, req models.CouponRequest) (*models.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("coupon not found")
+	}
+
+	if err := s.applyRequest(coupon, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Update(coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+func (s *couponService) DeleteCoupon(id uint) error {
+	if _, err := s.couponRepo.GetByID(id); err != nil {
+		return errors.New("coupon not found")
+	}
+	return s.couponRepo.Delete(id)
+}
+
+func (s *couponService) GetRedemptions(id uint, page, limit int) ([]models.CouponRedemption, int64, error) {
+	if _, err := s.couponRepo.GetByID(id); err != nil {
+		return nil, 0, errors.New("coupon not found")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.couponRepo.GetRedemptions(id, page, limit)
+}
+
+// GetUsageReport summarizes each coupon's redemptions made in the period,
+// with amounts converted to the base currency at each order's locked rate
+func (s *couponService) GetUsageReport(startDate, endDate time.Time) ([]models.CouponUsage, error) {
+	if !endDate.After(startDate) {
+		return nil, errors.New("end_date must be after start_date")
+	}
+
+	redemptions, err := s.couponRepo.GetRedemptionsBetween(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	byCoupon := make(map[uint]*models.CouponUsage)
+	users := make(map[uint]map[uint]bool)
+	for _, redemption := range redemptions {
+		usage, ok := byCoupon[redemption.CouponID]
+		if !ok {
+			usage = &models.CouponUsage{
+				CouponID: redemption.CouponID,
+				Currency: models.BaseCurrency,
+			}
+			if redemption.Coupon != nil {
+				usage.Code = redemption.Coupon.Code
+				usage.UsageLimit = redemption.Coupon.UsageLimit
+			}
+			byCoupon[redemption.CouponID] = usage
+			users[redemption.CouponID] = make(map[uint]bool)
+		}
+
+		usage.Redemptions++
+		users[redemption.CouponID][redemption.UserID] = true
+		if order := redemption.Order; order != nil {
+			usage.TotalDiscount += baseAmount(order, redemption.DiscountAmount)
+			if order.PaymentStatus == "paid" {
+				usage.PaidRedemptions++
+				usage.PaidRevenue += baseAmount(order, order.FinalAmount-order.TaxAmount)
+			}
+		}
+	}
+
+	report := make([]models.CouponUsage, 0, len(byCoupon))
+	for id, usage := range byCoupon {
+		usage.UniqueUsers = int64(len(users[id]))
+		report = append(report, *usage)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Redemptions != report[j].Redemptions {
+			return report[i].Redemptions > report[j].Redemptions
+		}
+		return report[i].CouponID < report[j].CouponID
+	})
+	return report, nil
+}
+
+// ApplyCoupon discounts a priced order with the coupon: it checks the coupon
+// is valid now and for the order's user, then spreads the discount over the
+// items in the coupon's scope. The limits are enforced again when the order
+// is placed, so concurrent checkouts cannot overuse the coupon.
+func (s *couponService) ApplyCoupon(order *models.Order, code string) error {
+	coupon, err := s.couponRepo.GetByCode(normalizeCouponCode(code))
+	if err != nil || !coupon.IsActive {
+		return errors.New("coupon not found")
+	}
+
+	now := time.Now()
+	if (coupon.StartsAt != nil && now.Before(*coupon.StartsAt)) || (coupon.EndsAt != nil && !now.Before(*coupon.EndsAt)) {
+		return errors.New("coupon is not valid at this time")
+	}
+	if coupon.UsageLimit != nil && coupon.UsedCount >= *coupon.UsageLimit {
+		return errors.New("coupon usage limit has been reached")
+	}
+
+	// Guests only see a preview; the user rules apply once they check out
+	if order.UserID != 0 {
+		if coupon.PerUserLimit != nil {
+			used, err := s.couponRepo.CountUserRedemptions(coupon.ID, order.UserID)
+			if err != nil {
+				return err
+			}
+			if used >= int64(*coupon.PerUserLimit) {
+				return errors.New("you have already used this coupon")
+			}
+		}
+		if coupon.FirstPurchaseOnly {
+			ordered, err := s.orderRepo.HasOrders(order.UserID)
+			if err != nil {
+				return err
+			}
+			if ordered {
+				return errors.New("coupon is only valid on your first purchase")
+			}
+		}
+	}
+
+	var productIDs []uint
+	for _, item := range order.Items {
+		if item.ProductID != nil {
+			productIDs = append(productIDs, *item.ProductID)
+		}
+	}
+	eligible, err := s.couponRepo.EligibleProducts(coupon, productIDs)
+	if err != nil {
+		return err
+	}
+
+	// Bundles are discounted by store-wide coupons only
+	var lines []*models.OrderItem
+	var subtotal int64
+	for i := range order.Items {
+		item := &order.Items[i]
+		if (item.ProductID != nil && eligible[*item.ProductID]) || (item.BundleID != nil && coupon.Scope == "store") {
+			lines = append(lines, item)
+			subtotal += item.Amount
+		}
+	}
+	if subtotal == 0 {
+		return errors.New("coupon does not apply to the items in this order")
+	}
+
+	rate, err := s.currencies.Rate(coupon.Currency, order.Currency, now)
+	if err != nil {
+		return err
+	}
+	if subtotal < convertAmount(coupon.MinOrderAmount, coupon.Currency, order.Currency, rate) {
+		return errors.New("order does not reach the coupon's minimum amount")
+	}
+
+	var discount int64
+	if coupon.DiscountType == "percentage" {
+		discount = subtotal - discountedPrice(coupon.DiscountType, coupon.DiscountValue, subtotal)
+	} else {
+		discount = convertAmount(coupon.DiscountValue, coupon.Currency, order.Currency, rate)
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+
+	// Spread the discount in proportion to the lines, the last one taking the rounding rest
+	remaining := discount
+	for i, item := range lines {
+		share := remaining
+		if i < len(lines)-1 {
+			share = int64(math.Round(float64(discount) * float64(item.Amount) / float64(subtotal)))
+			if share > remaining {
+				share = remaining
+			}
+		}
+		item.DiscountAmount = share
+		remaining -= share
+	}
+
+	order.CouponID = &coupon.ID
+	order.CouponCode = coupon.Code
+	order.DiscountAmount = discount
+	order.FinalAmount = order.TotalAmount - discount
+	return nil
+}
+
+// checkCode rejects codes of other coupons. Codes of deleted coupons stay
+// taken so their redemptions remain unambiguous.
+func (s *couponService) checkCode(coupon *models.Coupon) error {
+	taken, err := s.couponRepo.CodeTaken(coupon.Code, coupon.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("coupon code already exists")
+	}
+	return nil
+}
+
+func (s *couponService) applyRequest(coupon *models.Coupon, req models.CouponRequest) error {
+	code := normalizeCouponCode(req.Code)
+	if code == "" || strings.ContainsAny(code, " \t") {
+		return errors.New("code must not be empty or contain spaces")
+	}
+	if req.DiscountType == "percentage" && req.DiscountValue > 100 {
+		return errors.New("percentage discount cannot exceed 100")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	currency := models.BaseCurrency
+	if req.Currency != "" {
+		var err error
+		if currency, err = normalizeCurrency(req.Currency); err != nil {
+			return err
+		}
+	}
+
+	coupon.Code = code
+	coupon.Description = req.Description
+	coupon.DiscountType = req.DiscountType
+	coupon.DiscountValue = req.DiscountValue
+	coupon.Currency = currency
+	coupon.MinOrderAmount = req.MinOrderAmount
+	coupon.Scope = req.Scope
+	coupon.UsageLimit = req.UsageLimit
+	coupon.PerUserLimit = req.PerUserLimit
+	coupon.FirstPurchaseOnly = req.FirstPurchaseOnly
+	coupon.StartsAt = req.StartsAt
+	coupon.EndsAt = req.EndsAt
+	if req.IsActive != nil {
+		coupon.IsActive = *req.IsActive
+	}
+
+	products, categories, err := resolveScopeTargets(s.productRepo, s.categoryRepo, "coupons", req.Scope, req.ProductIDs, req.CategoryIDs)
+	if err != nil {
+		return err
+	}
+	coupon.Products = products
+	coupon.Categories = categories
+
+	return nil
+}
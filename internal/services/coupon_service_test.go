@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"testing"
+	"time"
+)
+
+// fakeCouponRepo serves a single coupon. Products are eligible when listed in
+// eligible, whatever the coupon's scope.
+type fakeCouponRepo struct {
+	repositories.CouponRepository
+	coupon      models.Coupon
+	eligible    map[uint]bool
+	redemptions int64
+}
+
+func (r *fakeCouponRepo) GetByCode(code string) (*models.Coupon, error) {
+	if code != r.coupon.Code {
+		return nil, errors.New("record not found")
+	}
+	coupon := r.coupon
+	return &coupon, nil
+}
+
+func (r *fakeCouponRepo) EligibleProducts(coupon *models.Coupon, productIDs []uint) (map[uint]bool, error) {
+	return r.eligible, nil
+}
+
+func (r *fakeCouponRepo) CountUserRedemptions(couponID, userID uint) (int64, error) {
+	return r.redemptions, nil
+}
+
+type fakeOrderRepo struct {
+	repositories.OrderRepository
+	hasOrders bool
+}
+
+func (r *fakeOrderRepo) HasOrders(userID uint) (bool, error) {
+	return r.hasOrders, nil
+}
+
+// fakeCurrencies converts with the rates keyed by "FROM/TO"
+type fakeCurrencies struct {
+	CurrencyService
+	rates map[string]float64
+}
+
+func (c *fakeCurrencies) Rate(from, to string, at time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rate, ok := c.rates[from+"/"+to]
+	if !ok {
+		return 0, errors.New("exchange rate not found")
+	}
+	return rate, nil
+}
+
+func TestApplyCoupon(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	one := 1
+	ten := 10
+
+	store := models.Coupon{ID: 1, Code: "HEMAT", DiscountType: "percentage", DiscountValue: 10, Currency: "IDR", Scope: "store", IsActive: true}
+	fixed := models.Coupon{ID: 2, Code: "POTONG", DiscountType: "fixed", DiscountValue: 5000, Currency: "IDR", Scope: "product", IsActive: true}
+
+	tests := []struct {
+		name         string
+		coupon       models.Coupon
+		code         string
+		eligible     map[uint]bool
+		redemptions  int64
+		hasOrders    bool
+		userID       uint
+		amounts      []int64 // product lines, product ids 1..n
+		bundle       int64   // amount of an extra bundle line, 0 for none
+		wantErr      bool
+		wantDiscount int64
+		wantShares   []int64 // DiscountAmount per line, bundle last
+	}{
+		{
+			name:   "percentage spread over lines",
+			coupon: store, code: "hemat", eligible: map[uint]bool{1: true, 2: true},
+			amounts: []int64{30000, 10000}, wantDiscount: 4000, wantShares: []int64{3000, 1000},
+		},
+		{
+			name:   "store coupon discounts bundles",
+			coupon: store, code: "HEMAT", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, bundle: 10000, wantDiscount: 4000, wantShares: []int64{3000, 1000},
+		},
+		{
+			name:   "product coupon skips other lines and bundles",
+			coupon: fixed, code: "POTONG", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000, 10000}, bundle: 10000, wantDiscount: 5000, wantShares: []int64{5000, 0, 0},
+		},
+		{
+			name:   "fixed discount is capped at the eligible subtotal",
+			coupon: fixed, code: "POTONG", eligible: map[uint]bool{2: true},
+			amounts: []int64{30000, 3000}, wantDiscount: 3000, wantShares: []int64{0, 3000},
+		},
+		{
+			name:   "last line takes the rounding rest",
+			coupon: withValue(fixed, 1000), code: "POTONG", eligible: map[uint]bool{1: true, 2: true, 3: true},
+			amounts: []int64{10000, 10000, 10000}, wantDiscount: 1000, wantShares: []int64{333, 333, 334},
+		},
+		{
+			name:   "fixed discount in another currency is converted",
+			coupon: withCurrency(withValue(fixed, 100), "USD"), code: "POTONG", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, wantDiscount: 16000, wantShares: []int64{16000},
+		},
+		{
+			name:   "no eligible items",
+			coupon: fixed, code: "POTONG", eligible: map[uint]bool{},
+			amounts: []int64{30000}, bundle: 10000, wantErr: true,
+		},
+		{
+			name:   "unknown code",
+			coupon: store, code: "LAINNYA", amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "inactive",
+			coupon: withInactive(store), code: "HEMAT", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "not started",
+			coupon: withWindow(store, &future, nil), code: "HEMAT", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "ended",
+			coupon: withWindow(store, nil, &past), code: "HEMAT", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "within window",
+			coupon: withWindow(store, &past, &future), code: "HEMAT", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, wantDiscount: 3000, wantShares: []int64{3000},
+		},
+		{
+			name:   "usage limit reached",
+			coupon: withUsage(store, &ten, nil, 10), code: "HEMAT", eligible: map[uint]bool{1: true},
+			amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "per user limit reached",
+			coupon: withUsage(store, nil, &one, 0), code: "HEMAT", eligible: map[uint]bool{1: true},
+			redemptions: 1, userID: 7, amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "guests preview without user limits",
+			coupon: withUsage(store, nil, &one, 0), code: "HEMAT", eligible: map[uint]bool{1: true},
+			redemptions: 1, amounts: []int64{30000}, wantDiscount: 3000, wantShares: []int64{3000},
+		},
+		{
+			name:   "first purchase only with earlier orders",
+			coupon: withFirstPurchase(store), code: "HEMAT", eligible: map[uint]bool{1: true},
+			hasOrders: true, userID: 7, amounts: []int64{30000}, wantErr: true,
+		},
+		{
+			name:   "first purchase only on first order",
+			coupon: withFirstPurchase(store), code: "HEMAT", eligible: map[uint]bool{1: true},
+			userID: 7, amounts: []int64{30000}, wantDiscount: 3000, wantShares: []int64{3000},
+		},
+		{
+			name:   "minimum counts eligible lines only",
+			coupon: withMinimum(fixed, 20000), code: "POTONG", eligible: map[uint]bool{2: true},
+			amounts: []int64{30000, 10000}, wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCouponService(
+				&fakeCouponRepo{coupon: tt.coupon, eligible: tt.eligible, redemptions: tt.redemptions},
+				&fakeOrderRepo{hasOrders: tt.hasOrders},
+				nil,
+				nil,
+				&fakeCurrencies{rates: map[string]float64{"USD/IDR": 16000}},
+			)
+
+			order := &models.Order{UserID: tt.userID, Currency: "IDR"}
+			for i, amount := range tt.amounts {
+				productID := uint(i + 1)
+				order.Items = append(order.Items, models.OrderItem{ProductID: &productID, Amount: amount})
+				order.TotalAmount += amount
+			}
+			if tt.bundle > 0 {
+				bundleID := uint(1)
+				order.Items = append(order.Items, models.OrderItem{BundleID: &bundleID, Amount: tt.bundle})
+				order.TotalAmount += tt.bundle
+			}
+			order.FinalAmount = order.TotalAmount
+
+			err := service.ApplyCoupon(order, tt.code)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ApplyCoupon() discount = %d, want error", order.DiscountAmount)
+				}
+				if order.CouponID != nil || order.DiscountAmount != 0 {
+					t.Errorf("rejected coupon changed the order: coupon %v, discount %d", order.CouponID, order.DiscountAmount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyCoupon() error = %v", err)
+			}
+
+			if order.DiscountAmount != tt.wantDiscount {
+				t.Errorf("DiscountAmount = %d, want %d", order.DiscountAmount, tt.wantDiscount)
+			}
+			if order.FinalAmount != order.TotalAmount-tt.wantDiscount {
+				t.Errorf("FinalAmount = %d, want %d", order.FinalAmount, order.TotalAmount-tt.wantDiscount)
+			}
+			if order.CouponID == nil || *order.CouponID != tt.coupon.ID {
+				t.Errorf("CouponID = %v, want %d", order.CouponID, tt.coupon.ID)
+			}
+			for i, want := range tt.wantShares {
+				if got := order.Items[i].DiscountAmount; got != want {
+					t.Errorf("line %d DiscountAmount = %d, want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func withValue(coupon models.Coupon, value int64) models.Coupon {
+	coupon.DiscountValue = value
+	return coupon
+}
+
+func withCurrency(coupon models.Coupon, currency string) models.Coupon {
+	coupon.Currency = currency
+	return coupon
+}
+
+func withInactive(coupon models.Coupon) models.Coupon {
+	coupon.IsActive = false
+	return coupon
+}
+
+func withWindow(coupon models.Coupon, startsAt, endsAt *time.Time) models.Coupon {
+	coupon.StartsAt, coupon.EndsAt = startsAt, endsAt
+	return coupon
+}
+
+func withUsage(coupon models.Coupon, usageLimit, perUserLimit *int, used int) models.Coupon {
+	coupon.UsageLimit, coupon.PerUserLimit, coupon.UsedCount = usageLimit, perUserLimit, used
+	return coupon
+}
+
+func withFirstPurchase(coupon models.Coupon) models.Coupon {
+	coupon.FirstPurchaseOnly = true
+	return coupon
+}
+
+func withMinimum(coupon models.Coupon, minimum int64) models.Coupon {
+	coupon.MinOrderAmount = minimum
+	return coupon
+}
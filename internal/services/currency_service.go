@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type CurrencyService interface {
+	CreateRate(req models.ExchangeRateRequest, createdBy uint) (*models.ExchangeRate, error)
+	GetRates(currency string, page, limit int) ([]models.ExchangeRate, int64, error)
+	GetCurrencies() ([]models.CurrencyInfo, error)
+	DeleteRate(id uint) error
+	Rate(from, to string, at time.Time) (float64, error)
+	ResolveCurrency(requested string, userID uint) (string, error)
+	ApplyDisplay(currency string, products ...*models.Product) error
+}
+
+type currencyService struct {
+	rateRepo repositories.ExchangeRateRepository
+	userRepo repositories.UserRepository
+}
+
+func NewCurrencyService(rateRepo repositories.ExchangeRateRepository, userRepo repositories.UserRepository) CurrencyService {
+	return &currencyService{
+		rateRepo: rateRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *currencyService) CreateRate(req models.ExchangeRateRequest, createdBy uint) (*models.ExchangeRate, error) {
+	currency, err := normalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == models.BaseCurrency {
+		return nil, errors.New("the base currency has no exchange rate")
+	}
+
+	rate := &models.ExchangeRate{
+		Currency:    currency,
+		Rate:        req.Rate,
+		EffectiveAt: time.Now(),
+		CreatedBy:   createdBy,
+	}
+	if req.EffectiveAt != nil {
+		rate.EffectiveAt = *req.EffectiveAt
+	}
+
+	if err := s.rateRepo.Create(rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *currencyService) GetRates(currency string, page, limit int) ([]models.ExchangeRate, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.rateRepo.GetByCurrency(strings.ToUpper(currency), page, limit)
+}
+
+// GetCurrencies lists the supported currencies with the rates in effect now
+func (s *currencyService) GetCurrencies() ([]models.CurrencyInfo, error) {
+	rates, err := s.rateRepo.GetAllEffective(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]models.ExchangeRate, len(rates))
+	for _, rate := range rates {
+		current[rate.Currency] = rate
+	}
+
+	currencies := make([]models.CurrencyInfo, 0, len(models.Currencies))
+	for code, minorUnits := range models.Currencies {
+		info := models.CurrencyInfo{
+			Code:       code,
+			MinorUnits: minorUnits,
+			IsBase:     code == models.BaseCurrency,
+		}
+		if info.IsBase {
+			one := 1.0
+			info.Rate = &one
+		} else if rate, ok := current[code]; ok {
+			info.Rate = &rate.Rate
+			info.EffectiveAt = &rate.EffectiveAt
+		}
+		currencies = append(currencies, info)
+	}
+
+	sort.Slice(currencies, func(i, j int) bool {
+		if currencies[i].IsBase != currencies[j].IsBase {
+			return currencies[i].IsBase
+		}
+		return currencies[i].Code < currencies[j].Code
+	})
+	return currencies, nil
+}
+
+func (s *currencyService) DeleteRate(id uint) error {
+	if _, err := s.rateRepo.GetByID(id); err != nil {
+		return errors.New("exchange rate not found")
+	}
+	return s.rateRepo.Delete(id)
+}
+
+// Rate returns how many units of to one unit of from is worth at the given time
+func (s *currencyService) Rate(from, to string, at time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := s.baseRate(from, at)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := s.baseRate(to, at)
+	if err != nil {
+		return 0, err
+	}
+	return fromRate / toRate, nil
+}
+
+// baseRate is the value of one unit of the currency in BaseCurrency
+func (s *currencyService) baseRate(currency string, at time.Time) (float64, error) {
+	if currency == models.BaseCurrency {
+		return 1, nil
+	}
+	rate, err := s.rateRepo.GetEffective(currency, at)
+	if err != nil {
+		return 0, errors.New("no exchange rate for " + currency)
+	}
+	return rate.Rate, nil
+}
+
+// ResolveCurrency picks the display currency: the requested one, otherwise the
+// user's preference. An empty result means prices are shown as listed.
+func (s *currencyService) ResolveCurrency(requested string, userID uint) (string, error) {
+	if requested != "" {
+		return normalizeCurrency(requested)
+	}
+	if userID == 0 {
+		return "", nil
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", nil
+	}
+	return user.Currency, nil
+}
+
+// ApplyDisplay fills in the products' prices converted to the given currency.
+// Sale prices are only converted once ApplySalePrices ran on the products.
+func (s *currencyService) ApplyDisplay(currency string, products ...*models.Product) error {
+	if currency == "" {
+		return nil
+	}
+
+	now := time.Now()
+	rates := make(map[string]float64)
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+
+		rate, ok := rates[product.Currency]
+		if !ok {
+			var err error
+			if rate, err = s.Rate(product.Currency, currency, now); err != nil {
+				return err
+			}
+			rates[product.Currency] = rate
+		}
+
+		display := &models.PriceDisplay{
+			Currency:     currency,
+			Price:        convertAmount(product.Price, product.Currency, currency, rate),
+			ExchangeRate: rate,
+		}
+		if product.DiscountPrice != nil {
+			price := convertAmount(*product.DiscountPrice, product.Currency, currency, rate)
+			display.DiscountPrice = &price
+		}
+		if product.SalePrice != nil {
+			price := convertAmount(*product.SalePrice, product.Currency, currency, rate)
+			display.SalePrice = &price
+		}
+		product.Display = display
+	}
+	return nil
+}
+
+func normalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if _, ok := models.Currencies[currency]; !ok {
+		return "", errors.New("unsupported currency")
+	}
+	return currency, nil
+}
+
+// convertAmount converts minor units of one currency into minor units of
+// another, rounding half away from zero
+func convertAmount(amount int64, from, to string, rate float64) int64 {
+	if from == to {
+		return amount
+	}
+	scale := math.Pow10(models.Currencies[to] - models.Currencies[from])
+	return int64(math.Round(float64(amount) * rate * scale))
+}
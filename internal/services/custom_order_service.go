@@ -8,14 +8,14 @@ import (
 )
 
 type CustomOrderService interface {
-	CreateRequest(userID uint, title, description, requirements string, budget float64) error
+	CreateRequest(userID uint, title, description, requirements, currency string, budget int64) error
 	GetUserRequests(userID uint) ([]*models.CustomOrder, error)
 	GetRequestByID(id uint) (*models.CustomOrder, error)
 	CancelRequest(id, userID uint) error
 
 	// Admin
 	GetAllRequests(page, limit int, status string) ([]*models.CustomOrder, int64, error)
-	ProcessRequest(id uint, status, adminNotes string, quotedPrice *float64, estimatedDays *int) error
+	ProcessRequest(id uint, status, adminNotes string, quotedPrice *int64, estimatedDays *int) error
 	CompleteRequest(id uint) error
 }
 
@@ -29,7 +29,7 @@ func NewCustomOrderService(customOrderRepo repositories.CustomOrderRepository) C
 	}
 }
 
-func (s *customOrderService) CreateRequest(userID uint, title, description, requirements string, budget float64) error {
+func (s *customOrderService) CreateRequest(userID uint, title, description, requirements, currency string, budget int64) error {
 	if title == "" || description == "" {
 		return errors.New("title dan description harus diisi")
 	}
@@ -38,11 +38,20 @@ func (s *customOrderService) CreateRequest(userID uint, title, description, requ
 		return errors.New("budget tidak valid")
 	}
 
+	if currency == "" {
+		currency = models.BaseCurrency
+	}
+	currency, err := normalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+
 	customOrder := &models.CustomOrder{
 		UserID:       userID,
 		Title:        title,
 		Description:  description,
 		Requirements: requirements,
+		Currency:     currency,
 		BudgetMin:    budget,
 		BudgetMax:    budget,
 		Status:       "pending",
@@ -88,7 +97,7 @@ func (s *customOrderService) GetAllRequests(page, limit int, status string) ([]*
 	return s.customOrderRepo.GetAll(page, limit, status)
 }
 
-func (s *customOrderService) ProcessRequest(id uint, status, adminNotes string, quotedPrice *float64, estimatedDays *int) error {
+func (s *customOrderService) ProcessRequest(id uint, status, adminNotes string, quotedPrice *int64, estimatedDays *int) error {
 	customOrder, err := s.customOrderRepo.GetByID(id)
 	if err != nil {
 		return errors.New("custom order tidak ditemukan")
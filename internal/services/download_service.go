@@ -42,11 +42,13 @@ func (s *downloadService) CreateDownload(userID, orderID, productID uint) error
 		return errors.New("unauthorized")
 	}
 
-	if order.Status != "paid" {
+	// Payment lives in PaymentStatus; Status never holds "paid"
+	if order.PaymentStatus != "paid" {
 		return errors.New("order is not paid")
 	}
 
-	if order.ProductID == nil || *order.ProductID != productID {
+	item := s.orderItemFor(order, productID)
+	if item == nil {
 		return errors.New("product does not match order")
 	}
 
@@ -57,6 +59,11 @@ func (s *downloadService) CreateDownload(userID, orderID, productID uint) error
 		OrderID:   orderID,
 	}
 
+	// Bundles grant the base license; direct purchases carry the tier that was bought
+	if item.ProductID != nil {
+		download.LicenseTierID = item.LicenseTierID
+	}
+
 	if err := s.downloadRepo.Create(download); err != nil {
 		return err
 	}
@@ -83,19 +90,28 @@ func (s *downloadService) GetUserDownloads(userID uint, page, limit int) ([]mode
 }
 
 func (s *downloadService) CanDownload(userID, productID uint) (bool, error) {
-	// Check if user has paid order for this product
-	orders, _, err := s.orderRepo.GetByUserID(userID, 1, 100)
-	if err != nil {
-		return false, err
-	}
+	// Check if user has a paid order for this product, directly or via a bundle
+	return s.orderRepo.HasPaidAccess(userID, productID)
+}
 
-	for _, order := range orders {
-		if order.ProductID != nil && *order.ProductID == productID && order.PaymentStatus == "paid" {
-			return true, nil
+// orderItemFor finds the order item that bought the product, preferring a
+// direct purchase over a bundle that contained it at checkout. It returns nil
+// when there is none.
+func (s *downloadService) orderItemFor(order *models.Order, productID uint) *models.OrderItem {
+	for i := range order.Items {
+		if item := &order.Items[i]; item.ProductID != nil && *item.ProductID == productID {
+			return item
 		}
 	}
-
-	return false, nil
+	for i := range order.Items {
+		item := &order.Items[i]
+		for _, granted := range item.BundleProducts {
+			if granted.ProductID == productID {
+				return item
+			}
+		}
+	}
+	return nil
 }
 
 func (s *downloadService) GetDownloadHistory(userID, productID uint) ([]models.Download, error) {
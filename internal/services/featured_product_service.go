@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"time"
+)
+
+type FeaturedProductService interface {
+	GetFeaturedProducts(limit int) ([]models.Product, error)
+	GetSlots() ([]models.FeaturedProduct, error)
+	CreateSlot(req models.FeaturedProductRequest, adminID uint) (*models.FeaturedProduct, error)
+	UpdateSlot(id uint, req models.FeaturedProductRequest) (*models.FeaturedProduct, error)
+	DeleteSlot(id uint) error
+}
+
+type featuredProductService struct {
+	featuredRepo   repositories.FeaturedProductRepository
+	productRepo    repositories.ProductRepository
+	campaigns      SaleCampaignService
+	maxPerCategory int
+}
+
+func NewFeaturedProductService(
+	featuredRepo repositories.FeaturedProductRepository,
+	productRepo repositories.ProductRepository,
+	campaigns SaleCampaignService,
+	maxPerCategory int,
+) FeaturedProductService {
+	if maxPerCategory < 1 {
+		maxPerCategory = 1
+	}
+	return &featuredProductService{
+		featuredRepo:   featuredRepo,
+		productRepo:    productRepo,
+		campaigns:      campaigns,
+		maxPerCategory: maxPerCategory,
+	}
+}
+
+func (s *featuredProductService) GetFeaturedProducts(limit int) ([]models.Product, error) {
+	if limit < 1 {
+		limit = 10
+	}
+	products, err := s.featuredRepo.GetLiveProducts(limit, s.maxPerCategory, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.campaigns.ApplySalePrices(productPointers(products)...); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (s *featuredProductService) GetSlots() ([]models.FeaturedProduct, error) {
+	return s.featuredRepo.GetAll()
+}
+
+func (s *featuredProductService) CreateSlot(req models.FeaturedProductRequest, adminID uint) (*models.FeaturedProduct, error) {
+	slot := &models.FeaturedProduct{CreatedBy: adminID}
+
+	if err := s.applyRequest(slot, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.featuredRepo.Create(slot); err != nil {
+		return nil, err
+	}
+
+	return slot, nil
+}
+
+func (s *featuredProductService) UpdateSlot(id uint, req models.FeaturedProductRequest) (*models.FeaturedProduct, error) {
+	slot, err := s.featuredRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("featured slot not found")
+	}
+
+	if err := s.applyRequest(slot, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.featuredRepo.Update(slot); err != nil {
+		return nil, err
+	}
+
+	return slot, nil
+}
+
+func (s *featuredProductService) DeleteSlot(id uint) error {
+	if _, err := s.featuredRepo.GetByID(id); err != nil {
+		return errors.New("featured slot not found")
+	}
+	return s.featuredRepo.Delete(id)
+}
+
+// applyRequest validates the schedule, the product's other slots and the
+// per-category cap before copying req onto slot
+func (s *featuredProductService) applyRequest(slot *models.FeaturedProduct, req models.FeaturedProductRequest) error {
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	product, err := s.productRepo.GetByID(req.ProductID)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	if product.Status != "published" {
+		return errors.New("product is not available")
+	}
+
+	// A product shows once on the list, so its slots must not overlap
+	duplicates, err := s.featuredRepo.CountOverlappingForProduct(product.ID, req.StartsAt, req.EndsAt, slot.ID)
+	if err != nil {
+		return err
+	}
+	if duplicates > 0 {
+		return errors.New("product already has a featured slot in this period")
+	}
+
+	overlapping, err := s.featuredRepo.CountOverlappingInCategory(product.CategoryID, req.StartsAt, req.EndsAt, slot.ID)
+	if err != nil {
+		return err
+	}
+	if overlapping >= int64(s.maxPerCategory) {
+		return fmt.Errorf("category already has %d featured products in this period", s.maxPerCategory)
+	}
+
+	slot.ProductID = req.ProductID
+	slot.Product = product
+	slot.Position = req.Position
+	slot.StartsAt = req.StartsAt
+	slot.EndsAt = req.EndsAt
+
+	return nil
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/pkg/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LicenseKeyService interface {
+	IssueForOrder(order *models.Order) ([]models.LicenseKey, error)
+	IssueForOrderID(orderID uint) ([]models.LicenseKey, error)
+	PublicKey() ed25519.PublicKey
+	Validate(req models.LicenseValidateRequest) (*models.LicenseValidation, error)
+	Activate(req models.LicenseActivationRequest) (*models.LicenseActivation, error)
+	Deactivate(req models.LicenseActivationRequest) error
+	GetUserLicenses(userID uint) ([]models.LicenseKey, error)
+	DeactivateForUser(userID, licenseID, activationID uint) error
+	Revoke(licenseID uint) error
+}
+
+type licenseKeyService struct {
+	licenseRepo repositories.LicenseKeyRepository
+	orderRepo   repositories.OrderRepository
+	signingKey  ed25519.PrivateKey
+}
+
+func NewLicenseKeyService(licenseRepo repositories.LicenseKeyRepository, orderRepo repositories.OrderRepository, signingKey ed25519.PrivateKey) LicenseKeyService {
+	return &licenseKeyService{
+		licenseRepo: licenseRepo,
+		orderRepo:   orderRepo,
+		signingKey:  signingKey,
+	}
+}
+
+// IssueForOrder creates the license keys of a paid order: one per order line
+// and product that requires a license, allowing tier seats × quantity
+// activations. Keys that already exist are kept, so calling it again only
+// issues the missing ones and returns those.
+func (s *licenseKeyService) IssueForOrder(order *models.Order) ([]models.LicenseKey, error) {
+	if order.PaymentStatus != "paid" {
+		return nil, errors.New("order is not paid")
+	}
+
+	var licenses []models.LicenseKey
+	for _, item := range order.Items {
+		var products []models.Product
+		switch {
+		case item.BundleID != nil:
+			for _, granted := range item.BundleProducts {
+				if granted.Product != nil {
+					products = append(products, *granted.Product)
+				}
+			}
+		case item.Product != nil:
+			products = []models.Product{*item.Product}
+		}
+
+		quantity := item.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+
+		for _, product := range products {
+			if !product.RequiresLicense {
+				continue
+			}
+
+			seats := 1
+			var tierName string
+			if item.BundleID == nil && item.LicenseTier != nil {
+				tierName = item.LicenseTier.Name
+				if item.LicenseTier.Seats > 1 {
+					seats = item.LicenseTier.Seats
+				}
+			}
+
+			itemID := item.ID
+			license := &models.LicenseKey{
+				OrderID:        order.ID,
+				OrderItemID:    &itemID,
+				UserID:         order.UserID,
+				ProductID:      product.ID,
+				MaxActivations: seats * quantity,
+				Status:         "active",
+			}
+			if item.BundleID == nil {
+				license.LicenseTierID = item.LicenseTierID
+			}
+			// An upgrade supersedes the keys of the lower tier
+			issued, err := s.licenseRepo.Issue(license, order.OrderType == "license_upgrade", func(l *models.LicenseKey) string {
+				return s.sign(l, tierName)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if issued {
+				licenses = append(licenses, *license)
+			}
+		}
+	}
+
+	return licenses, nil
+}
+
+// IssueForOrderID issues the keys of a paid order that did not get them at approval
+func (s *licenseKeyService) IssueForOrderID(orderID uint) ([]models.LicenseKey, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	return s.IssueForOrder(order)
+}
+
+func (s *licenseKeyService) sign(license *models.LicenseKey, tierName string) string {
+	payload, _ := json.Marshal(models.LicenseKeyPayload{
+		LicenseID:      license.ID,
+		ProductID:      license.ProductID,
+		OrderID:        license.OrderID,
+		Tier:           tierName,
+		MaxActivations: license.MaxActivations,
+		IssuedAt:       license.CreatedAt.Unix(),
+	})
+	return utils.SignLicenseKey(payload, s.signingKey)
+}
+
+func (s *licenseKeyService) PublicKey() ed25519.PublicKey {
+	return s.signingKey.Public().(ed25519.PublicKey)
+}
+
+// decode checks the key's signature and returns its payload
+func (s *licenseKeyService) decode(key string) (*models.LicenseKeyPayload, error) {
+	raw, err := utils.VerifyLicenseKey(key, s.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	var payload models.LicenseKeyPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, errors.New("malformed license key")
+	}
+	return &payload, nil
+}
+
+// verify checks the key's signature and loads the license it was issued for
+func (s *licenseKeyService) verify(key string) (*models.LicenseKey, error) {
+	key = strings.TrimSpace(key)
+	if _, err := s.decode(key); err != nil {
+		return nil, err
+	}
+
+	license, err := s.licenseRepo.GetByKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("license key not found")
+		}
+		return nil, err
+	}
+	return license, nil
+}
+
+// Validate reports whether a key is genuine and active and, when a fingerprint
+// is given, activated on that machine. Rejections are part of the answer, only
+// internal errors are returned.
+func (s *licenseKeyService) Validate(req models.LicenseValidateRequest) (*models.LicenseValidation, error) {
+	key := strings.TrimSpace(req.Key)
+	result := &models.LicenseValidation{}
+
+	payload, err := s.decode(key)
+	if err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+	result.Payload = payload
+
+	license, err := s.licenseRepo.GetByKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.Reason = "license key not found"
+			return result, nil
+		}
+		return nil, err
+	}
+
+	used, err := s.licenseRepo.CountActivations(license.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = license.Status
+	result.ActivationsUsed = int(used)
+	result.MaxActivations = license.MaxActivations
+
+	if license.Status != "active" {
+		result.Reason = "license is " + license.Status
+		return result, nil
+	}
+
+	if req.Fingerprint != "" {
+		activation, err := s.licenseRepo.GetActivation(license.ID, strings.TrimSpace(req.Fingerprint))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.Reason = "license is not activated on this machine"
+				return result, nil
+			}
+			return nil, err
+		}
+		if err := s.licenseRepo.TouchActivation(activation.ID, time.Now()); err != nil {
+			return nil, err
+		}
+		result.Activated = true
+	}
+
+	result.Valid = true
+	return result, nil
+}
+
+// Activate binds the key to a machine. Activating an already activated
+// machine again is not counted twice.
+func (s *licenseKeyService) Activate(req models.LicenseActivationRequest) (*models.LicenseActivation, error) {
+	license, err := s.verify(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if license.Status != "active" {
+		return nil, errors.New("license is " + license.Status)
+	}
+
+	fingerprint := strings.TrimSpace(req.Fingerprint)
+	activation, err := s.licenseRepo.GetActivation(license.ID, fingerprint)
+	if err == nil {
+		now := time.Now()
+		if err := s.licenseRepo.TouchActivation(activation.ID, now); err != nil {
+			return nil, err
+		}
+		activation.LastValidatedAt = now
+		return activation, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	activation = &models.LicenseActivation{
+		LicenseKeyID:    license.ID,
+		Fingerprint:     fingerprint,
+		MachineName:     strings.TrimSpace(req.MachineName),
+		LastValidatedAt: time.Now(),
+	}
+	created, err := s.licenseRepo.CreateActivation(activation, license.MaxActivations)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New("activation limit reached")
+	}
+	return activation, nil
+}
+
+// Deactivate frees the activation slot of a machine, e.g. before moving the license
+func (s *licenseKeyService) Deactivate(req models.LicenseActivationRequest) error {
+	license, err := s.verify(req.Key)
+	if err != nil {
+		return err
+	}
+
+	activation, err := s.licenseRepo.GetActivation(license.ID, strings.TrimSpace(req.Fingerprint))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("license is not activated on this machine")
+		}
+		return err
+	}
+
+	_, err = s.licenseRepo.DeleteActivation(license.ID, activation.ID)
+	return err
+}
+
+func (s *licenseKeyService) GetUserLicenses(userID uint) ([]models.LicenseKey, error) {
+	return s.licenseRepo.GetByUserID(userID)
+}
+
+// DeactivateForUser lets an owner release a machine they no longer have access to
+func (s *licenseKeyService) DeactivateForUser(userID, licenseID, activationID uint) error {
+	license, err := s.licenseRepo.GetByID(licenseID)
+	if err != nil || license.UserID != userID {
+		return errors.New("license not found")
+	}
+
+	deleted, err := s.licenseRepo.DeleteActivation(license.ID, activationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("activation not found")
+	}
+	return nil
+}
+
+func (s *licenseKeyService) Revoke(licenseID uint) error {
+	license, err := s.licenseRepo.GetByID(licenseID)
+	if err != nil {
+		return errors.New("license not found")
+	}
+	if license.Status == "revoked" {
+		return errors.New("license is already revoked")
+	}
+
+	now := time.Now()
+	license.Status = "revoked"
+	license.RevokedAt = &now
+	return s.licenseRepo.Update(license)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+)
+
+// licenseTierRanks orders tiers from least to most permissive; upgrades must go up
+var licenseTierRanks = map[string]int{
+	"personal":   1,
+	"commercial": 2,
+	"extended":   3,
+}
+
+type LicenseTierService interface {
+	GetProductTiers(productID uint) ([]models.LicenseTier, error)
+	CreateTier(productID uint, req models.LicenseTierRequest) (*models.LicenseTier, error)
+	UpdateTier(productID, tierID uint, req models.LicenseTierRequest) (*models.LicenseTier, error)
+	DeleteTier(productID, tierID uint) error
+}
+
+type licenseTierService struct {
+	tierRepo    repositories.LicenseTierRepository
+	productRepo repositories.ProductRepository
+}
+
+func NewLicenseTierService(tierRepo repositories.LicenseTierRepository, productRepo repositories.ProductRepository) LicenseTierService {
+	return &licenseTierService{
+		tierRepo:    tierRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *licenseTierService) GetProductTiers(productID uint) ([]models.LicenseTier, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	tiers, err := s.tierRepo.GetByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]models.LicenseTier, 0, len(tiers))
+	for _, tier := range tiers {
+		if tier.IsActive {
+			active = append(active, tier)
+		}
+	}
+	return active, nil
+}
+
+func (s *licenseTierService) CreateTier(productID uint, req models.LicenseTierRequest) (*models.LicenseTier, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	existing, _ := s.tierRepo.GetByProductAndName(productID, req.Name)
+	if existing != nil {
+		return nil, errors.New("product already has a " + req.Name + " license")
+	}
+
+	tier := &models.LicenseTier{
+		ProductID: productID,
+		Name:      req.Name,
+		Price:     req.Price,
+		Terms:     req.Terms,
+		Seats:     req.Seats,
+		IsActive:  true,
+	}
+	if tier.Seats < 1 {
+		tier.Seats = 1
+	}
+	if req.IsActive != nil {
+		tier.IsActive = *req.IsActive
+	}
+
+	if err := s.tierRepo.Create(tier); err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+func (s *licenseTierService) UpdateTier(productID, tierID uint, req models.LicenseTierRequest) (*models.LicenseTier, error) {
+	tier, err := s.tierRepo.GetByID(tierID)
+	if err != nil || tier.ProductID != productID {
+		return nil, errors.New("license tier not found")
+	}
+
+	if req.Name != tier.Name {
+		existing, _ := s.tierRepo.GetByProductAndName(productID, req.Name)
+		if existing != nil {
+			return nil, errors.New("product already has a " + req.Name + " license")
+		}
+		tier.Name = req.Name
+	}
+
+	tier.Price = req.Price
+	tier.Terms = req.Terms
+	if req.Seats > 0 {
+		tier.Seats = req.Seats
+	}
+	if req.IsActive != nil {
+		tier.IsActive = *req.IsActive
+	}
+
+	if err := s.tierRepo.Update(tier); err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+func (s *licenseTierService) DeleteTier(productID, tierID uint) error {
+	tier, err := s.tierRepo.GetByID(tierID)
+	if err != nil || tier.ProductID != productID {
+		return errors.New("license tier not found")
+	}
+	return s.tierRepo.Delete(tierID)
+}
+
+// resolveLicenseTier picks the tier a buyer is purchasing. Products without
+// tiers are sold on their own price (nil tier); products with tiers default to
+// the cheapest active one when none is chosen.
+func resolveLicenseTier(tierRepo repositories.LicenseTierRepository, product *models.Product, tierID *uint) (*models.LicenseTier, error) {
+	if tierID != nil && *tierID > 0 {
+		tier, err := tierRepo.GetByID(*tierID)
+		if err != nil || tier.ProductID != product.ID {
+			return nil, errors.New("license tier not found")
+		}
+		if !tier.IsActive {
+			return nil, errors.New("license tier is not available")
+		}
+		return tier, nil
+	}
+
+	tiers, err := tierRepo.GetByProductID(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tiers {
+		if tiers[i].IsActive {
+			return &tiers[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// unitPrice is what one unit of the product costs under the given tier.
+// Sale campaigns are only taken into account once ApplySalePrices ran on the product.
+func unitPrice(product *models.Product, tier *models.LicenseTier) int64 {
+	if tier != nil {
+		if product.Sale != nil {
+			if price := discountedPrice(product.Sale.DiscountType, product.Sale.DiscountValue, tier.Price); price < tier.Price {
+				return price
+			}
+		}
+		return tier.Price
+	}
+	if product.SalePrice != nil {
+		return *product.SalePrice
+	}
+	return regularPrice(product)
+}
+
+// regularPrice is the product's price outside of sale campaigns
+func regularPrice(product *models.Product) int64 {
+	if product.DiscountPrice != nil && *product.DiscountPrice > 0 {
+		return *product.DiscountPrice
+	}
+	return product.Price
+}
@@ -36,6 +36,7 @@ func (s *notificationService) CreateNotification(userID uint, notifType, title,
 		"download":     true,
 		"review":       true,
 		"custom_order": true,
+		"question":     true,
 		"system":       true,
 	}
 
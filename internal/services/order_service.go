@@ -6,12 +6,16 @@ import (
 	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/repositories"
 	"gin-quickstart/pkg/utils"
+	"log"
 	"mime/multipart"
 	"time"
 )
 
 type OrderService interface {
-	CreateOrder(userID, productID uint, quantity int) (*models.Order, error)
+	CreateOrder(userID, productID uint, licenseTierID *uint, quantity int, currency, couponCode string) (*models.Order, error)
+	CreateBundleOrder(userID, bundleID uint, currency, couponCode string) (*models.Order, error)
+	CheckoutCart(userID uint, currency, couponCode string) (*models.Order, error)
+	UpgradeLicense(userID, productID, licenseTierID uint, currency string) (*models.Order, error)
 	GetOrderByID(userID, orderID uint) (*models.Order, error)
 	GetUserOrders(userID uint, page, limit int) ([]models.Order, int64, error)
 	GetAllOrders(page, limit int, status string) ([]models.Order, int64, error)
@@ -19,6 +23,7 @@ type OrderService interface {
 	ApprovePayment(orderID uint, adminID uint) error
 	RejectPayment(orderID uint, adminID uint, reason string) error
 	CancelOrder(userID, orderID uint) error
+	ExpirePendingOrders() error
 }
 
 type orderService struct {
@@ -26,6 +31,14 @@ type orderService struct {
 	transactionRepo repositories.TransactionRepository
 	productRepo     repositories.ProductRepository
 	cartRepo        repositories.CartRepository
+	bundleRepo      repositories.BundleRepository
+	tierRepo        repositories.LicenseTierRepository
+	campaigns       SaleCampaignService
+	coupons         CouponService
+	taxes           TaxService
+	currencies      CurrencyService
+	pendingOrderTTL time.Duration
+	licenses        LicenseKeyService
 }
 
 func NewOrderService(
@@ -33,16 +46,60 @@ func NewOrderService(
 	transactionRepo repositories.TransactionRepository,
 	productRepo repositories.ProductRepository,
 	cartRepo repositories.CartRepository,
+	bundleRepo repositories.BundleRepository,
+	tierRepo repositories.LicenseTierRepository,
+	campaigns SaleCampaignService,
+	coupons CouponService,
+	taxes TaxService,
+	currencies CurrencyService,
+	licenses LicenseKeyService,
+	pendingOrderTTL time.Duration,
+	expiryInterval time.Duration,
 ) OrderService {
-	return &orderService{
+	s := &orderService{
 		orderRepo:       orderRepo,
 		transactionRepo: transactionRepo,
 		productRepo:     productRepo,
 		cartRepo:        cartRepo,
+		bundleRepo:      bundleRepo,
+		tierRepo:        tierRepo,
+		campaigns:       campaigns,
+		coupons:         coupons,
+		taxes:           taxes,
+		currencies:      currencies,
+		licenses:        licenses,
+		pendingOrderTTL: pendingOrderTTL,
+	}
+
+	// Cancel orders left unpaid for longer than the TTL so they stop holding coupon uses
+	go s.run(expiryInterval)
+
+	return s
+}
+
+func (s *orderService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.ExpirePendingOrders(); err != nil {
+			log.Println("Failed to expire pending orders:", err)
+		}
 	}
 }
 
-func (s *orderService) CreateOrder(userID, productID uint, quantity int) (*models.Order, error) {
+func (s *orderService) ExpirePendingOrders() error {
+	count, err := s.orderRepo.ExpirePending(time.Now().Add(-s.pendingOrderTTL))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("Expired %d unpaid order(s)", count)
+	}
+	return nil
+}
+
+func (s *orderService) CreateOrder(userID, productID uint, licenseTierID *uint, quantity int, currency, couponCode string) (*models.Order, error) {
 	if quantity <= 0 {
 		return nil, errors.New("quantity must be greater than 0")
 	}
@@ -52,15 +109,17 @@ func (s *orderService) CreateOrder(userID, productID uint, quantity int) (*model
 		return nil, errors.New("product not found")
 	}
 
-	if !product.IsActive {
+	if product.Status != "published" {
 		return nil, errors.New("product is not available")
 	}
 
-	var totalAmount float64
-	if product.DiscountPrice != nil && *product.DiscountPrice > 0 {
-		totalAmount = *product.DiscountPrice * float64(quantity)
-	} else {
-		totalAmount = product.Price * float64(quantity)
+	tier, err := resolveLicenseTier(s.tierRepo, product, licenseTierID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.campaigns.ApplySalePrices(product); err != nil {
+		return nil, err
 	}
 
 	orderNumber := fmt.Sprintf("ORD-%d-%d", time.Now().Unix(), userID)
@@ -69,32 +128,418 @@ func (s *orderService) CreateOrder(userID, productID uint, quantity int) (*model
 		OrderNumber:   orderNumber,
 		UserID:        userID,
 		ProductID:     &productID,
+		Quantity:      quantity,
 		OrderType:     "product",
-		TotalAmount:   totalAmount,
-		FinalAmount:   totalAmount,
 		Status:        "pending",
 		PaymentMethod: "manual_transfer",
 		PaymentStatus: "pending",
+		Items:         []models.OrderItem{productItem(product, tier, quantity)},
+	}
+	if err := s.priceOrder(order, currency); err != nil {
+		return nil, err
+	}
+	if err := s.applyCoupon(order, couponCode); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.ApplyTax(order); err != nil {
+		return nil, err
+	}
+
+	if tier != nil {
+		order.LicenseTierID = &tier.ID
+	}
+
+	if err := s.createOrder(order); err != nil {
+		return nil, err
+	}
+	order.LicenseTier = tier
+	order.Items[0].LicenseTier = tier
+
+	return order, nil
+}
+
+func (s *orderService) CreateBundleOrder(userID, bundleID uint, currency, couponCode string) (*models.Order, error) {
+	bundle, err := s.bundleRepo.GetByID(bundleID)
+	if err != nil {
+		return nil, errors.New("bundle not found")
+	}
+
+	if !bundle.IsActive {
+		return nil, errors.New("bundle is not available")
+	}
+
+	orderNumber := fmt.Sprintf("ORD-%d-%d", time.Now().Unix(), userID)
+
+	order := &models.Order{
+		OrderNumber:   orderNumber,
+		UserID:        userID,
+		BundleID:      &bundleID,
+		OrderType:     "bundle",
+		Status:        "pending",
+		PaymentMethod: "manual_transfer",
+		PaymentStatus: "pending",
+		Items: []models.OrderItem{{
+			BundleID:       &bundleID,
+			Title:          bundle.Title,
+			Quantity:       1,
+			ListCurrency:   bundle.Currency,
+			UnitPrice:      bundle.Price,
+			BundleProducts: make([]models.OrderItemProduct, len(bundle.Products)),
+		}},
+	}
+	for i, product := range bundle.Products {
+		order.Items[0].BundleProducts[i].ProductID = product.ID
+	}
+	if err := s.priceOrder(order, currency); err != nil {
+		return nil, err
+	}
+	if err := s.applyCoupon(order, couponCode); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.ApplyTax(order); err != nil {
+		return nil, err
+	}
+
+	if err := s.createOrder(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CheckoutCart turns the user's whole cart into one order, priced at today's
+// prices, and empties the cart
+func (s *orderService) CheckoutCart(userID uint, currency, couponCode string) (*models.Order, error) {
+	carts, err := s.cartRepo.GetByOwner(models.CartOwner{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(carts) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	products := make([]*models.Product, len(carts))
+	tiers := make([]*models.LicenseTier, len(carts))
+	cartIDs := make([]uint, len(carts))
+	for i, cart := range carts {
+		if cart.Product == nil || cart.Product.Status != "published" {
+			return nil, fmt.Errorf("cart item %d is no longer available", cart.ID)
+		}
+		tier, err := resolveLicenseTier(s.tierRepo, cart.Product, cart.LicenseTierID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", cart.Product.Title, err)
+		}
+		products[i] = cart.Product
+		tiers[i] = tier
+		cartIDs[i] = cart.ID
+	}
+
+	if err := s.campaigns.ApplySalePrices(products...); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.OrderItem, len(carts))
+	for i, cart := range carts {
+		items[i] = productItem(products[i], tiers[i], cart.Quantity)
+	}
+
+	orderNumber := fmt.Sprintf("ORD-%d-%d", time.Now().Unix(), userID)
+
+	order := &models.Order{
+		OrderNumber:   orderNumber,
+		UserID:        userID,
+		OrderType:     "cart",
+		Status:        "pending",
+		PaymentMethod: "manual_transfer",
+		PaymentStatus: "pending",
+		Items:         items,
+	}
+	if err := s.priceOrder(order, currency); err != nil {
+		return nil, err
+	}
+	if err := s.applyCoupon(order, couponCode); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.ApplyTax(order); err != nil {
+		return nil, err
+	}
+
+	placed, err := s.orderRepo.Place(order, pendingTransaction(order), cartIDs)
+	if err != nil {
+		return nil, err
+	}
+	if !placed {
+		return nil, errors.New("cart changed during checkout, please review it and try again")
+	}
+	for i := range order.Items {
+		order.Items[i].LicenseTier = tiers[i]
+	}
+
+	return order, nil
+}
+
+// UpgradeLicense creates an order for a higher license tier of a product the
+// user already owns, charging the tier's price less what the user paid for the
+// license they own. Later price changes of the owned tier do not count.
+func (s *orderService) UpgradeLicense(userID, productID, licenseTierID uint, currency string) (*models.Order, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
 	}
 
-	if err := s.orderRepo.Create(order); err != nil {
+	tier, err := resolveLicenseTier(s.tierRepo, product, &licenseTierID)
+	if err != nil {
 		return nil, err
 	}
 
-	transaction := &models.Transaction{
-		OrderID:       order.ID,
-		Amount:        totalAmount,
+	owned, err := s.tierRepo.GetOwnedTiers(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *models.LicenseTier
+	for i := range owned {
+		if current == nil || licenseTierRanks[owned[i].Name] > licenseTierRanks[current.Name] {
+			current = &owned[i]
+		}
+	}
+
+	// Purchases made before tiers existed, or through a bundle, count as the base tier
+	if current == nil {
+		hasAccess, err := s.orderRepo.HasPaidAccess(userID, productID)
+		if err != nil {
+			return nil, err
+		}
+		if hasAccess {
+			current, _ = resolveLicenseTier(s.tierRepo, product, nil)
+		}
+	}
+
+	if current == nil {
+		return nil, errors.New("you do not own a license for this product")
+	}
+
+	if licenseTierRanks[tier.Name] <= licenseTierRanks[current.Name] {
+		return nil, errors.New("license tier must be higher than your current " + current.Name + " license")
+	}
+
+	paid, err := s.paidForLicense(userID, product)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := tier.Price - paid
+	if amount <= 0 {
+		return nil, errors.New("you already paid at least the price of the " + tier.Name + " license")
+	}
+
+	orderNumber := fmt.Sprintf("ORD-%d-%d", time.Now().Unix(), userID)
+
+	order := &models.Order{
+		OrderNumber:   orderNumber,
+		UserID:        userID,
+		ProductID:     &productID,
+		LicenseTierID: &tier.ID,
+		OrderType:     "license_upgrade",
 		Status:        "pending",
 		PaymentMethod: "manual_transfer",
+		PaymentStatus: "pending",
+		Notes:         fmt.Sprintf("Upgrade from %s license", current.Name),
+		Items: []models.OrderItem{{
+			ProductID:     &productID,
+			LicenseTierID: &tier.ID,
+			Title:         product.Title,
+			Quantity:      1,
+			ListCurrency:  product.Currency,
+			UnitPrice:     amount,
+		}},
+	}
+	if err := s.priceOrder(order, currency); err != nil {
+		return nil, err
+	}
+	if err := s.taxes.ApplyTax(order); err != nil {
+		return nil, err
 	}
 
-	if err := s.transactionRepo.Create(transaction); err != nil {
+	if err := s.createOrder(order); err != nil {
 		return nil, err
 	}
+	order.LicenseTier = tier
+	order.Items[0].LicenseTier = tier
 
 	return order, nil
 }
 
+// paidForLicense is what the user paid for one license of the product, in the
+// product's currency. Products owned only through a bundle have no price of
+// their own; they count as the base tier at its current price.
+func (s *orderService) paidForLicense(userID uint, product *models.Product) (int64, error) {
+	orders, err := s.orderRepo.GetPaidProductOrders(userID, product.ID)
+	if err != nil {
+		return 0, err
+	}
+	base, err := resolveLicenseTier(s.tierRepo, product, nil)
+	if err != nil {
+		return 0, err
+	}
+	var baseRank int
+	var basePrice int64
+	if base != nil {
+		baseRank, basePrice = licenseTierRanks[base.Name], base.Price
+	}
+
+	now := time.Now()
+	return upgradeCredit(orders, baseRank, basePrice, func(amount int64, currency string) (int64, error) {
+		rate, err := s.currencies.Rate(currency, product.Currency, now)
+		if err != nil {
+			return 0, err
+		}
+		return convertAmount(amount, currency, product.Currency, rate), nil
+	})
+}
+
+// upgradeCredit follows the user's license chain through their paid orders
+// for a product, oldest first: a direct purchase of a tier at least as high
+// as the one owned restarts the chain at what it cost, and each upgrade adds
+// what it cost. Buying the same tier again therefore never counts twice.
+// Lines without a tier count as the base tier, and the chain starts at the
+// base price for users who own the product only through a bundle. Amounts
+// are per license, after the coupon discount, converted by convert.
+func upgradeCredit(orders []models.Order, baseRank int, basePrice int64, convert func(amount int64, currency string) (int64, error)) (int64, error) {
+	credit, rank := basePrice, baseRank
+	for _, order := range orders {
+		for _, item := range order.Items {
+			itemRank := baseRank
+			if item.LicenseTier != nil {
+				itemRank = licenseTierRanks[item.LicenseTier.Name]
+			}
+			if order.OrderType != "license_upgrade" && itemRank < rank {
+				continue
+			}
+
+			paid, err := convert(paidPerLicense(&order, &item), item.ListCurrency)
+			if err != nil {
+				return 0, err
+			}
+			if order.OrderType == "license_upgrade" {
+				credit += paid
+			} else {
+				credit = paid
+			}
+			rank = itemRank
+		}
+	}
+	return credit, nil
+}
+
+// paidPerLicense is the line's unit price less its share of the coupon
+// discount, in the line's list currency
+func paidPerLicense(order *models.Order, item *models.OrderItem) int64 {
+	quantity := int64(item.Quantity)
+	if quantity < 1 {
+		quantity = 1
+	}
+	discount := item.DiscountAmount
+	if discount > 0 && item.ExchangeRate > 0 {
+		discount = convertAmount(discount, order.Currency, item.ListCurrency, 1/item.ExchangeRate)
+	}
+	return item.UnitPrice - discount/quantity
+}
+
+// productItem is an order line for the product in the tier. Sale prices count
+// once ApplySalePrices ran on the product.
+func productItem(product *models.Product, tier *models.LicenseTier, quantity int) models.OrderItem {
+	productID := product.ID
+	item := models.OrderItem{
+		ProductID:    &productID,
+		Title:        product.Title,
+		Quantity:     quantity,
+		ListCurrency: product.Currency,
+		UnitPrice:    unitPrice(product, tier),
+	}
+	if tier != nil {
+		item.LicenseTierID = &tier.ID
+	}
+	return item
+}
+
+// createOrder stores the order with its items and its pending payment transaction
+func (s *orderService) createOrder(order *models.Order) error {
+	_, err := s.orderRepo.Place(order, pendingTransaction(order), nil)
+	return err
+}
+
+// pendingTransaction is the payment awaited for the order
+func pendingTransaction(order *models.Order) *models.Transaction {
+	return &models.Transaction{
+		Amount:        order.FinalAmount,
+		TaxAmount:     order.TaxAmount,
+		Currency:      order.Currency,
+		Status:        "pending",
+		PaymentMethod: "manual_transfer",
+	}
+}
+
+// applyCoupon discounts the priced order with the coupon code, if one was given
+func (s *orderService) applyCoupon(order *models.Order, couponCode string) error {
+	if couponCode == "" {
+		return nil
+	}
+	return s.coupons.ApplyCoupon(order, couponCode)
+}
+
+// priceOrder charges the order's items, listed in their own currencies, in the
+// buyer's currency. It defaults to the currency the items share, or the base
+// currency when they differ. The rates used are locked into the items and the
+// order so later rate changes never alter what was agreed.
+func (s *orderService) priceOrder(order *models.Order, currency string) error {
+	listCurrency := order.Items[0].ListCurrency
+	for _, item := range order.Items {
+		if item.ListCurrency != listCurrency {
+			listCurrency = ""
+			break
+		}
+	}
+
+	if currency == "" {
+		currency = listCurrency
+		if currency == "" {
+			currency = models.BaseCurrency
+		}
+	}
+
+	now := time.Now()
+	baseRate, err := s.currencies.Rate(currency, models.BaseCurrency, now)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for i := range order.Items {
+		item := &order.Items[i]
+		rate, err := s.currencies.Rate(item.ListCurrency, currency, now)
+		if err != nil {
+			return err
+		}
+		item.ExchangeRate = rate
+		item.Amount = convertAmount(item.UnitPrice*int64(item.Quantity), item.ListCurrency, currency, rate)
+		total += item.Amount
+	}
+
+	order.Currency = currency
+	order.BaseRate = baseRate
+	if listCurrency != "" {
+		order.ListCurrency = listCurrency
+		order.ExchangeRate = order.Items[0].ExchangeRate
+	} else {
+		order.ListCurrency = currency
+		order.ExchangeRate = 1
+	}
+	order.TotalAmount = total
+	order.FinalAmount = order.TotalAmount
+	return nil
+}
+
 func (s *orderService) GetOrderByID(userID, orderID uint) (*models.Order, error) {
 	order, err := s.orderRepo.GetByID(orderID)
 	if err != nil {
@@ -207,6 +652,11 @@ func (s *orderService) ApprovePayment(orderID uint, adminID uint) error {
 		return err
 	}
 
+	// The payment stands even if issuing fails; admins can issue the keys again
+	if _, err := s.licenses.IssueForOrder(order); err != nil {
+		log.Println("Failed to issue license keys:", err)
+	}
+
 	return nil
 }
 
@@ -239,11 +689,7 @@ func (s *orderService) RejectPayment(orderID uint, adminID uint, reason string)
 	// Update order
 	order.Status = "cancelled"
 	order.PaymentStatus = "failed"
-	if err := s.orderRepo.Update(order); err != nil {
-		return err
-	}
-
-	return nil
+	return s.orderRepo.Cancel(order)
 }
 
 func (s *orderService) CancelOrder(userID, orderID uint) error {
@@ -264,5 +710,5 @@ func (s *orderService) CancelOrder(userID, orderID uint) error {
 
 	order.Status = "cancelled"
 	order.PaymentStatus = "cancelled"
-	return s.orderRepo.Update(order)
+	return s.orderRepo.Cancel(order)
 }
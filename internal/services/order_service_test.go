@@ -0,0 +1,126 @@
+package services
+
+import (
+	"gin-quickstart/internal/models"
+	"testing"
+)
+
+func TestUpgradeCredit(t *testing.T) {
+	personal := &models.LicenseTier{Name: "personal"}
+	commercial := &models.LicenseTier{Name: "commercial"}
+	extended := &models.LicenseTier{Name: "extended"}
+
+	line := func(tier *models.LicenseTier, unitPrice, discount int64, quantity int) models.OrderItem {
+		return models.OrderItem{
+			LicenseTier:    tier,
+			Quantity:       quantity,
+			ListCurrency:   "IDR",
+			UnitPrice:      unitPrice,
+			ExchangeRate:   1,
+			DiscountAmount: discount,
+		}
+	}
+	order := func(orderType string, items ...models.OrderItem) models.Order {
+		return models.Order{OrderType: orderType, Currency: "IDR", Items: items}
+	}
+
+	tests := []struct {
+		name   string
+		orders []models.Order
+		want   int64
+	}{
+		{
+			name:   "single purchase",
+			orders: []models.Order{order("product", line(personal, 100000, 0, 1))},
+			want:   100000,
+		},
+		{
+			name: "repeat purchase of the same tier counts once",
+			orders: []models.Order{
+				order("product", line(personal, 100000, 0, 1)),
+				order("cart", line(personal, 90000, 0, 1)),
+			},
+			want: 90000,
+		},
+		{
+			name:   "coupon discount is taken off",
+			orders: []models.Order{order("product", line(personal, 100000, 20000, 1))},
+			want:   80000,
+		},
+		{
+			name:   "quantity splits the line",
+			orders: []models.Order{order("product", line(personal, 100000, 30000, 3))},
+			want:   90000,
+		},
+		{
+			name: "upgrades add to the purchase",
+			orders: []models.Order{
+				order("product", line(personal, 100000, 0, 1)),
+				order("license_upgrade", line(commercial, 150000, 0, 1)),
+			},
+			want: 250000,
+		},
+		{
+			name: "lower tier bought after an upgrade is ignored",
+			orders: []models.Order{
+				order("product", line(personal, 100000, 0, 1)),
+				order("license_upgrade", line(commercial, 150000, 10000, 1)),
+				order("product", line(personal, 100000, 0, 1)),
+			},
+			want: 240000,
+		},
+		{
+			name: "buying a higher tier outright restarts the chain",
+			orders: []models.Order{
+				order("product", line(personal, 100000, 0, 1)),
+				order("license_upgrade", line(commercial, 150000, 0, 1)),
+				order("product", line(extended, 500000, 0, 1)),
+			},
+			want: 500000,
+		},
+		{
+			name:   "lines without a tier count as the base tier",
+			orders: []models.Order{order("product", line(nil, 70000, 0, 1))},
+			want:   70000,
+		},
+		{
+			name:   "bundle owners start at the base price",
+			orders: []models.Order{order("license_upgrade", line(commercial, 150000, 0, 1))},
+			want:   250000,
+		},
+		{
+			name: "discount in the order currency is converted back",
+			orders: []models.Order{{
+				OrderType: "product",
+				Currency:  "USD",
+				Items: []models.OrderItem{{
+					LicenseTier:    personal,
+					Quantity:       1,
+					ListCurrency:   "IDR",
+					UnitPrice:      160000,
+					ExchangeRate:   0.0000625, // 1 USD = 16000 IDR
+					DiscountAmount: 200,       // 2.00 USD
+				}},
+			}},
+			want: 128000,
+		},
+		{
+			name: "no orders",
+			want: 100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upgradeCredit(tt.orders, licenseTierRanks["personal"], 100000, func(amount int64, currency string) (int64, error) {
+				return amount, nil
+			})
+			if err != nil {
+				t.Fatalf("upgradeCredit() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("upgradeCredit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/pkg/utils"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxArchiveEntries = 20000
+	maxPreviewBytes   = 256 << 10 // 256KB
+)
+
+// fileLanguages maps file extensions to the language reported in the tree and previews
+var fileLanguages = map[string]string{
+	".go": "Go", ".php": "PHP", ".js": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript",
+	".jsx": "JavaScript", ".ts": "TypeScript", ".tsx": "TypeScript", ".vue": "Vue", ".svelte": "Svelte",
+	".py": "Python", ".rb": "Ruby", ".java": "Java", ".kt": "Kotlin", ".swift": "Swift",
+	".dart": "Dart", ".rs": "Rust", ".c": "C", ".h": "C", ".cpp": "C++", ".hpp": "C++",
+	".cs": "C#", ".html": "HTML", ".htm": "HTML", ".css": "CSS", ".scss": "SCSS", ".sass": "Sass",
+	".less": "Less", ".sql": "SQL", ".sh": "Shell", ".bash": "Shell", ".json": "JSON",
+	".yaml": "YAML", ".yml": "YAML", ".xml": "XML", ".toml": "TOML", ".ini": "INI",
+	".md": "Markdown", ".txt": "Text", ".env": "Dotenv", ".blade.php": "Blade",
+}
+
+// fileNameLanguages covers well-known files without a meaningful extension
+var fileNameLanguages = map[string]string{
+	"Dockerfile": "Dockerfile",
+	"Makefile":   "Makefile",
+	"go.mod":     "Go Module",
+	".gitignore": "Ignore List",
+}
+
+type ProductFileService interface {
+	UploadDeliverable(productID uint, file *multipart.FileHeader) (*models.ProductTree, error)
+	GetTree(productID uint) (*models.ProductTree, error)
+	GetFileContent(productID uint, filePath string) (*models.ProductFileContent, error)
+	SetPreviewable(productID uint, req models.ProductFilePreviewRequest) (*models.ProductTree, error)
+}
+
+type productFileService struct {
+	fileRepo    repositories.ProductFileRepository
+	productRepo repositories.ProductRepository
+}
+
+func NewProductFileService(fileRepo repositories.ProductFileRepository, productRepo repositories.ProductRepository) ProductFileService {
+	return &productFileService{
+		fileRepo:    fileRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *productFileService) UploadDeliverable(productID uint, file *multipart.FileHeader) (*models.ProductTree, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	isSourceCode := product.Type == "source_code"
+	if isSourceCode && strings.ToLower(filepath.Ext(file.Filename)) != ".zip" {
+		return nil, errors.New("source code deliverables must be a ZIP archive")
+	}
+
+	filePath, err := utils.UploadDeliverable(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []models.ProductFile
+	if isSourceCode {
+		files, err = indexArchive(filePath)
+		if err != nil {
+			utils.DeleteFile(filePath)
+			return nil, err
+		}
+	}
+
+	oldFile := product.FileURL
+	if err := s.fileRepo.ReplaceDeliverable(product.ID, filePath, files); err != nil {
+		utils.DeleteFile(filePath)
+		return nil, err
+	}
+	if oldFile != "" && oldFile != filePath {
+		utils.DeleteFile(oldFile)
+	}
+
+	return s.buildTree(product.ID)
+}
+
+func (s *productFileService) GetTree(productID uint) (*models.ProductTree, error) {
+	if _, err := s.browsableProduct(productID); err != nil {
+		return nil, err
+	}
+	return s.buildTree(productID)
+}
+
+func (s *productFileService) GetFileContent(productID uint, filePath string) (*models.ProductFileContent, error) {
+	product, err := s.browsableProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, ok := cleanArchivePath(filePath)
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+
+	// Only files an admin explicitly opened up can be read
+	indexed, err := s.fileRepo.GetByProductAndPath(productID, name)
+	if err != nil || !indexed.IsPreviewable {
+		return nil, errors.New("file not found")
+	}
+
+	data, truncated, err := readArchiveFile(product.FileURL, name)
+	if err != nil {
+		return nil, err
+	}
+	data = trimPartialRune(data, truncated)
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return nil, errors.New("binary files cannot be previewed")
+	}
+
+	content := string(data)
+	return &models.ProductFileContent{
+		Path:      indexed.Path,
+		Language:  indexed.Language,
+		Size:      indexed.Size,
+		Lines:     strings.Count(content, "\n") + 1,
+		Content:   content,
+		Truncated: truncated,
+	}, nil
+}
+
+func (s *productFileService) SetPreviewable(productID uint, req models.ProductFilePreviewRequest) (*models.ProductTree, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	paths := make([]string, 0, len(req.Paths))
+	for _, p := range req.Paths {
+		if name, ok := cleanArchivePath(p); ok {
+			paths = append(paths, name)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no valid file paths given")
+	}
+
+	updated, err := s.fileRepo.SetPreviewable(productID, paths, *req.Previewable)
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, errors.New("none of the given files exist in the archive")
+	}
+
+	return s.buildTree(productID)
+}
+
+// browsableProduct returns a published source code product that has an uploaded archive
+func (s *productFileService) browsableProduct(productID uint) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil || product.Status != "published" {
+		return nil, errors.New("product not found")
+	}
+	if product.Type != "source_code" || product.FileURL == "" {
+		return nil, errors.New("product has no browsable source code")
+	}
+	return product, nil
+}
+
+// buildTree nests the flat file index into directories, directories first
+func (s *productFileService) buildTree(productID uint) (*models.ProductTree, error) {
+	files, err := s.fileRepo.GetByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ProductTree{
+		Languages: []models.FacetCount{},
+		Tree:      []*models.ProductTreeNode{},
+	}
+	root := &models.ProductTreeNode{Type: "dir"}
+	dirs := map[string]*models.ProductTreeNode{"": root}
+	languages := make(map[string]int64)
+
+	for _, f := range files {
+		result.FilesCount++
+		result.TotalSize += f.Size
+		if f.Language != "" {
+			languages[f.Language]++
+		}
+
+		parent := root
+		parts := strings.Split(f.Path, "/")
+		for i := range parts[:len(parts)-1] {
+			dirPath := strings.Join(parts[:i+1], "/")
+			dir, ok := dirs[dirPath]
+			if !ok {
+				dir = &models.ProductTreeNode{Name: parts[i], Path: dirPath, Type: "dir"}
+				dirs[dirPath] = dir
+				parent.Children = append(parent.Children, dir)
+			}
+			dir.Size += f.Size
+			parent = dir
+		}
+
+		parent.Children = append(parent.Children, &models.ProductTreeNode{
+			Name:          parts[len(parts)-1],
+			Path:          f.Path,
+			Type:          "file",
+			Size:          f.Size,
+			Language:      f.Language,
+			IsPreviewable: f.IsPreviewable,
+		})
+	}
+
+	sortTree(root)
+	if root.Children != nil {
+		result.Tree = root.Children
+	}
+
+	for lang, count := range languages {
+		result.Languages = append(result.Languages, models.FacetCount{Value: lang, Count: count})
+	}
+	sort.Slice(result.Languages, func(i, j int) bool {
+		if result.Languages[i].Count != result.Languages[j].Count {
+			return result.Languages[i].Count > result.Languages[j].Count
+		}
+		return result.Languages[i].Value < result.Languages[j].Value
+	})
+
+	return result, nil
+}
+
+func sortTree(node *models.ProductTreeNode) {
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if a.Type != b.Type {
+			return a.Type == "dir"
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	for _, child := range node.Children {
+		if child.Type == "dir" {
+			sortTree(child)
+		}
+	}
+}
+
+// indexArchive lists the regular files in a ZIP with their sizes and languages.
+// Contents are not extracted; unsafe and OS junk entries are left out of the index.
+func indexArchive(archivePath string) ([]models.ProductFile, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, errors.New("invalid ZIP archive")
+	}
+	defer reader.Close()
+
+	seen := make(map[string]bool)
+	var files []models.ProductFile
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() || !entry.Mode().IsRegular() {
+			continue
+		}
+		name, ok := cleanArchivePath(entry.Name)
+		if !ok || seen[name] || isArchiveJunk(name) {
+			continue
+		}
+		seen[name] = true
+
+		files = append(files, models.ProductFile{
+			Path:     name,
+			Size:     int64(entry.UncompressedSize64),
+			Language: detectLanguage(name),
+		})
+		if len(files) > maxArchiveEntries {
+			return nil, errors.New("archive contains too many files")
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("archive is empty")
+	}
+	return files, nil
+}
+
+// readArchiveFile reads at most maxPreviewBytes of a single archive entry
+func readArchiveFile(archivePath, name string) ([]byte, bool, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, false, errors.New("product archive is unavailable")
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		if cleaned, ok := cleanArchivePath(entry.Name); !ok || cleaned != name {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, false, err
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(io.LimitReader(rc, maxPreviewBytes+1))
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) > maxPreviewBytes {
+			return data[:maxPreviewBytes], true, nil
+		}
+		return data, false, nil
+	}
+
+	return nil, false, errors.New("file not found")
+}
+
+// cleanArchivePath normalizes an entry name and rejects absolute or escaping paths
+func cleanArchivePath(name string) (string, bool) {
+	name = strings.ReplaceAll(strings.TrimSpace(name), "\\", "/")
+	if name == "" || strings.HasPrefix(name, "/") {
+		return "", false
+	}
+	name = path.Clean(name)
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return name, true
+}
+
+func isArchiveJunk(name string) bool {
+	base := path.Base(name)
+	return strings.HasPrefix(name, "__MACOSX/") || base == ".DS_Store" || base == "Thumbs.db"
+}
+
+func detectLanguage(name string) string {
+	base := path.Base(name)
+	if lang, ok := fileNameLanguages[base]; ok {
+		return lang
+	}
+	lower := strings.ToLower(base)
+	if strings.HasSuffix(lower, ".blade.php") {
+		return fileLanguages[".blade.php"]
+	}
+	return fileLanguages[path.Ext(lower)]
+}
+
+// trimPartialRune drops a multi-byte character cut in half by the preview limit
+func trimPartialRune(data []byte, truncated bool) []byte {
+	if !truncated {
+		return data
+	}
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return data
+		}
+		data = data[:len(data)-1]
+	}
+	return data
+}
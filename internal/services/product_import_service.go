@@ -0,0 +1,411 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// importListSeparator separates list values inside a single CSV cell
+const importListSeparator = "|"
+
+// exportBatchSize is how many products are loaded per query while exporting
+const exportBatchSize = 200
+
+// ProductCSVColumns is the header of exported CSV files and the columns understood on import
+var ProductCSVColumns = []string{
+	"slug", "title", "description", "category_slug", "type", "currency", "price", "discount_price",
+	"demo_url", "tech_stack", "features", "requirements", "status",
+}
+
+// parsedImportRow keeps parse problems (such as a non-numeric price) with the row
+// so they show up in the validation report instead of aborting the import.
+type parsedImportRow struct {
+	models.ProductImportRow
+	errors        []string
+	generatedSlug bool
+}
+
+type ProductImportService interface {
+	Import(r io.Reader, format string, dryRun bool, createdBy uint) (*models.ProductImportReport, error)
+	Export(fn func(row models.ProductImportRow) error) error
+}
+
+type productImportService struct {
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+	slugRepo     repositories.SlugRedirectRepository
+}
+
+func NewProductImportService(productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository, slugRepo repositories.SlugRedirectRepository) ProductImportService {
+	return &productImportService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		slugRepo:     slugRepo,
+	}
+}
+
+// Import validates every row first and only writes when the whole file is valid.
+// With dryRun the validation report is returned without touching the catalog.
+func (s *productImportService) Import(r io.Reader, format string, dryRun bool, createdBy uint) (*models.ProductImportReport, error) {
+	var rows []parsedImportRow
+	var err error
+
+	switch format {
+	case "csv":
+		rows, err = parseProductCSV(r)
+	case "jsonl":
+		rows, err = parseProductJSONL(r)
+	default:
+		return nil, errors.New("format must be csv or jsonl")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("import file has no rows")
+	}
+
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	categoryBySlug := make(map[string]uint, len(categories))
+	for _, category := range categories {
+		categoryBySlug[category.Slug] = category.ID
+	}
+
+	// Resolve slugs up front so existing products are fetched in one query
+	slugs := make([]string, len(rows))
+	for i := range rows {
+		rows[i].Slug = strings.TrimSpace(rows[i].Slug)
+		if rows[i].Slug == "" {
+			rows[i].Slug = generateProductSlug(rows[i].Title)
+			rows[i].generatedSlug = true
+		}
+		slugs[i] = rows[i].Slug
+	}
+
+	existing, err := s.productRepo.GetBySlugsWithDeleted(slugs)
+	if err != nil {
+		return nil, err
+	}
+	existingBySlug := make(map[string]models.Product, len(existing))
+	var liveIDs []uint
+	for _, product := range existing {
+		existingBySlug[product.Slug] = product
+		if needsRevision(&product) {
+			liveIDs = append(liveIDs, product.ID)
+		}
+	}
+
+	// Live products are revised through review instead of being overwritten
+	pending, err := s.productRepo.GetPendingRevisionsFor(liveIDs)
+	if err != nil {
+		return nil, err
+	}
+	pendingByProduct := make(map[uint]models.ProductRevision, len(pending))
+	for _, revision := range pending {
+		pendingByProduct[revision.ProductID] = revision
+	}
+
+	report := &models.ProductImportReport{DryRun: dryRun, Total: len(rows)}
+	firstRow := make(map[string]int, len(rows))
+	var creates, updates []*models.Product
+	var revisions []*models.ProductRevision
+
+	for i, row := range rows {
+		// Row numbers are 1-based data rows, matching what spreadsheet users see below the header
+		result := models.ProductImportRowResult{Row: i + 1, Slug: row.Slug}
+		result.Errors = append(row.errors, validateImportRow(row.ProductImportRow)...)
+
+		current, exists := existingBySlug[row.Slug]
+		if row.Slug != "" && (!exists || current.DeletedAt.Valid) {
+			if row.generatedSlug {
+				// A new product gets a slug that no product uses or redirects from
+				slug, err := uniqueSlug(s.slugRepo, "product", row.Slug, 0)
+				if err != nil {
+					return nil, err
+				}
+				row.Slug, result.Slug = slug, slug
+				current, exists = models.Product{}, false
+			} else if !exists {
+				taken, err := s.slugRepo.IsTaken("product", row.Slug, 0)
+				if err != nil {
+					return nil, err
+				}
+				if taken {
+					result.Errors = append(result.Errors, "slug collision: "+row.Slug+" redirects to another product")
+				}
+			}
+		}
+
+		if row.Slug != "" {
+			if prev, dup := firstRow[row.Slug]; dup {
+				result.Errors = append(result.Errors, fmt.Sprintf("slug collision: %s is also used on row %d", row.Slug, prev))
+			} else {
+				firstRow[row.Slug] = result.Row
+			}
+		}
+
+		categoryID, ok := categoryBySlug[row.CategorySlug]
+		if !ok {
+			result.Errors = append(result.Errors, "unknown category: "+row.CategorySlug)
+		}
+
+		if exists && current.DeletedAt.Valid {
+			result.Errors = append(result.Errors, "slug collision: "+row.Slug+" belongs to a deleted product")
+		}
+
+		if len(result.Errors) > 0 {
+			result.Action = "skip"
+			report.Failed++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		product := &current
+		switch {
+		case exists && needsRevision(product):
+			revision := &models.ProductRevision{ProductID: product.ID, Status: "pending"}
+			if prev, ok := pendingByProduct[product.ID]; ok {
+				revision = &prev
+				applyProductContent(product, prev.Content)
+			}
+			applyImportRow(product, row.ProductImportRow, categoryID)
+			revision.Content = productContent(product)
+			revision.SubmittedBy = createdBy
+			revisions = append(revisions, revision)
+			result.Action = "review"
+			report.PendingReview++
+		case exists:
+			applyImportRow(product, row.ProductImportRow, categoryID)
+			updates = append(updates, product)
+			result.Action = "update"
+			report.Updated++
+		default:
+			product = &models.Product{Slug: row.Slug, Status: "draft", CreatedBy: createdBy}
+			applyImportRow(product, row.ProductImportRow, categoryID)
+			creates = append(creates, product)
+			result.Action = "create"
+			report.Created++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	if dryRun || report.Failed > 0 {
+		return report, nil
+	}
+
+	if err := s.productRepo.Import(creates, updates, revisions); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+
+	return report, nil
+}
+
+func (s *productImportService) Export(fn func(row models.ProductImportRow) error) error {
+	return s.productRepo.EachInBatches(exportBatchSize, func(products []models.Product) error {
+		for _, product := range products {
+			row := models.ProductImportRow{
+				Slug:          product.Slug,
+				Title:         product.Title,
+				Description:   product.Description,
+				Type:          product.Type,
+				Currency:      product.Currency,
+				Price:         product.Price,
+				DiscountPrice: product.DiscountPrice,
+				DemoURL:       product.DemoURL,
+				TechStack:     product.TechStack,
+				Features:      product.Features,
+				Requirements:  product.Requirements,
+				Status:        product.Status,
+			}
+			if product.Category != nil {
+				row.CategorySlug = product.Category.Slug
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func validateImportRow(row models.ProductImportRow) []string {
+	var errs []string
+	if strings.TrimSpace(row.Title) == "" {
+		errs = append(errs, "title is required")
+	}
+	if row.Slug == "" {
+		errs = append(errs, "slug could not be generated from title")
+	}
+	if !repositories.IsProductType(row.Type) {
+		errs = append(errs, "invalid type: "+row.Type)
+	}
+	if _, ok := models.Currencies[importCurrency(row)]; !ok {
+		errs = append(errs, "unsupported currency: "+row.Currency)
+	}
+	if row.Price <= 0 {
+		errs = append(errs, "price must be greater than 0")
+	}
+	if row.DiscountPrice != nil && (*row.DiscountPrice < 0 || *row.DiscountPrice >= row.Price) {
+		errs = append(errs, "discount_price must be between 0 and price")
+	}
+	return errs
+}
+
+// applyImportRow copies imported fields onto the product; workflow status is left untouched
+func applyImportRow(product *models.Product, row models.ProductImportRow, categoryID uint) {
+	product.Title = strings.TrimSpace(row.Title)
+	product.Description = row.Description
+	product.CategoryID = categoryID
+	product.Category = nil
+	product.Type = row.Type
+	product.Currency = importCurrency(row)
+	product.Price = row.Price
+	product.DiscountPrice = row.DiscountPrice
+	product.DemoURL = row.DemoURL
+	product.TechStack = normalizeTechStack(row.TechStack)
+	product.Features = normalizeList(row.Features)
+	product.Requirements = normalizeList(row.Requirements)
+}
+
+// importCurrency is the row's currency code, defaulting to the base currency
+func importCurrency(row models.ProductImportRow) string {
+	if row.Currency == "" {
+		return models.BaseCurrency
+	}
+	return strings.ToUpper(row.Currency)
+}
+
+func parseProductCSV(r io.Reader) ([]parsedImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("csv file has no header row")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"title", "category_slug", "type", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("csv header is missing column: " + required)
+		}
+	}
+
+	var rows []parsedImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := parsedImportRow{ProductImportRow: models.ProductImportRow{
+			Slug:         get("slug"),
+			Title:        get("title"),
+			Description:  get("description"),
+			CategorySlug: get("category_slug"),
+			Type:         get("type"),
+			Currency:     get("currency"),
+			DemoURL:      get("demo_url"),
+			TechStack:    splitImportList(get("tech_stack")),
+			Features:     splitImportList(get("features")),
+			Requirements: splitImportList(get("requirements")),
+		}}
+
+		if price, err := strconv.ParseInt(get("price"), 10, 64); err == nil {
+			row.Price = price
+		} else {
+			row.errors = append(row.errors, "invalid price: "+get("price"))
+		}
+		if raw := get("discount_price"); raw != "" {
+			if val, err := strconv.ParseInt(raw, 10, 64); err == nil {
+				row.DiscountPrice = &val
+			} else {
+				row.errors = append(row.errors, "invalid discount_price: "+raw)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseProductJSONL(r io.Reader) ([]parsedImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+
+	var rows []parsedImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var row parsedImportRow
+		if err := json.Unmarshal([]byte(text), &row.ProductImportRow); err != nil {
+			row.errors = append(row.errors, fmt.Sprintf("invalid json on line %d: %v", line, err))
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func splitImportList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, importListSeparator)
+}
+
+// ProductCSVRecord flattens an export row into ProductCSVColumns order
+func ProductCSVRecord(row models.ProductImportRow) []string {
+	discount := ""
+	if row.DiscountPrice != nil {
+		discount = strconv.FormatInt(*row.DiscountPrice, 10)
+	}
+	return []string{
+		row.Slug,
+		row.Title,
+		row.Description,
+		row.CategorySlug,
+		row.Type,
+		row.Currency,
+		strconv.FormatInt(row.Price, 10),
+		discount,
+		row.DemoURL,
+		strings.Join(row.TechStack, importListSeparator),
+		strings.Join(row.Features, importListSeparator),
+		strings.Join(row.Requirements, importListSeparator),
+		row.Status,
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"strings"
+	"time"
+)
+
+type ProductQuestionService interface {
+	AskQuestion(userID, productID uint, req models.ProductQuestionRequest) (*models.ProductQuestion, error)
+	GetProductQuestions(productID uint, sort string, page, limit int) ([]models.ProductQuestion, int64, error)
+	AnswerQuestion(questionID, userID uint, userRole string, req models.ProductAnswerRequest) (*models.ProductAnswer, error)
+	Upvote(questionID, userID uint) error
+	RemoveUpvote(questionID, userID uint) error
+	DeleteQuestion(questionID, userID uint) error
+	AdminGetQuestions(status string, page, limit int) ([]models.ProductQuestion, int64, error)
+	Approve(questionID uint) (*models.ProductQuestion, error)
+	Reject(questionID uint, reason string) (*models.ProductQuestion, error)
+	AdminDeleteQuestion(questionID uint) error
+	AdminDeleteAnswer(answerID uint) error
+}
+
+type productQuestionService struct {
+	questionRepo        repositories.ProductQuestionRepository
+	productRepo         repositories.ProductRepository
+	notificationService NotificationService
+}
+
+func NewProductQuestionService(questionRepo repositories.ProductQuestionRepository, productRepo repositories.ProductRepository, notificationService NotificationService) ProductQuestionService {
+	return &productQuestionService{
+		questionRepo:        questionRepo,
+		productRepo:         productRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *productQuestionService) AskQuestion(userID, productID uint, req models.ProductQuestionRequest) (*models.ProductQuestion, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil || product.Status != "published" {
+		return nil, errors.New("product not found")
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("question cannot be empty")
+	}
+
+	// New questions wait for moderation before they are listed
+	question := &models.ProductQuestion{
+		ProductID: productID,
+		UserID:    userID,
+		Body:      body,
+		Status:    "pending",
+	}
+
+	if err := s.questionRepo.Create(question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (s *productQuestionService) GetProductQuestions(productID uint, sort string, page, limit int) ([]models.ProductQuestion, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil || product.Status != "published" {
+		return nil, 0, errors.New("product not found")
+	}
+
+	offset := (page - 1) * limit
+	return s.questionRepo.GetByProductID(productID, sort, limit, offset)
+}
+
+// AnswerQuestion lets the product's creator or an admin reply, and the asker is
+// notified. Answers do not moderate the question: a pending question stays
+// hidden until an admin approves it.
+func (s *productQuestionService) AnswerQuestion(questionID, userID uint, userRole string, req models.ProductAnswerRequest) (*models.ProductAnswer, error) {
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil || question.Product == nil {
+		return nil, errors.New("question not found")
+	}
+
+	isCreator := question.Product.CreatedBy == userID
+	if !isCreator && userRole != "admin" {
+		return nil, errors.New("only the product creator or an admin can answer questions")
+	}
+	if question.Status == "rejected" {
+		return nil, errors.New("rejected questions cannot be answered")
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errors.New("answer cannot be empty")
+	}
+
+	answer := &models.ProductAnswer{
+		QuestionID: question.ID,
+		UserID:     userID,
+		Body:       body,
+		IsCreator:  isCreator,
+	}
+	if err := s.questionRepo.CreateAnswer(answer); err != nil {
+		return nil, err
+	}
+
+	if question.UserID != userID {
+		s.notificationService.CreateNotification(
+			question.UserID,
+			"question",
+			"Your question has been answered",
+			fmt.Sprintf("Your question about %s has a new answer.", question.Product.Title),
+		)
+	}
+
+	return answer, nil
+}
+
+func (s *productQuestionService) Upvote(questionID, userID uint) error {
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil || question.Status != "approved" {
+		return errors.New("question not found")
+	}
+	if question.UserID == userID {
+		return errors.New("you cannot upvote your own question")
+	}
+
+	added, err := s.questionRepo.AddVote(questionID, userID)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return errors.New("you already upvoted this question")
+	}
+	return nil
+}
+
+func (s *productQuestionService) RemoveUpvote(questionID, userID uint) error {
+	removed, err := s.questionRepo.RemoveVote(questionID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("upvote not found")
+	}
+	return nil
+}
+
+func (s *productQuestionService) DeleteQuestion(questionID, userID uint) error {
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return errors.New("question not found")
+	}
+	if question.UserID != userID {
+		return errors.New("unauthorized to delete this question")
+	}
+	return s.questionRepo.Delete(questionID)
+}
+
+func (s *productQuestionService) AdminGetQuestions(status string, page, limit int) ([]models.ProductQuestion, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+	return s.questionRepo.GetByStatus(status, limit, offset)
+}
+
+func (s *productQuestionService) Approve(questionID uint) (*models.ProductQuestion, error) {
+	return s.moderate(questionID, "approved", "")
+}
+
+func (s *productQuestionService) Reject(questionID uint, reason string) (*models.ProductQuestion, error) {
+	return s.moderate(questionID, "rejected", reason)
+}
+
+func (s *productQuestionService) moderate(questionID uint, status, reason string) (*models.ProductQuestion, error) {
+	question, err := s.questionRepo.GetByID(questionID)
+	if err != nil {
+		return nil, errors.New("question not found")
+	}
+	if question.Status == status {
+		return nil, errors.New("question is already " + status)
+	}
+
+	now := time.Now()
+	question.Status = status
+	question.RejectReason = strings.TrimSpace(reason)
+	question.ModeratedAt = &now
+
+	if err := s.questionRepo.Update(question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (s *productQuestionService) AdminDeleteQuestion(questionID uint) error {
+	if _, err := s.questionRepo.GetByID(questionID); err != nil {
+		return errors.New("question not found")
+	}
+	return s.questionRepo.Delete(questionID)
+}
+
+func (s *productQuestionService) AdminDeleteAnswer(answerID uint) error {
+	answer, err := s.questionRepo.GetAnswerByID(answerID)
+	if err != nil {
+		return errors.New("answer not found")
+	}
+	return s.questionRepo.DeleteAnswer(answer)
+}
@@ -5,59 +5,71 @@ import (
 	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/repositories"
 	"gin-quickstart/pkg/utils"
+	"log"
 	"mime/multipart"
 	"strings"
 	"unicode"
+
+	"gorm.io/gorm"
 )
 
 type ProductService interface {
 	CreateProduct(req CreateProductRequest, file *multipart.FileHeader, createdBy uint) (*models.Product, error)
-	GetAllProducts(page, limit int, categoryID *uint, search string) ([]models.Product, int64, error)
+	ValidateFilter(filter *models.ProductFilter) error
+	GetAllProducts(page, limit int, filter models.ProductFilter) ([]models.Product, int64, error)
 	GetProductByID(id uint) (*models.Product, error)
 	GetProductBySlug(slug string) (*models.Product, error)
-	UpdateProduct(id uint, req UpdateProductRequest, file *multipart.FileHeader) (*models.Product, error)
-	DeleteProduct(id uint) error
-	GetFeaturedProducts(limit int) ([]models.Product, error)
-	GetProductsByCategory(categoryID uint, page, limit int) ([]models.Product, int64, error)
+	ResolveSlugRedirect(oldSlug string) (string, error)
+	UpdateProduct(id uint, req UpdateProductRequest, file *multipart.FileHeader, updatedBy uint) (*models.Product, *models.ProductRevision, error)
+	DeleteProduct(id, deletedBy uint) error
+	GetProductsByCategory(categoryID uint, includeSubcategories bool, page, limit int) ([]models.Product, int64, error)
+	GetProductFacets(filter models.ProductFilter) (*models.ProductFacets, error)
+	SearchTechStacks(prefix string, limit int) ([]models.FacetCount, error)
 }
 
 type CreateProductRequest struct {
-	Title         string   `json:"title" binding:"required"`
-	Description   string   `json:"description"`
-	Type          string   `json:"type" binding:"required"`
-	Price         float64  `json:"price" binding:"required,gt=0"`
-	DiscountPrice *float64 `json:"discount_price"`
-	CategoryID    uint     `json:"category_id" binding:"required"`
-	DemoURL       string   `json:"demo_url"`
-	TechStack     []string `json:"tech_stack"`
-	Features      []string `json:"features"`
-	Requirements  []string `json:"requirements"`
-	IsActive      bool     `json:"is_active"`
+	Title           string   `json:"title" form:"title" binding:"required"`
+	Description     string   `json:"description" form:"description"`
+	Type            string   `json:"type" form:"type" binding:"required"`
+	Currency        string   `json:"currency" form:"currency"` // defaults to BaseCurrency
+	Price           int64    `json:"price" form:"price" binding:"required,gt=0"`
+	DiscountPrice   *int64   `json:"discount_price" form:"discount_price"`
+	CategoryID      uint     `json:"category_id" form:"category_id" binding:"required"`
+	DemoURL         string   `json:"demo_url" form:"demo_url"`
+	TechStack       []string `json:"tech_stack" form:"tech_stack"`
+	Features        []string `json:"features" form:"features"`
+	Requirements    []string `json:"requirements" form:"requirements"`
+	RequiresLicense bool     `json:"requires_license" form:"requires_license"`
 }
 
 type UpdateProductRequest struct {
-	Title         string   `json:"title"`
-	Description   string   `json:"description"`
-	Type          string   `json:"type"`
-	Price         float64  `json:"price"`
-	DiscountPrice *float64 `json:"discount_price"`
-	CategoryID    uint     `json:"category_id"`
-	DemoURL       string   `json:"demo_url"`
-	TechStack     []string `json:"tech_stack"`
-	Features      []string `json:"features"`
-	Requirements  []string `json:"requirements"`
-	IsActive      bool     `json:"is_active"`
+	Title           string   `json:"title" form:"title"`
+	Description     string   `json:"description" form:"description"`
+	Type            string   `json:"type" form:"type"`
+	Currency        string   `json:"currency" form:"currency"`
+	Price           int64    `json:"price" form:"price"`
+	DiscountPrice   *int64   `json:"discount_price" form:"discount_price"`
+	CategoryID      uint     `json:"category_id" form:"category_id"`
+	DemoURL         string   `json:"demo_url" form:"demo_url"`
+	TechStack       []string `json:"tech_stack" form:"tech_stack"`
+	Features        []string `json:"features" form:"features"`
+	Requirements    []string `json:"requirements" form:"requirements"`
+	RequiresLicense *bool    `json:"requires_license" form:"requires_license"`
 }
 
 type productService struct {
 	productRepo  repositories.ProductRepository
 	categoryRepo repositories.CategoryRepository
+	slugRepo     repositories.SlugRedirectRepository
+	campaigns    SaleCampaignService
 }
 
-func NewProductService(productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository) ProductService {
+func NewProductService(productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository, slugRepo repositories.SlugRedirectRepository, campaigns SaleCampaignService) ProductService {
 	return &productService{
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
+		slugRepo:     slugRepo,
+		campaigns:    campaigns,
 	}
 }
 
@@ -67,22 +79,32 @@ func (s *productService) CreateProduct(req CreateProductRequest, file *multipart
 		return nil, errors.New("category not found")
 	}
 
-	slug := generateProductSlug(req.Title)
+	slug, err := uniqueSlug(s.slugRepo, "product", generateProductSlug(req.Title), 0)
+	if err != nil {
+		return nil, err
+	}
 
-	existing, _ := s.productRepo.GetBySlug(slug)
-	if existing != nil {
-		return nil, errors.New("product with this title already exists")
+	currency := models.BaseCurrency
+	if req.Currency != "" {
+		if currency, err = normalizeCurrency(req.Currency); err != nil {
+			return nil, err
+		}
 	}
 
 	product := &models.Product{
-		Title:       req.Title,
-		Slug:        slug,
-		Description: req.Description,
-		Type:        req.Type,
-		Price:       req.Price,
-		CategoryID:  req.CategoryID,
-		IsActive:    req.IsActive,
-		CreatedBy:   createdBy,
+		Title:           req.Title,
+		Slug:            slug,
+		Description:     req.Description,
+		Type:            req.Type,
+		Currency:        currency,
+		Price:           req.Price,
+		CategoryID:      req.CategoryID,
+		TechStack:       normalizeTechStack(req.TechStack),
+		Features:        normalizeList(req.Features),
+		Requirements:    normalizeList(req.Requirements),
+		RequiresLicense: req.RequiresLicense,
+		Status:          "draft",
+		CreatedBy:       createdBy,
 	}
 
 	if req.DiscountPrice != nil {
@@ -109,10 +131,20 @@ func (s *productService) CreateProduct(req CreateProductRequest, file *multipart
 		return nil, err
 	}
 
+	if err := s.campaigns.RecordProductPrice(product); err != nil {
+		log.Println("Failed to record product price:", err)
+	}
+
 	return product, nil
 }
 
-func (s *productService) GetAllProducts(page, limit int, categoryID *uint, search string) ([]models.Product, int64, error) {
+// ValidateFilter checks a client supplied listing filter, so callers can
+// tell bad requests apart from failing queries
+func (s *productService) ValidateFilter(filter *models.ProductFilter) error {
+	return repositories.ValidateProductFilter(filter)
+}
+
+func (s *productService) GetAllProducts(page, limit int, filter models.ProductFilter) ([]models.Product, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -120,34 +152,129 @@ func (s *productService) GetAllProducts(page, limit int, categoryID *uint, searc
 		limit = 10
 	}
 
-	return s.productRepo.GetAll(page, limit, categoryID, search)
+	products, total, err := s.productRepo.GetAll(page, limit, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.campaigns.ApplySalePrices(productPointers(products)...); err != nil {
+		return nil, 0, err
+	}
+	if err := s.applyBreadcrumbs(productPointers(products)...); err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
 }
 
 func (s *productService) GetProductByID(id uint) (*models.Product, error) {
 	product, err := s.productRepo.GetByID(id)
-	if err != nil {
+	if err != nil || product.Status != "published" {
 		return nil, errors.New("product not found")
 	}
-	return product, nil
+	return s.withPricing(product)
 }
 
 func (s *productService) GetProductBySlug(slug string) (*models.Product, error) {
 	product, err := s.productRepo.GetBySlug(slug)
-	if err != nil {
+	if err != nil || product.Status != "published" {
 		return nil, errors.New("product not found")
 	}
+	return s.withPricing(product)
+}
+
+// withPricing adds the running sale and the 30-day lowest price to a product detail
+func (s *productService) withPricing(product *models.Product) (*models.Product, error) {
+	if err := s.campaigns.ApplySalePrices(product); err != nil {
+		return nil, err
+	}
+	if err := s.campaigns.ApplyLowestPrice(product); err != nil {
+		return nil, err
+	}
+	if err := s.applyBreadcrumbs(product); err != nil {
+		return nil, err
+	}
 	return product, nil
 }
 
-func (s *productService) UpdateProduct(id uint, req UpdateProductRequest, file *multipart.FileHeader) (*models.Product, error) {
+// applyBreadcrumbs sets the category path of each product
+func (s *productService) applyBreadcrumbs(products ...*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	seen := make(map[uint]bool, len(products))
+	var ids []uint
+	for _, product := range products {
+		if !seen[product.CategoryID] {
+			seen[product.CategoryID] = true
+			ids = append(ids, product.CategoryID)
+		}
+	}
+
+	categories, err := s.categoryRepo.GetAncestors(ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	for _, product := range products {
+		product.Breadcrumbs = categoryBreadcrumbs(byID, product.CategoryID)
+	}
+	return nil
+}
+
+// ResolveSlugRedirect returns the current slug of a published product that used to have oldSlug
+func (s *productService) ResolveSlugRedirect(oldSlug string) (string, error) {
+	id, err := s.slugRepo.Resolve("product", oldSlug)
+	if err != nil {
+		return "", errors.New("product not found")
+	}
+
+	product, err := s.productRepo.GetByID(id)
+	if err != nil || product.Status != "published" {
+		return "", errors.New("product not found")
+	}
+	return product.Slug, nil
+}
+
+// UpdateProduct edits a product. Products that are not live yet change right
+// away; edits of published or scheduled products are stored as a pending
+// revision, returned instead of the product, until a reviewer approves them.
+func (s *productService) UpdateProduct(id uint, req UpdateProductRequest, file *multipart.FileHeader, updatedBy uint) (*models.Product, *models.ProductRevision, error) {
 	product, err := s.productRepo.GetByID(id)
 	if err != nil {
-		return nil, errors.New("product not found")
+		return nil, nil, errors.New("product not found")
+	}
+
+	var revision *models.ProductRevision
+	if needsRevision(product) {
+		revision, err = s.productRepo.GetPendingRevision(product.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			revision, err = &models.ProductRevision{ProductID: product.ID, Status: "pending"}, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		// Edits build on the changes already waiting for review
+		if revision.ID != 0 {
+			applyProductContent(product, revision.Content)
+		}
 	}
 
-	if req.Title != "" {
+	oldSlug := product.Slug
+	if req.Title != "" && req.Title != product.Title {
+		// A revision's slug is chosen when it is approved
+		if revision == nil {
+			slug, err := uniqueSlug(s.slugRepo, "product", generateProductSlug(req.Title), product.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			product.Slug = slug
+		}
 		product.Title = req.Title
-		product.Slug = generateProductSlug(req.Title)
 	}
 	if req.Description != "" {
 		product.Description = req.Description
@@ -155,6 +282,13 @@ func (s *productService) UpdateProduct(id uint, req UpdateProductRequest, file *
 	if req.Type != "" {
 		product.Type = req.Type
 	}
+	if req.Currency != "" {
+		currency, err := normalizeCurrency(req.Currency)
+		if err != nil {
+			return nil, nil, err
+		}
+		product.Currency = currency
+	}
 	if req.Price > 0 {
 		product.Price = req.Price
 	}
@@ -164,15 +298,25 @@ func (s *productService) UpdateProduct(id uint, req UpdateProductRequest, file *
 	if req.CategoryID > 0 {
 		_, err := s.categoryRepo.GetByID(req.CategoryID)
 		if err != nil {
-			return nil, errors.New("category not found")
+			return nil, nil, errors.New("category not found")
 		}
 		product.CategoryID = req.CategoryID
 	}
 	if req.DemoURL != "" {
 		product.DemoURL = req.DemoURL
 	}
-
-	product.IsActive = req.IsActive
+	if req.TechStack != nil {
+		product.TechStack = normalizeTechStack(req.TechStack)
+	}
+	if req.Features != nil {
+		product.Features = normalizeList(req.Features)
+	}
+	if req.Requirements != nil {
+		product.Requirements = normalizeList(req.Requirements)
+	}
+	if req.RequiresLicense != nil {
+		product.RequiresLicense = *req.RequiresLicense
+	}
 
 	if file != nil {
 		if product.PreviewImages != "" {
@@ -181,39 +325,88 @@ func (s *productService) UpdateProduct(id uint, req UpdateProductRequest, file *
 
 		imagePath, err := utils.UploadFile(file, "products")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		product.PreviewImages = `["` + imagePath + `"]`
 	}
 
-	if err := s.productRepo.Update(product); err != nil {
-		return nil, err
+	if revision != nil {
+		revision.Content = productContent(product)
+		revision.SubmittedBy = updatedBy
+		if err := s.productRepo.SaveRevision(revision); err != nil {
+			return nil, nil, err
+		}
+		return nil, revision, nil
 	}
 
-	return product, nil
-}
+	// Keep old links working after a rename
+	if err := s.productRepo.UpdateWithSlug(product, oldSlug); err != nil {
+		return nil, nil, err
+	}
 
-func (s *productService) DeleteProduct(id uint) error {
-	product, err := s.productRepo.GetByID(id)
-	if err != nil {
-		return errors.New("product not found")
+	if err := s.campaigns.RecordProductPrice(product); err != nil {
+		log.Println("Failed to record product price:", err)
 	}
 
-	if product.PreviewImages != "" {
+	return product, nil, nil
+}
+
+// needsRevision reports whether edits of the product must be reviewed before
+// they go live: it is on the storefront or already approved to be.
+func needsRevision(product *models.Product) bool {
+	return product.Status == "published" || product.Status == "scheduled"
+}
+
+// productContent captures the product's editable content for a revision
+func productContent(product *models.Product) models.ProductRevisionContent {
+	return models.ProductRevisionContent{
+		Title:           product.Title,
+		Description:     product.Description,
+		CategoryID:      product.CategoryID,
+		Type:            product.Type,
+		Currency:        product.Currency,
+		Price:           product.Price,
+		DiscountPrice:   product.DiscountPrice,
+		PreviewImages:   product.PreviewImages,
+		DemoURL:         product.DemoURL,
+		TechStack:       product.TechStack,
+		Features:        product.Features,
+		Requirements:    product.Requirements,
+		RequiresLicense: product.RequiresLicense,
 	}
+}
 
-	return s.productRepo.Delete(id)
+// applyProductContent copies a revision's content onto the product, leaving its slug alone
+func applyProductContent(product *models.Product, content models.ProductRevisionContent) {
+	product.Title = content.Title
+	product.Description = content.Description
+	if product.CategoryID != content.CategoryID {
+		product.CategoryID = content.CategoryID
+		product.Category = nil
+	}
+	product.Type = content.Type
+	product.Currency = content.Currency
+	product.Price = content.Price
+	product.DiscountPrice = content.DiscountPrice
+	product.PreviewImages = content.PreviewImages
+	product.DemoURL = content.DemoURL
+	product.TechStack = content.TechStack
+	product.Features = content.Features
+	product.Requirements = content.Requirements
+	product.RequiresLicense = content.RequiresLicense
 }
 
-func (s *productService) GetFeaturedProducts(limit int) ([]models.Product, error) {
-	if limit < 1 {
-		limit = 10
+// DeleteProduct moves the product to the trash. Its files are kept until it
+// is purged, so it can still be restored.
+func (s *productService) DeleteProduct(id, deletedBy uint) error {
+	if _, err := s.productRepo.GetByID(id); err != nil {
+		return errors.New("product not found")
 	}
-	products, _, err := s.productRepo.GetAll(1, limit, nil, "")
-	return products, err
+
+	return s.productRepo.Delete(id, deletedBy)
 }
 
-func (s *productService) GetProductsByCategory(categoryID uint, page, limit int) ([]models.Product, int64, error) {
+func (s *productService) GetProductsByCategory(categoryID uint, includeSubcategories bool, page, limit int) ([]models.Product, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -227,7 +420,68 @@ func (s *productService) GetProductsByCategory(categoryID uint, page, limit int)
 		return nil, 0, errors.New("category not found")
 	}
 
-	return s.productRepo.GetByCategory(categoryID, page, limit)
+	products, total, err := s.productRepo.GetByCategory(categoryID, includeSubcategories, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.campaigns.ApplySalePrices(productPointers(products)...); err != nil {
+		return nil, 0, err
+	}
+	if err := s.applyBreadcrumbs(productPointers(products)...); err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (s *productService) GetProductFacets(filter models.ProductFilter) (*models.ProductFacets, error) {
+	return s.productRepo.GetFacets(filter)
+}
+
+func (s *productService) SearchTechStacks(prefix string, limit int) ([]models.FacetCount, error) {
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	return s.productRepo.SearchTechStacks(strings.TrimSpace(prefix), limit)
+}
+
+// productPointers lets listings be passed to ApplySalePrices in place
+func productPointers(products []models.Product) []*models.Product {
+	pointers := make([]*models.Product, len(products))
+	for i := range products {
+		pointers[i] = &products[i]
+	}
+	return pointers
+}
+
+// normalizeTechStack trims tags, splits comma separated form values and drops
+// case-insensitive duplicates so "Laravel" and "laravel " count as one tag.
+func normalizeTechStack(tags []string) models.StringArray {
+	seen := make(map[string]bool)
+	result := models.StringArray{}
+	for _, raw := range tags {
+		for _, tag := range strings.Split(raw, ",") {
+			tag = strings.TrimSpace(tag)
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// normalizeList trims entries and drops empty ones
+func normalizeList(items []string) models.StringArray {
+	result := models.StringArray{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
 }
 
 // generateProductSlug creates URL-friendly slug from name
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"gin-quickstart/internal/repositories"
+	"log"
+	"sync"
+	"time"
+)
+
+type ProductViewService interface {
+	RecordView(productID uint, visitorKey string)
+	Flush() error
+}
+
+type viewBucket struct {
+	day       time.Time
+	productID uint
+}
+
+// productViewService buffers product views in memory and flushes them in batches.
+// A visitor is counted at most once per product within the dedupe window.
+type productViewService struct {
+	analyticsRepo repositories.AnalyticsRepository
+	window        time.Duration
+
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	pending map[viewBucket]int
+}
+
+func NewProductViewService(analyticsRepo repositories.AnalyticsRepository, window, flushInterval time.Duration) ProductViewService {
+	s := &productViewService{
+		analyticsRepo: analyticsRepo,
+		window:        window,
+		seen:          make(map[string]time.Time),
+		pending:       make(map[viewBucket]int),
+	}
+
+	// Flush buffered views periodically
+	go s.run(flushInterval)
+
+	return s
+}
+
+func (s *productViewService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.Flush(); err != nil {
+			log.Println("Failed to flush product views:", err)
+		}
+	}
+}
+
+func (s *productViewService) RecordView(productID uint, visitorKey string) {
+	now := time.Now()
+	key := fmt.Sprintf("%s:%d", visitorKey, productID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.seen[key]; ok && now.Sub(last) < s.window {
+		return
+	}
+	s.seen[key] = now
+
+	y, m, d := now.Date()
+	s.pending[viewBucket{day: time.Date(y, m, d, 0, 0, 0, 0, now.Location()), productID: productID}]++
+}
+
+func (s *productViewService) Flush() error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[viewBucket]int)
+
+	// Drop dedupe entries that fell out of the window
+	now := time.Now()
+	for key, last := range s.seen {
+		if now.Sub(last) >= s.window {
+			delete(s.seen, key)
+		}
+	}
+	s.mu.Unlock()
+
+	byDay := make(map[time.Time]map[uint]int)
+	for bucket, count := range batch {
+		if byDay[bucket.day] == nil {
+			byDay[bucket.day] = make(map[uint]int)
+		}
+		byDay[bucket.day][bucket.productID] += count
+	}
+
+	var flushErr error
+	for day, counts := range byDay {
+		if err := s.analyticsRepo.RecordProductViews(day, counts); err != nil {
+			// Put the batch back so it is retried on the next flush
+			s.mu.Lock()
+			for productID, count := range counts {
+				s.pending[viewBucket{day: day, productID: productID}] += count
+			}
+			s.mu.Unlock()
+			flushErr = err
+		}
+	}
+
+	return flushErr
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"log"
+	"time"
+)
+
+// productTransitions lists the workflow states a product may move to from each state.
+// Publishing always goes through in_review; scheduled products are published by the scheduler.
+var productTransitions = map[string][]string{
+	"draft":     {"in_review", "archived"},
+	"in_review": {"draft", "scheduled", "published"},
+	"scheduled": {"draft", "published"},
+	"published": {"draft", "archived"},
+	"archived":  {"draft"},
+}
+
+type ProductWorkflowService interface {
+	GetProducts(page, limit int, filter models.ProductFilter) ([]models.Product, int64, error)
+	GetProduct(id uint) (*models.Product, []models.ProductStatusLog, error)
+	SubmitForReview(id, actorID uint, comment string) (*models.Product, error)
+	Approve(id, reviewerID uint, req models.ProductWorkflowRequest) (*models.Product, error)
+	Reject(id, reviewerID uint, comment string) (*models.Product, error)
+	Archive(id, actorID uint, comment string) (*models.Product, error)
+	RevertToDraft(id, actorID uint, comment string) (*models.Product, error)
+	GetPendingRevisions(page, limit int) ([]models.ProductRevision, int64, error)
+	GetPendingRevision(id uint) (*models.ProductRevision, error)
+	ApproveRevision(id, reviewerID uint, comment string) (*models.Product, error)
+	RejectRevision(id, reviewerID uint, comment string) (*models.ProductRevision, error)
+	PublishDue() error
+}
+
+type productWorkflowService struct {
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+	slugRepo     repositories.SlugRedirectRepository
+	campaigns    SaleCampaignService
+}
+
+func NewProductWorkflowService(
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	slugRepo repositories.SlugRedirectRepository,
+	campaigns SaleCampaignService,
+	checkInterval time.Duration,
+) ProductWorkflowService {
+	s := &productWorkflowService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		slugRepo:     slugRepo,
+		campaigns:    campaigns,
+	}
+
+	// Publish scheduled products once their publish_at has passed
+	go s.run(checkInterval)
+
+	return s
+}
+
+func (s *productWorkflowService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.PublishDue(); err != nil {
+			log.Println("Failed to publish scheduled products:", err)
+		}
+	}
+}
+
+func (s *productWorkflowService) PublishDue() error {
+	count, err := s.productRepo.PublishDue(time.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("Published %d scheduled product(s)", count)
+	}
+	return nil
+}
+
+func (s *productWorkflowService) GetProducts(page, limit int, filter models.ProductFilter) ([]models.Product, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	// Admin listings include every state unless narrowed down
+	if len(filter.Statuses) == 0 {
+		for status := range productTransitions {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	return s.productRepo.GetAll(page, limit, filter)
+}
+
+func (s *productWorkflowService) GetProduct(id uint) (*models.Product, []models.ProductStatusLog, error) {
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, errors.New("product not found")
+	}
+
+	logs, err := s.productRepo.GetStatusLogs(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, logs, nil
+}
+
+func (s *productWorkflowService) SubmitForReview(id, actorID uint, comment string) (*models.Product, error) {
+	return s.transition(id, actorID, "in_review", comment, nil)
+}
+
+func (s *productWorkflowService) Approve(id, reviewerID uint, req models.ProductWorkflowRequest) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Status != "in_review" {
+		return nil, errors.New("only products in review can be approved")
+	}
+
+	submitter, err := s.submitter(id)
+	if err != nil {
+		return nil, err
+	}
+	if submitter != nil && *submitter == reviewerID {
+		return nil, errors.New("a product must be approved by someone other than its submitter")
+	}
+
+	// A future publish_at schedules the product instead of publishing it now
+	if req.PublishAt != nil && req.PublishAt.After(time.Now()) {
+		return s.transition(id, reviewerID, "scheduled", req.Comment, req.PublishAt)
+	}
+	return s.transition(id, reviewerID, "published", req.Comment, nil)
+}
+
+func (s *productWorkflowService) Reject(id, reviewerID uint, comment string) (*models.Product, error) {
+	if comment == "" {
+		return nil, errors.New("a comment is required when requesting changes")
+	}
+
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Status != "in_review" {
+		return nil, errors.New("only products in review can be rejected")
+	}
+
+	return s.transition(id, reviewerID, "draft", comment, nil)
+}
+
+func (s *productWorkflowService) Archive(id, actorID uint, comment string) (*models.Product, error) {
+	return s.transition(id, actorID, "archived", comment, nil)
+}
+
+func (s *productWorkflowService) RevertToDraft(id, actorID uint, comment string) (*models.Product, error) {
+	return s.transition(id, actorID, "draft", comment, nil)
+}
+
+func (s *productWorkflowService) GetPendingRevisions(page, limit int) ([]models.ProductRevision, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	return s.productRepo.GetPendingRevisions(page, limit)
+}
+
+// GetPendingRevision returns the product's changes waiting for review
+func (s *productWorkflowService) GetPendingRevision(id uint) (*models.ProductRevision, error) {
+	revision, err := s.productRepo.GetPendingRevision(id)
+	if err != nil {
+		return nil, errors.New("product has no changes waiting for review")
+	}
+	return revision, nil
+}
+
+// ApproveRevision puts a live product's pending changes on the storefront.
+// The reviewer must not be the one who made the changes.
+func (s *productWorkflowService) ApproveRevision(id, reviewerID uint, comment string) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	revision, err := s.GetPendingRevision(id)
+	if err != nil {
+		return nil, err
+	}
+	if revision.SubmittedBy == reviewerID {
+		return nil, errors.New("changes must be approved by someone other than their author")
+	}
+
+	content := revision.Content
+	if content.CategoryID != product.CategoryID {
+		if _, err := s.categoryRepo.GetByID(content.CategoryID); err != nil {
+			return nil, errors.New("category not found")
+		}
+	}
+
+	oldSlug := product.Slug
+	if content.Title != product.Title {
+		slug, err := uniqueSlug(s.slugRepo, "product", generateProductSlug(content.Title), product.ID)
+		if err != nil {
+			return nil, err
+		}
+		product.Slug = slug
+	}
+	applyProductContent(product, content)
+
+	now := time.Now()
+	revision.Status = "approved"
+	revision.Comment = comment
+	revision.ReviewedBy = &reviewerID
+	revision.ReviewedAt = &now
+
+	// Keep old links working after a rename
+	if err := s.productRepo.ApplyRevision(product, revision, oldSlug); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaigns.RecordProductPrice(product); err != nil {
+		log.Println("Failed to record product price:", err)
+	}
+
+	return product, nil
+}
+
+// RejectRevision discards a live product's pending changes; the product stays as it is
+func (s *productWorkflowService) RejectRevision(id, reviewerID uint, comment string) (*models.ProductRevision, error) {
+	if comment == "" {
+		return nil, errors.New("a comment is required when requesting changes")
+	}
+
+	revision, err := s.GetPendingRevision(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	revision.Status = "rejected"
+	revision.Comment = comment
+	revision.ReviewedBy = &reviewerID
+	revision.ReviewedAt = &now
+
+	if err := s.productRepo.SaveRevision(revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// submitter is who last sent the product to review, nil if unknown
+func (s *productWorkflowService) submitter(id uint) (*uint, error) {
+	logs, err := s.productRepo.GetStatusLogs(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range logs {
+		if entry.ToStatus == "in_review" {
+			return entry.ActorID, nil
+		}
+	}
+	return nil, nil
+}
+
+// transition moves a product to a new workflow state and records who did it and why
+func (s *productWorkflowService) transition(id, actorID uint, to, comment string, publishAt *time.Time) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	allowed := false
+	for _, next := range productTransitions[product.Status] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, errors.New("cannot move product from " + product.Status + " to " + to)
+	}
+
+	entry := &models.ProductStatusLog{
+		ProductID:  product.ID,
+		FromStatus: product.Status,
+		ToStatus:   to,
+		Comment:    comment,
+		ActorID:    &actorID,
+	}
+
+	product.Status = to
+	switch to {
+	case "scheduled":
+		product.PublishAt = publishAt
+	case "published":
+		now := time.Now()
+		product.PublishAt = nil
+		product.PublishedAt = &now
+	case "draft":
+		product.PublishAt = nil
+	}
+
+	if err := s.productRepo.UpdateStatus(product, entry); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"log"
+	"time"
+)
+
+// relationsPerProduct caps how many edges of each source are kept per product
+const relationsPerProduct = 20
+
+type RecommendationService interface {
+	GetRelatedProducts(productID uint, limit int) ([]models.Product, error)
+	GetUserRecommendations(userID uint, limit int) ([]models.Product, error)
+	Refresh() error
+}
+
+type recommendationService struct {
+	recommendationRepo repositories.RecommendationRepository
+	productRepo        repositories.ProductRepository
+	campaigns          SaleCampaignService
+}
+
+func NewRecommendationService(recommendationRepo repositories.RecommendationRepository, productRepo repositories.ProductRepository, campaigns SaleCampaignService, refreshInterval time.Duration) RecommendationService {
+	s := &recommendationService{
+		recommendationRepo: recommendationRepo,
+		productRepo:        productRepo,
+		campaigns:          campaigns,
+	}
+
+	// Rebuild the relation table on startup and then periodically
+	go s.run(refreshInterval)
+
+	return s
+}
+
+func (s *recommendationService) run(interval time.Duration) {
+	if err := s.Refresh(); err != nil {
+		log.Println("Failed to refresh product relations:", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.Refresh(); err != nil {
+			log.Println("Failed to refresh product relations:", err)
+		}
+	}
+}
+
+func (s *recommendationService) Refresh() error {
+	return s.recommendationRepo.RebuildRelations(relationsPerProduct)
+}
+
+func (s *recommendationService) GetRelatedProducts(productID uint, limit int) ([]models.Product, error) {
+	if limit < 1 || limit > 20 {
+		limit = 8
+	}
+
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil || product.Status != "published" {
+		return nil, errors.New("product not found")
+	}
+
+	related, err := s.recommendationRepo.GetRelated(productID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Products added since the last refresh have no edges yet; fall back to their category
+	if len(related) == 0 {
+		filter := models.ProductFilter{CategoryID: &product.CategoryID, Sort: "popular"}
+		candidates, _, err := s.productRepo.GetAll(1, limit+1, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range candidates {
+			if p.ID != productID && len(related) < limit {
+				related = append(related, p)
+			}
+		}
+	}
+
+	if err := s.campaigns.ApplySalePrices(productPointers(related)...); err != nil {
+		return nil, err
+	}
+	return related, nil
+}
+
+func (s *recommendationService) GetUserRecommendations(userID uint, limit int) ([]models.Product, error) {
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	products, err := s.recommendationRepo.GetForUser(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// New users without orders or wishlist get best sellers instead
+	if len(products) < limit {
+		popular, err := s.recommendationRepo.GetPopularForUser(userID, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[uint]bool, len(products))
+		for _, p := range products {
+			seen[p.ID] = true
+		}
+		for _, p := range popular {
+			if !seen[p.ID] && len(products) < limit {
+				products = append(products, p)
+			}
+		}
+	}
+
+	if err := s.campaigns.ApplySalePrices(productPointers(products)...); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
@@ -13,7 +13,7 @@ type ReviewService interface {
 	UpdateReview(reviewID, userID uint, rating int, comment string) error
 	DeleteReview(reviewID, userID uint) error
 	GetAverageRating(productID uint) (float64, error)
-	AdminDeleteReview(reviewID uint) error
+	AdminDeleteReview(reviewID, adminID uint) error
 }
 
 type reviewService struct {
@@ -44,7 +44,7 @@ func (s *reviewService) CreateReview(userID, productID uint, rating int, comment
 		return errors.New("product tidak ditemukan")
 	}
 
-	hasPurchased, err := s.orderRepo.HasUserPurchasedProduct(userID, productID)
+	hasPurchased, err := s.orderRepo.HasPaidAccess(userID, productID)
 	if err != nil {
 		return err
 	}
@@ -110,18 +110,18 @@ func (s *reviewService) DeleteReview(reviewID, userID uint) error {
 		return errors.New("anda tidak memiliki akses untuk menghapus review ini")
 	}
 
-	return s.reviewRepo.Delete(reviewID)
+	return s.reviewRepo.Delete(reviewID, userID)
 }
 
 func (s *reviewService) GetAverageRating(productID uint) (float64, error) {
 	return s.reviewRepo.GetAverageRating(productID)
 }
 
-func (s *reviewService) AdminDeleteReview(reviewID uint) error {
+func (s *reviewService) AdminDeleteReview(reviewID, adminID uint) error {
 	_, err := s.reviewRepo.GetByID(reviewID)
 	if err != nil {
 		return errors.New("review tidak ditemukan")
 	}
 
-	return s.reviewRepo.Delete(reviewID)
+	return s.reviewRepo.Delete(reviewID, adminID)
 }
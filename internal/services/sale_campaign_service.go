@@ -0,0 +1,397 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"log"
+	"sync"
+	"time"
+)
+
+// lowestPriceWindow is how far back the "lowest price" reference looks
+const lowestPriceWindow = 30 * 24 * time.Hour
+
+type SaleCampaignService interface {
+	CreateCampaign(req models.SaleCampaignRequest, createdBy uint) (*models.SaleCampaign, error)
+	GetCampaigns(page, limit int, state string) ([]models.SaleCampaign, int64, error)
+	GetCampaign(id uint) (*models.SaleCampaign, error)
+	GetActiveCampaigns() ([]models.SaleCampaign, error)
+	UpdateCampaign(id uint, req models.SaleCampaignRequest) (*models.SaleCampaign, error)
+	DeleteCampaign(id uint) error
+	ApplySalePrices(products ...*models.Product) error
+	ApplyLowestPrice(product *models.Product) error
+	GetPriceHistory(productID uint) (*models.ProductPriceHistoryResponse, error)
+	RecordProductPrice(product *models.Product) error
+	RecordPriceChanges() error
+}
+
+type saleCampaignService struct {
+	campaignRepo repositories.SaleCampaignRepository
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+
+	// mu keeps concurrent recordings from appending the same change twice
+	mu sync.Mutex
+}
+
+func NewSaleCampaignService(campaignRepo repositories.SaleCampaignRepository, productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository, historyInterval time.Duration) SaleCampaignService {
+	s := &saleCampaignService{
+		campaignRepo: campaignRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+	}
+
+	// Campaigns start and end without any write, so the price history is
+	// brought up to date on startup and then periodically
+	go s.run(historyInterval)
+
+	return s
+}
+
+func (s *saleCampaignService) run(interval time.Duration) {
+	s.recordPriceChanges()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.recordPriceChanges()
+	}
+}
+
+// recordPriceChanges records prices in the background after a campaign edit,
+// so changes taking effect immediately don't wait for the next tick
+func (s *saleCampaignService) recordPriceChanges() {
+	if err := s.RecordPriceChanges(); err != nil {
+		log.Println("Failed to record price history:", err)
+	}
+}
+
+func (s *saleCampaignService) CreateCampaign(req models.SaleCampaignRequest, createdBy uint) (*models.SaleCampaign, error) {
+	campaign := &models.SaleCampaign{
+		IsActive:  true,
+		CreatedBy: createdBy,
+	}
+	if err := s.applyRequest(campaign, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.Create(campaign); err != nil {
+		return nil, err
+	}
+	go s.recordPriceChanges()
+
+	return campaign, nil
+}
+
+func (s *saleCampaignService) GetCampaigns(page, limit int, state string) ([]models.SaleCampaign, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.campaignRepo.GetAll(page, limit, state, time.Now())
+}
+
+func (s *saleCampaignService) GetCampaign(id uint) (*models.SaleCampaign, error) {
+	campaign, err := s.campaignRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("campaign not found")
+	}
+	return campaign, nil
+}
+
+func (s *saleCampaignService) GetActiveCampaigns() ([]models.SaleCampaign, error) {
+	campaigns, _, err := s.campaignRepo.GetAll(1, 100, "active", time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range campaigns {
+		campaigns[i].Products = publishedProducts(campaigns[i].Products)
+	}
+	return campaigns, nil
+}
+
+func (s *saleCampaignService) UpdateCampaign(id uint, req models.SaleCampaignRequest) (*models.SaleCampaign, error) {
+	campaign, err := s.campaignRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("campaign not found")
+	}
+
+	if err := s.applyRequest(campaign, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.Update(campaign); err != nil {
+		return nil, err
+	}
+	go s.recordPriceChanges()
+
+	return campaign, nil
+}
+
+func (s *saleCampaignService) DeleteCampaign(id uint) error {
+	if _, err := s.campaignRepo.GetByID(id); err != nil {
+		return errors.New("campaign not found")
+	}
+	if err := s.campaignRepo.Delete(id); err != nil {
+		return err
+	}
+
+	go s.recordPriceChanges()
+
+	return nil
+}
+
+func (s *saleCampaignService) applyRequest(campaign *models.SaleCampaign, req models.SaleCampaignRequest) error {
+	if !req.EndsAt.After(req.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if req.DiscountType == "percentage" && req.DiscountValue > 100 {
+		return errors.New("percentage discount cannot exceed 100")
+	}
+
+	// A percentage applies to any currency; a fixed amount only to products priced in its own
+	currency := ""
+	if req.DiscountType == "fixed" {
+		currency = models.BaseCurrency
+		if req.Currency != "" {
+			var err error
+			if currency, err = normalizeCurrency(req.Currency); err != nil {
+				return err
+			}
+		}
+	}
+
+	campaign.Name = req.Name
+	campaign.Description = req.Description
+	campaign.DiscountType = req.DiscountType
+	campaign.DiscountValue = req.DiscountValue
+	campaign.Currency = currency
+	campaign.Scope = req.Scope
+	campaign.Priority = req.Priority
+	campaign.StartsAt = req.StartsAt
+	campaign.EndsAt = req.EndsAt
+	if req.IsActive != nil {
+		campaign.IsActive = *req.IsActive
+	}
+
+	products, categories, err := resolveScopeTargets(s.productRepo, s.categoryRepo, "campaigns", req.Scope, req.ProductIDs, req.CategoryIDs)
+	if err != nil {
+		return err
+	}
+	campaign.Products = products
+	campaign.Categories = categories
+
+	return nil
+}
+
+// ApplySalePrices fills in the sale fields of products from the campaigns running now
+func (s *saleCampaignService) ApplySalePrices(products ...*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	campaigns, err := s.campaignRepo.GetActive(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if product != nil {
+			applyCampaigns(campaigns, product)
+		}
+	}
+	return nil
+}
+
+// ApplyLowestPrice sets the lowest price in the 30 days before the current price took effect
+func (s *saleCampaignService) ApplyLowestPrice(product *models.Product) error {
+	end := time.Now()
+	if latest, err := s.campaignRepo.GetLatestPrice(product.ID); err == nil && samePrice(latest, product, unitPrice(product, nil)) {
+		end = latest.RecordedAt
+	}
+
+	lowest, err := s.campaignRepo.GetLowestPrice(product.ID, product.Currency, end.Add(-lowestPriceWindow), end)
+	if err != nil {
+		return err
+	}
+	product.LowestPrice30d = lowest
+	return nil
+}
+
+func (s *saleCampaignService) GetPriceHistory(productID uint) (*models.ProductPriceHistoryResponse, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil || product.Status != "published" {
+		return nil, errors.New("product not found")
+	}
+
+	if err := s.ApplySalePrices(product); err != nil {
+		return nil, err
+	}
+	if err := s.ApplyLowestPrice(product); err != nil {
+		return nil, err
+	}
+
+	history, err := s.campaignRepo.GetPriceHistory(productID, time.Now().Add(-lowestPriceWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProductPriceHistoryResponse{
+		Currency:          product.Currency,
+		CurrentPrice:      unitPrice(product, nil),
+		LowestPrice30Days: product.LowestPrice30d,
+		History:           history,
+	}, nil
+}
+
+// RecordProductPrice appends a history entry when the product's current price differs from the last one
+func (s *saleCampaignService) RecordProductPrice(product *models.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ApplySalePrices(product); err != nil {
+		return err
+	}
+
+	price := unitPrice(product, nil)
+	if latest, err := s.campaignRepo.GetLatestPrice(product.ID); err == nil && samePrice(latest, product, price) {
+		return nil
+	}
+
+	return s.campaignRepo.RecordPrices([]models.ProductPriceHistory{priceEntry(product, price, time.Now())})
+}
+
+// RecordPriceChanges compares every product's current price with its last
+// recorded one and appends entries for those that changed
+func (s *saleCampaignService) RecordPriceChanges() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	campaigns, err := s.campaignRepo.GetActive(now)
+	if err != nil {
+		return err
+	}
+	latest, err := s.campaignRepo.GetLatestPrices()
+	if err != nil {
+		return err
+	}
+
+	return s.productRepo.EachInBatches(500, func(products []models.Product) error {
+		var entries []models.ProductPriceHistory
+		for i := range products {
+			product := &products[i]
+			applyCampaigns(campaigns, product)
+
+			price := unitPrice(product, nil)
+			if last, ok := latest[product.ID]; ok && samePrice(&last, product, price) {
+				continue
+			}
+			entries = append(entries, priceEntry(product, price, now))
+		}
+		return s.campaignRepo.RecordPrices(entries)
+	})
+}
+
+// samePrice reports whether a history entry already records the product's current price
+func samePrice(entry *models.ProductPriceHistory, product *models.Product, price int64) bool {
+	return entry.Price == price && entry.Currency == product.Currency
+}
+
+func priceEntry(product *models.Product, price int64, at time.Time) models.ProductPriceHistory {
+	entry := models.ProductPriceHistory{
+		ProductID:    product.ID,
+		Currency:     product.Currency,
+		Price:        price,
+		RegularPrice: product.Price,
+		RecordedAt:   at,
+	}
+	if product.SalePrice != nil && product.Sale != nil {
+		entry.SaleCampaignID = &product.Sale.CampaignID
+	}
+	return entry
+}
+
+// applyCampaigns resolves overlapping campaigns deterministically: the highest
+// priority wins, then the lowest resulting price, then the oldest campaign.
+// Campaigns never stack. The sale price is only set when it beats the product's
+// own discount price.
+func applyCampaigns(campaigns []models.SaleCampaign, product *models.Product) {
+	product.Sale = nil
+	product.SalePrice = nil
+
+	var winner *models.SaleCampaign
+	var winnerPrice int64
+	for i := range campaigns {
+		c := &campaigns[i]
+		if !campaignApplies(c, product) {
+			continue
+		}
+		price := discountedPrice(c.DiscountType, c.DiscountValue, product.Price)
+		if winner == nil ||
+			c.Priority > winner.Priority ||
+			(c.Priority == winner.Priority && price < winnerPrice) ||
+			(c.Priority == winner.Priority && price == winnerPrice && c.ID < winner.ID) {
+			winner = c
+			winnerPrice = price
+		}
+	}
+	if winner == nil {
+		return
+	}
+
+	product.Sale = &models.ProductSale{
+		CampaignID:    winner.ID,
+		Name:          winner.Name,
+		DiscountType:  winner.DiscountType,
+		DiscountValue: winner.DiscountValue,
+		EndsAt:        winner.EndsAt,
+	}
+	if winnerPrice < regularPrice(product) {
+		product.SalePrice = &winnerPrice
+	}
+}
+
+// campaignApplies reports whether the campaign covers the product. The
+// categories of category campaigns come from GetActive with their
+// subcategories included.
+func campaignApplies(campaign *models.SaleCampaign, product *models.Product) bool {
+	if campaign.DiscountType == "fixed" && campaign.Currency != product.Currency {
+		return false
+	}
+
+	switch campaign.Scope {
+	case "store":
+		return true
+	case "category":
+		for _, c := range campaign.Categories {
+			if c.ID == product.CategoryID {
+				return true
+			}
+		}
+	case "product":
+		for _, p := range campaign.Products {
+			if p.ID == product.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// discountedPrice applies a campaign discount to a price in minor units,
+// rounding percentages half up to the nearest minor unit
+func discountedPrice(discountType string, value, base int64) int64 {
+	if discountType == "percentage" {
+		return (base*(100-value) + 50) / 100
+	}
+	if base <= value {
+		return 0
+	}
+	return base - value
+}
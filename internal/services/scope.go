@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+)
+
+// resolveScopeTargets loads the products or categories a product or category
+// scoped coupon or sale campaign targets, skipping repeated ids. kind names
+// the rule in error messages, e.g. "coupons".
+func resolveScopeTargets(
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	kind, scope string,
+	productIDs, categoryIDs []uint,
+) ([]models.Product, []models.Category, error) {
+	products := []models.Product{}
+	categories := []models.Category{}
+
+	switch scope {
+	case "product":
+		if len(productIDs) == 0 {
+			return nil, nil, errors.New("product_ids is required for product " + kind)
+		}
+		seen := make(map[uint]bool)
+		for _, id := range productIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			product, err := productRepo.GetByID(id)
+			if err != nil {
+				return nil, nil, errors.New("product not found")
+			}
+			products = append(products, *product)
+		}
+	case "category":
+		if len(categoryIDs) == 0 {
+			return nil, nil, errors.New("category_ids is required for category " + kind)
+		}
+		seen := make(map[uint]bool)
+		for _, id := range categoryIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			category, err := categoryRepo.GetByID(id)
+			if err != nil {
+				return nil, nil, errors.New("category not found")
+			}
+			categories = append(categories, *category)
+		}
+	}
+
+	return products, categories, nil
+}
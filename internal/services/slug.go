@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"gin-quickstart/internal/repositories"
+)
+
+// maxSlugSuffix bounds the search for a free "-N" suffix
+const maxSlugSuffix = 1000
+
+// uniqueSlug returns base, or base with the first free "-2", "-3", ... suffix when
+// another entity of the same type uses or used that slug.
+func uniqueSlug(slugRepo repositories.SlugRedirectRepository, entityType, base string, excludeID uint) (string, error) {
+	if base == "" {
+		base = entityType
+	}
+
+	for n := 1; n <= maxSlugSuffix; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		taken, err := slugRepo.IsTaken(entityType, candidate, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no free slug found for %s", base)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeSlugRepo reports the slugs in taken as used by another entity
+type fakeSlugRepo struct {
+	taken map[string]bool
+	err   error
+}
+
+func (r *fakeSlugRepo) Resolve(entityType, slug string) (uint, error) {
+	return 0, errors.New("not found")
+}
+
+func (r *fakeSlugRepo) IsTaken(entityType, slug string, excludeID uint) (bool, error) {
+	return r.taken[slug], r.err
+}
+
+// takenUpTo marks base and base-2 ... base-n as taken
+func takenUpTo(base string, n int) map[string]bool {
+	taken := map[string]bool{base: true}
+	for i := 2; i <= n; i++ {
+		taken[fmt.Sprintf("%s-%d", base, i)] = true
+	}
+	return taken
+}
+
+func TestUniqueSlug(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		taken   map[string]bool
+		repoErr error
+		want    string
+		wantErr bool
+	}{
+		{name: "free base", base: "laravel-starter", want: "laravel-starter"},
+		{name: "base taken", base: "laravel-starter", taken: takenUpTo("laravel-starter", 1), want: "laravel-starter-2"},
+		{name: "first free suffix", base: "kit", taken: takenUpTo("kit", 4), want: "kit-5"},
+		{name: "gap is reused", base: "kit", taken: map[string]bool{"kit": true, "kit-3": true}, want: "kit-2"},
+		{name: "empty base uses entity type", base: "", want: "product"},
+		{name: "last suffix is tried", base: "kit", taken: takenUpTo("kit", maxSlugSuffix-1), want: fmt.Sprintf("kit-%d", maxSlugSuffix)},
+		{name: "every suffix taken", base: "kit", taken: takenUpTo("kit", maxSlugSuffix), wantErr: true},
+		{name: "repository error", base: "kit", repoErr: errors.New("db down"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSlugRepo{taken: tt.taken, err: tt.repoErr}
+			got, err := uniqueSlug(repo, "product", tt.base, 0)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("uniqueSlug() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("uniqueSlug() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("uniqueSlug() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
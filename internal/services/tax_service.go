@@ -0,0 +1,310 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TaxService interface {
+	CreateRule(req models.TaxRuleRequest, createdBy uint) (*models.TaxRule, error)
+	GetRules(country string) ([]models.TaxRule, error)
+	GetRule(id uint) (*models.TaxRule, error)
+	UpdateRule(id uint, req models.TaxRuleRequest) (*models.TaxRule, error)
+	DeleteRule(id uint) error
+	ApplyTax(order *models.Order) error
+	GetTaxReport(startDate, endDate time.Time) ([]models.TaxReportLine, error)
+}
+
+type taxService struct {
+	taxRepo        repositories.TaxRepository
+	userRepo       repositories.UserRepository
+	defaultCountry string
+}
+
+func NewTaxService(taxRepo repositories.TaxRepository, userRepo repositories.UserRepository, defaultCountry string) TaxService {
+	return &taxService{
+		taxRepo:        taxRepo,
+		userRepo:       userRepo,
+		defaultCountry: strings.ToUpper(defaultCountry),
+	}
+}
+
+func (s *taxService) CreateRule(req models.TaxRuleRequest, createdBy uint) (*models.TaxRule, error) {
+	rule := &models.TaxRule{
+		IsActive:  true,
+		CreatedBy: createdBy,
+	}
+	if err := s.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.taxRepo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *taxService) GetRules(country string) ([]models.TaxRule, error) {
+	return s.taxRepo.GetRules(strings.ToUpper(strings.TrimSpace(country)))
+}
+
+func (s *taxService) GetRule(id uint) (*models.TaxRule, error) {
+	rule, err := s.taxRepo.GetRuleByID(id)
+	if err != nil {
+		return nil, errors.New("tax rule not found")
+	}
+	return rule, nil
+}
+
+func (s *taxService) UpdateRule(id uint, req models.TaxRuleRequest) (*models.TaxRule, error) {
+	rule, err := s.taxRepo.GetRuleByID(id)
+	if err != nil {
+		return nil, errors.New("tax rule not found")
+	}
+
+	if err := s.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.taxRepo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *taxService) DeleteRule(id uint) error {
+	if _, err := s.taxRepo.GetRuleByID(id); err != nil {
+		return errors.New("tax rule not found")
+	}
+	return s.taxRepo.DeleteRule(id)
+}
+
+// ApplyTax taxes a priced and discounted order where its buyer is billed,
+// the default country for buyers who gave none. Exclusive tax is added to
+// the final amount, inclusive tax is taken out of the prices. Business buyers
+// whose tax ID an admin verified for the rule's country are not charged under
+// reverse charge rules and pay net prices.
+func (s *taxService) ApplyTax(order *models.Order) error {
+	country, region := s.defaultCountry, ""
+	taxIDCountry := ""
+	if order.UserID != 0 {
+		user, err := s.userRepo.FindByID(order.UserID)
+		if err != nil {
+			return err
+		}
+		if user.Country != "" {
+			country, region = user.Country, user.Region
+		}
+		order.BusinessName = user.BusinessName
+		order.BuyerTaxID = user.TaxID
+		if user.TaxID != "" && user.TaxIDVerified {
+			taxIDCountry = user.Country
+		}
+	}
+	if country == "" {
+		return nil
+	}
+	order.TaxCountry = country
+	order.TaxRegion = region
+
+	rules, err := s.taxRepo.GetActiveRules(country)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	var productIDs []uint
+	for _, item := range order.Items {
+		if item.ProductID != nil {
+			productIDs = append(productIDs, *item.ProductID)
+		}
+	}
+	types, err := s.taxRepo.ProductTypes(productIDs)
+	if err != nil {
+		return err
+	}
+
+	lines := make(map[uint]*models.OrderTax)
+	var ruleIDs []uint
+	for i := range order.Items {
+		item := &order.Items[i]
+		productType := "bundle"
+		if item.ProductID != nil {
+			productType = types[*item.ProductID]
+		}
+
+		rule := matchTaxRule(rules, region, productType)
+		if rule == nil {
+			continue
+		}
+
+		base := item.Amount - item.DiscountAmount
+		tax := int64(math.Round(float64(base) * rule.Rate / 100))
+		taxable := base
+		if rule.Inclusive {
+			tax = base - int64(math.Round(float64(base)*100/(100+rule.Rate)))
+			taxable = base - tax
+		}
+		reverseCharge := rule.ReverseCharge && taxIDCountry != "" && strings.EqualFold(taxIDCountry, rule.Country)
+
+		line, ok := lines[rule.ID]
+		if !ok {
+			ruleID := rule.ID
+			line = &models.OrderTax{
+				TaxRuleID:     &ruleID,
+				Name:          rule.Name,
+				Country:       rule.Country,
+				Region:        rule.Region,
+				Rate:          rule.Rate,
+				Inclusive:     rule.Inclusive,
+				ReverseCharge: reverseCharge,
+			}
+			lines[rule.ID] = line
+			ruleIDs = append(ruleIDs, rule.ID)
+		}
+		line.TaxableAmount += taxable
+
+		if reverseCharge {
+			if rule.Inclusive {
+				order.FinalAmount -= tax
+			}
+			continue
+		}
+
+		item.TaxRate = rule.Rate
+		item.TaxAmount = tax
+		line.TaxAmount += tax
+		order.TaxAmount += tax
+		if !rule.Inclusive {
+			order.FinalAmount += tax
+		}
+	}
+
+	order.Taxes = make([]models.OrderTax, 0, len(ruleIDs))
+	for _, id := range ruleIDs {
+		order.Taxes = append(order.Taxes, *lines[id])
+	}
+	return nil
+}
+
+// matchTaxRule picks the most specific rule for an order line: a matching
+// region outranks a matching product type, which outranks the country-wide rule
+func matchTaxRule(rules []models.TaxRule, region, productType string) *models.TaxRule {
+	var best *models.TaxRule
+	bestScore := -1
+	for i := range rules {
+		rule := &rules[i]
+		score := 0
+		if rule.Region != "" {
+			if !strings.EqualFold(rule.Region, region) {
+				continue
+			}
+			score += 2
+		}
+		if rule.ProductType != "" {
+			if rule.ProductType != productType {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// GetTaxReport totals the tax of paid orders placed in the period per rule,
+// converted to the base currency at each order's locked rate
+func (s *taxService) GetTaxReport(startDate, endDate time.Time) ([]models.TaxReportLine, error) {
+	if !endDate.After(startDate) {
+		return nil, errors.New("end_date must be after start_date")
+	}
+
+	taxes, err := s.taxRepo.GetPaidOrderTaxesBetween(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		country, region, name string
+		rate                  float64
+		inclusive, reverse    bool
+	}
+	byKey := make(map[key]*models.TaxReportLine)
+	orders := make(map[key]map[uint]bool)
+	for _, tax := range taxes {
+		if tax.Order == nil {
+			continue
+		}
+		k := key{tax.Country, tax.Region, tax.Name, tax.Rate, tax.Inclusive, tax.ReverseCharge}
+		line, ok := byKey[k]
+		if !ok {
+			line = &models.TaxReportLine{
+				Country:       tax.Country,
+				Region:        tax.Region,
+				Name:          tax.Name,
+				Rate:          tax.Rate,
+				Inclusive:     tax.Inclusive,
+				ReverseCharge: tax.ReverseCharge,
+			}
+			byKey[k] = line
+			orders[k] = make(map[uint]bool)
+		}
+		orders[k][tax.OrderID] = true
+		line.TaxableAmount += baseAmount(tax.Order, tax.TaxableAmount)
+		line.TaxAmount += baseAmount(tax.Order, tax.TaxAmount)
+	}
+
+	report := make([]models.TaxReportLine, 0, len(byKey))
+	for k, line := range byKey {
+		line.Orders = int64(len(orders[k]))
+		report = append(report, *line)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Country != b.Country {
+			return a.Country < b.Country
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Rate != b.Rate {
+			return a.Rate < b.Rate
+		}
+		return !a.ReverseCharge && b.ReverseCharge
+	})
+	return report, nil
+}
+
+func (s *taxService) applyRequest(rule *models.TaxRule, req models.TaxRuleRequest) error {
+	rule.Name = strings.TrimSpace(req.Name)
+	if rule.Name == "" {
+		return errors.New("name is required")
+	}
+	rule.Country = strings.ToUpper(req.Country)
+	rule.Region = strings.TrimSpace(req.Region)
+	rule.ProductType = req.ProductType
+	rule.Rate = *req.Rate
+	rule.Inclusive = req.Inclusive
+	rule.ReverseCharge = req.ReverseCharge
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	taken, err := s.taxRepo.RuleScopeTaken(rule)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("a tax rule for this country, region and product type already exists")
+	}
+	return nil
+}
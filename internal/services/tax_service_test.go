@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"testing"
+)
+
+// fakeTaxRepo serves the active rules of every country from rules. Methods
+// ApplyTax does not call panic through the nil embedded interface.
+type fakeTaxRepo struct {
+	repositories.TaxRepository
+	rules []models.TaxRule
+	types map[uint]string
+}
+
+func (r *fakeTaxRepo) GetActiveRules(country string) ([]models.TaxRule, error) {
+	return r.rules, nil
+}
+
+func (r *fakeTaxRepo) ProductTypes(productIDs []uint) (map[uint]string, error) {
+	return r.types, nil
+}
+
+type fakeUserRepo struct {
+	repositories.UserRepository
+	users map[uint]*models.User
+}
+
+func (r *fakeUserRepo) FindByID(id uint) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return user, nil
+}
+
+func TestMatchTaxRule(t *testing.T) {
+	rules := []models.TaxRule{
+		{ID: 1, Country: "ID"},
+		{ID: 2, Country: "ID", ProductType: "pdf"},
+		{ID: 3, Country: "ID", Region: "Bali"},
+		{ID: 4, Country: "ID", Region: "Bali", ProductType: "pdf"},
+	}
+
+	tests := []struct {
+		name        string
+		rules       []models.TaxRule
+		region      string
+		productType string
+		want        uint
+	}{
+		{name: "country wide", rules: rules, region: "Jawa Barat", productType: "template", want: 1},
+		{name: "product type", rules: rules, region: "Jawa Barat", productType: "pdf", want: 2},
+		{name: "region outranks product type", rules: rules[:3], region: "Bali", productType: "pdf", want: 3},
+		{name: "region and product type", rules: rules, region: "Bali", productType: "pdf", want: 4},
+		{name: "region is case insensitive", rules: rules, region: "bali", productType: "template", want: 3},
+		{name: "bundle lines", rules: rules, productType: "bundle", want: 1},
+		{name: "no matching rule", rules: rules[3:], region: "Bali", productType: "template", want: 0},
+		{name: "no rules", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uint
+			if rule := matchTaxRule(tt.rules, tt.region, tt.productType); rule != nil {
+				got = rule.ID
+			}
+			if got != tt.want {
+				t.Errorf("matchTaxRule() = rule %d, want rule %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTax(t *testing.T) {
+	productID := uint(10)
+	vat := models.TaxRule{ID: 1, Name: "VAT", Country: "DE", Rate: 19, IsActive: true}
+
+	tests := []struct {
+		name          string
+		rule          models.TaxRule
+		user          models.User
+		wantTax       int64
+		wantFinal     int64
+		wantItemTax   int64
+		wantTaxable   int64
+		reverseCharge bool
+	}{
+		{
+			name:    "exclusive",
+			rule:    vat,
+			user:    models.User{Country: "DE"},
+			wantTax: 1900, wantFinal: 11900, wantItemTax: 1900, wantTaxable: 10000,
+		},
+		{
+			name:    "inclusive",
+			rule:    withInclusive(vat),
+			user:    models.User{Country: "DE"},
+			wantTax: 1597, wantFinal: 10000, wantItemTax: 1597, wantTaxable: 8403,
+		},
+		{
+			name:          "reverse charge with verified tax ID",
+			rule:          withReverseCharge(vat),
+			user:          models.User{Country: "DE", TaxID: "DE123456789", TaxIDVerified: true},
+			wantFinal:     10000,
+			wantTaxable:   10000,
+			reverseCharge: true,
+		},
+		{
+			name:          "reverse charge on inclusive prices pays net",
+			rule:          withInclusive(withReverseCharge(vat)),
+			user:          models.User{Country: "DE", TaxID: "DE123456789", TaxIDVerified: true},
+			wantFinal:     8403,
+			wantTaxable:   8403,
+			reverseCharge: true,
+		},
+		{
+			name:    "unverified tax ID is charged",
+			rule:    withReverseCharge(vat),
+			user:    models.User{Country: "DE", TaxID: "DE123456789"},
+			wantTax: 1900, wantFinal: 11900, wantItemTax: 1900, wantTaxable: 10000,
+		},
+		{
+			name:    "tax ID from another country is charged",
+			rule:    withReverseCharge(vat),
+			user:    models.User{Country: "FR", TaxID: "FR12345678901", TaxIDVerified: true},
+			wantTax: 1900, wantFinal: 11900, wantItemTax: 1900, wantTaxable: 10000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			taxRepo := &fakeTaxRepo{rules: []models.TaxRule{tt.rule}, types: map[uint]string{productID: "source_code"}}
+			userRepo := &fakeUserRepo{users: map[uint]*models.User{1: &user}}
+			service := NewTaxService(taxRepo, userRepo, "ID")
+
+			order := &models.Order{
+				UserID:      1,
+				FinalAmount: 10000,
+				Items:       []models.OrderItem{{ProductID: &productID, Amount: 10000}},
+			}
+			if err := service.ApplyTax(order); err != nil {
+				t.Fatalf("ApplyTax() error = %v", err)
+			}
+
+			if order.TaxAmount != tt.wantTax {
+				t.Errorf("TaxAmount = %d, want %d", order.TaxAmount, tt.wantTax)
+			}
+			if order.FinalAmount != tt.wantFinal {
+				t.Errorf("FinalAmount = %d, want %d", order.FinalAmount, tt.wantFinal)
+			}
+			if order.Items[0].TaxAmount != tt.wantItemTax {
+				t.Errorf("item TaxAmount = %d, want %d", order.Items[0].TaxAmount, tt.wantItemTax)
+			}
+			if len(order.Taxes) != 1 {
+				t.Fatalf("len(Taxes) = %d, want 1", len(order.Taxes))
+			}
+			if order.Taxes[0].TaxableAmount != tt.wantTaxable {
+				t.Errorf("TaxableAmount = %d, want %d", order.Taxes[0].TaxableAmount, tt.wantTaxable)
+			}
+			if order.Taxes[0].ReverseCharge != tt.reverseCharge {
+				t.Errorf("ReverseCharge = %v, want %v", order.Taxes[0].ReverseCharge, tt.reverseCharge)
+			}
+		})
+	}
+}
+
+func TestApplyTaxDefaultCountry(t *testing.T) {
+	productID := uint(10)
+	taxRepo := &fakeTaxRepo{
+		rules: []models.TaxRule{{ID: 1, Name: "PPN", Country: "ID", Rate: 11, IsActive: true}},
+		types: map[uint]string{productID: "pdf"},
+	}
+	userRepo := &fakeUserRepo{users: map[uint]*models.User{1: {}}}
+
+	order := &models.Order{
+		UserID:      1,
+		FinalAmount: 10000,
+		Items:       []models.OrderItem{{ProductID: &productID, Amount: 10000}},
+	}
+	if err := NewTaxService(taxRepo, userRepo, "id").ApplyTax(order); err != nil {
+		t.Fatalf("ApplyTax() error = %v", err)
+	}
+	if order.TaxCountry != "ID" {
+		t.Errorf("TaxCountry = %q, want %q", order.TaxCountry, "ID")
+	}
+	if order.TaxAmount != 1100 || order.FinalAmount != 11100 {
+		t.Errorf("TaxAmount, FinalAmount = %d, %d, want 1100, 11100", order.TaxAmount, order.FinalAmount)
+	}
+}
+
+func withInclusive(rule models.TaxRule) models.TaxRule {
+	rule.Inclusive = true
+	return rule
+}
+
+func withReverseCharge(rule models.TaxRule) models.TaxRule {
+	rule.ReverseCharge = true
+	return rule
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"sort"
+	"strings"
+)
+
+type TranslationService interface {
+	Locales() (string, []string)
+	GetProductTranslations(productID uint) ([]models.ProductTranslation, error)
+	SetProductTranslation(productID uint, locale string, req models.ProductTranslationRequest) (*models.ProductTranslation, error)
+	DeleteProductTranslation(productID uint, locale string) error
+	GetCategoryTranslations(categoryID uint) ([]models.CategoryTranslation, error)
+	SetCategoryTranslation(categoryID uint, locale string, req models.CategoryTranslationRequest) (*models.CategoryTranslation, error)
+	DeleteCategoryTranslation(categoryID uint, locale string) error
+	LocalizeProducts(locale string, products ...*models.Product) error
+	LocalizeCategories(locale string, categories ...*models.Category) error
+}
+
+type translationService struct {
+	translationRepo repositories.TranslationRepository
+	productRepo     repositories.ProductRepository
+	categoryRepo    repositories.CategoryRepository
+	defaultLocale   string
+	supported       []string
+}
+
+func NewTranslationService(
+	translationRepo repositories.TranslationRepository,
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	defaultLocale string,
+	supported []string,
+) TranslationService {
+	return &translationService{
+		translationRepo: translationRepo,
+		productRepo:     productRepo,
+		categoryRepo:    categoryRepo,
+		defaultLocale:   defaultLocale,
+		supported:       supported,
+	}
+}
+
+func (s *translationService) Locales() (string, []string) {
+	return s.defaultLocale, s.supported
+}
+
+// translatableLocale checks that content can be stored for the locale. The
+// default locale's content lives on the product or category itself.
+func (s *translationService) translatableLocale(locale string) (string, error) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == s.defaultLocale {
+		return "", errors.New("content in the default locale is edited on the item itself")
+	}
+	for _, supported := range s.supported {
+		if supported == locale {
+			return locale, nil
+		}
+	}
+	return "", errors.New("unsupported locale, expected one of: " + strings.Join(s.supported, ", "))
+}
+
+// chain returns the translated locales to try for a locale, most specific
+// first. The default locale ends every chain and needs no lookup.
+func (s *translationService) chain(locale string) []string {
+	var locales []string
+	for locale != "" && locale != s.defaultLocale {
+		locales = append(locales, locale)
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return locales
+}
+
+func (s *translationService) GetProductTranslations(productID uint) ([]models.ProductTranslation, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+	return s.translationRepo.GetProductTranslations(productID)
+}
+
+func (s *translationService) SetProductTranslation(productID uint, locale string, req models.ProductTranslationRequest) (*models.ProductTranslation, error) {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	translation := &models.ProductTranslation{
+		ProductID:   productID,
+		Locale:      locale,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+	}
+	if translation.Title == "" && translation.Description == "" {
+		return nil, errors.New("title or description is required")
+	}
+
+	if err := s.translationRepo.SaveProductTranslation(translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (s *translationService) DeleteProductTranslation(productID uint, locale string) error {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.translationRepo.DeleteProductTranslation(productID, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("translation not found")
+	}
+	return nil
+}
+
+func (s *translationService) GetCategoryTranslations(categoryID uint) ([]models.CategoryTranslation, error) {
+	if _, err := s.categoryRepo.GetByID(categoryID); err != nil {
+		return nil, errors.New("category not found")
+	}
+	return s.translationRepo.GetCategoryTranslations(categoryID)
+}
+
+func (s *translationService) SetCategoryTranslation(categoryID uint, locale string, req models.CategoryTranslationRequest) (*models.CategoryTranslation, error) {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.categoryRepo.GetByID(categoryID); err != nil {
+		return nil, errors.New("category not found")
+	}
+
+	translation := &models.CategoryTranslation{
+		CategoryID:  categoryID,
+		Locale:      locale,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	if translation.Name == "" && translation.Description == "" {
+		return nil, errors.New("name or description is required")
+	}
+
+	if err := s.translationRepo.SaveCategoryTranslation(translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (s *translationService) DeleteCategoryTranslation(categoryID uint, locale string) error {
+	locale, err := s.translatableLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.translationRepo.DeleteCategoryTranslation(categoryID, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("translation not found")
+	}
+	return nil
+}
+
+// LocalizeProducts replaces the products' title and description, and their
+// category's, with the locale's content. Each field falls back along the
+// locale chain to the default content when it has no translation.
+func (s *translationService) LocalizeProducts(locale string, products ...*models.Product) error {
+	locales := s.chain(locale)
+	if len(locales) == 0 || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(products))
+	var categories []*models.Category
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		ids = append(ids, product.ID)
+		if product.Category != nil {
+			categories = append(categories, product.Category)
+		}
+	}
+
+	translations, err := s.translationRepo.FindProductTranslations(ids, locales)
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[uint]map[string]models.ProductTranslation)
+	for _, t := range translations {
+		if byProduct[t.ProductID] == nil {
+			byProduct[t.ProductID] = make(map[string]models.ProductTranslation)
+		}
+		byProduct[t.ProductID][t.Locale] = t
+	}
+
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		found := byProduct[product.ID]
+		if found == nil {
+			continue
+		}
+		// Walk from the least specific locale so the most specific one wins
+		for i := len(locales) - 1; i >= 0; i-- {
+			t, ok := found[locales[i]]
+			if !ok {
+				continue
+			}
+			if t.Title != "" {
+				product.Title = t.Title
+			}
+			if t.Description != "" {
+				product.Description = t.Description
+			}
+		}
+	}
+
+	if err := s.localizeBreadcrumbs(locales, products); err != nil {
+		return err
+	}
+	return s.LocalizeCategories(locale, categories...)
+}
+
+// LocalizeCategories replaces the categories' name and description, including
+// those of their loaded parents and children, with the locale's content
+func (s *translationService) LocalizeCategories(locale string, categories ...*models.Category) error {
+	locales := s.chain(locale)
+	if len(locales) == 0 || len(categories) == 0 {
+		return nil
+	}
+
+	var all []*models.Category
+	var collect func(category *models.Category)
+	collect = func(category *models.Category) {
+		all = append(all, category)
+		if category.Parent != nil {
+			collect(category.Parent)
+		}
+		for i := range category.Children {
+			collect(&category.Children[i])
+		}
+	}
+	for _, category := range categories {
+		collect(category)
+	}
+
+	ids := make([]uint, 0, len(all))
+	for _, category := range all {
+		ids = append(ids, category.ID)
+	}
+
+	content, err := s.categoryContent(ids, locales)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range all {
+		t, ok := content[category.ID]
+		if !ok {
+			continue
+		}
+		if t.Name != "" {
+			category.Name = t.Name
+		}
+		if t.Description != "" {
+			category.Description = t.Description
+		}
+	}
+
+	return nil
+}
+
+// localizeBreadcrumbs replaces the category names in the products' breadcrumbs
+func (s *translationService) localizeBreadcrumbs(locales []string, products []*models.Product) error {
+	var ids []uint
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		for _, crumb := range product.Breadcrumbs {
+			ids = append(ids, crumb.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	content, err := s.categoryContent(ids, locales)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+		for i, crumb := range product.Breadcrumbs {
+			if t, ok := content[crumb.ID]; ok && t.Name != "" {
+				product.Breadcrumbs[i].Name = t.Name
+			}
+		}
+	}
+	return nil
+}
+
+// categoryContent loads the categories' translations along the locale chain
+// and merges them per field, the most specific locale winning
+func (s *translationService) categoryContent(ids []uint, locales []string) (map[uint]models.CategoryTranslation, error) {
+	translations, err := s.translationRepo.FindCategoryTranslations(ids, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[string]int, len(locales))
+	for i, locale := range locales {
+		rank[locale] = i
+	}
+	// Apply the least specific locale first so more specific ones override it
+	sort.SliceStable(translations, func(i, j int) bool {
+		return rank[translations[i].Locale] > rank[translations[j].Locale]
+	})
+
+	content := make(map[uint]models.CategoryTranslation)
+	for _, t := range translations {
+		merged := content[t.CategoryID]
+		if t.Name != "" {
+			merged.Name = t.Name
+		}
+		if t.Description != "" {
+			merged.Description = t.Description
+		}
+		content[t.CategoryID] = merged
+	}
+	return content, nil
+}
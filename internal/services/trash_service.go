@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"gin-quickstart/internal/models"
+	"gin-quickstart/internal/repositories"
+	"gin-quickstart/pkg/utils"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// trashTypes lists the trashable entity types, in the order expired rows are
+// purged: dependents go first so their parents are no longer referenced.
+var trashTypes = []string{"reviews", "products", "categories", "users"}
+
+// trashSlugTypes maps trash types to their slug redirect entity type
+var trashSlugTypes = map[string]string{
+	"products":   "product",
+	"categories": "category",
+}
+
+// trashPurgeBatch is how many expired rows are loaded at a time
+const trashPurgeBatch = 200
+
+type TrashService interface {
+	GetTrash(entityType string, page, limit int) ([]models.TrashItem, int64, error)
+	Restore(entityType string, id uint, req models.TrashRestoreRequest) (*models.TrashItem, error)
+	Purge(entityType string, id uint) error
+	PurgeExpired() (int, error)
+}
+
+type trashService struct {
+	trashRepo  repositories.TrashRepository
+	reviewRepo repositories.ReviewRepository
+	userRepo   repositories.UserRepository
+	slugRepo   repositories.SlugRedirectRepository
+	retention  time.Duration
+}
+
+func NewTrashService(
+	trashRepo repositories.TrashRepository,
+	reviewRepo repositories.ReviewRepository,
+	userRepo repositories.UserRepository,
+	slugRepo repositories.SlugRedirectRepository,
+	retention, purgeInterval time.Duration,
+) TrashService {
+	s := &trashService{
+		trashRepo:  trashRepo,
+		reviewRepo: reviewRepo,
+		userRepo:   userRepo,
+		slugRepo:   slugRepo,
+		retention:  retention,
+	}
+
+	// A zero retention keeps deleted rows until they are purged by hand
+	if retention > 0 {
+		go s.run(purgeInterval)
+	}
+
+	return s
+}
+
+func (s *trashService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := s.PurgeExpired()
+		if err != nil {
+			log.Println("Failed to purge expired trash:", err)
+		}
+		if count > 0 {
+			log.Printf("Purged %d expired trash item(s)", count)
+		}
+	}
+}
+
+func validTrashType(entityType string) error {
+	for _, t := range trashTypes {
+		if t == entityType {
+			return nil
+		}
+	}
+	return errors.New("unknown trash type, expected one of: " + strings.Join(trashTypes, ", "))
+}
+
+func (s *trashService) GetTrash(entityType string, page, limit int) ([]models.TrashItem, int64, error) {
+	if err := validTrashType(entityType); err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	items, total, err := s.trashRepo.List(entityType, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		s.setPurgeAt(&items[i])
+	}
+	return items, total, nil
+}
+
+func (s *trashService) setPurgeAt(item *models.TrashItem) {
+	if s.retention > 0 {
+		item.PurgeAt = item.DeletedAt.Add(s.retention)
+	}
+}
+
+func (s *trashService) getDeleted(entityType string, id uint) (*models.TrashItem, error) {
+	if err := validTrashType(entityType); err != nil {
+		return nil, err
+	}
+
+	item, err := s.trashRepo.GetDeleted(entityType, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("item not found in trash")
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// Restore takes a row out of the trash. A product or category whose slug was
+// taken in the meantime gets a suffixed one; a user whose email was taken
+// needs a new address in the request.
+func (s *trashService) Restore(entityType string, id uint, req models.TrashRestoreRequest) (*models.TrashItem, error) {
+	item, err := s.getDeleted(entityType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	parentDeleted, err := s.trashRepo.HasDeletedParent(entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	if parentDeleted {
+		switch entityType {
+		case "products":
+			return nil, errors.New("the product's category is in the trash, restore it first")
+		case "categories":
+			return nil, errors.New("the parent category is in the trash, restore it first")
+		default:
+			return nil, errors.New("the reviewed product is in the trash, restore it first")
+		}
+	}
+
+	switch entityType {
+	case "reviews":
+		err = s.reviewRepo.Restore(id)
+	case "products", "categories":
+		err = s.restoreWithSlug(entityType, item)
+	case "users":
+		err = s.restoreUser(item, req.Email)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *trashService) restoreWithSlug(entityType string, item *models.TrashItem) error {
+	slugType := trashSlugTypes[entityType]
+	oldSlug := item.Identifier
+	slug, err := uniqueSlug(s.slugRepo, slugType, oldSlug, item.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.trashRepo.RestoreWithSlug(entityType, slugType, item.ID, oldSlug, slug); err != nil {
+		return err
+	}
+
+	item.Identifier = slug
+	return nil
+}
+
+func (s *trashService) restoreUser(item *models.TrashItem, email string) error {
+	if email == "" {
+		email = item.Identifier
+	}
+
+	existing, err := s.userRepo.FindByEmail(email)
+	if err == nil && existing.ID != item.ID {
+		if email == item.Identifier {
+			return errors.New("email is now used by another account, provide a new email to restore this user")
+		}
+		return errors.New("email already registered")
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if email != item.Identifier {
+		updates["email"] = email
+	}
+	if err := s.trashRepo.Restore("users", item.ID, updates); err != nil {
+		return err
+	}
+
+	item.Identifier = email
+	return nil
+}
+
+// Purge permanently deletes a row from the trash. Rows still referenced by
+// orders or other records that must be kept cannot be purged.
+func (s *trashService) Purge(entityType string, id uint) error {
+	if _, err := s.getDeleted(entityType, id); err != nil {
+		return err
+	}
+
+	referenced, err := s.trashRepo.IsReferenced(entityType, id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return errors.New("item is still referenced by orders or other records and cannot be purged")
+	}
+
+	return s.purge(entityType, id)
+}
+
+func (s *trashService) purge(entityType string, id uint) error {
+	var product *models.Product
+	if entityType == "products" {
+		var err error
+		if product, err = s.trashRepo.GetDeletedProduct(id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.trashRepo.Purge(entityType, id); err != nil {
+		return err
+	}
+
+	// Uploaded files are only removed once the row is gone for good
+	if product != nil {
+		deleteBundleImages(product.PreviewImages)
+		utils.DeleteFile(product.FileURL)
+	}
+	return nil
+}
+
+// PurgeExpired permanently deletes rows that have been in the trash longer
+// than the retention period. Referenced rows are skipped and stay in the trash.
+func (s *trashService) PurgeExpired() (int, error) {
+	before := time.Now().Add(-s.retention)
+	purged := 0
+
+	for _, entityType := range trashTypes {
+		// Purged rows leave the result set, so only skipped ones move the offset
+		skipped := 0
+		for {
+			ids, err := s.trashRepo.GetExpired(entityType, before, skipped, trashPurgeBatch)
+			if err != nil {
+				return purged, err
+			}
+
+			for _, id := range ids {
+				referenced, err := s.trashRepo.IsReferenced(entityType, id)
+				if err != nil {
+					return purged, err
+				}
+				if referenced {
+					skipped++
+					continue
+				}
+				if err := s.purge(entityType, id); err != nil {
+					return purged, err
+				}
+				purged++
+			}
+
+			if len(ids) < trashPurgeBatch {
+				break
+			}
+		}
+	}
+
+	return purged, nil
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"gin-quickstart/internal/models"
 	"gin-quickstart/internal/repositories"
+	"regexp"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -15,9 +17,11 @@ type UserService interface {
 	GetAllUsers() ([]models.UserResponse, error)
 	GetUserByID(id uint) (*models.UserResponse, error)
 	UpdateProfile(userID uint, req *models.UserUpdateRequest) (*models.UserResponse, error)
+	UpdateBilling(userID uint, req *models.BillingDetailsRequest) (*models.UserResponse, error)
+	SetTaxIDVerified(userID uint, verified bool) (*models.UserResponse, error)
 	UpdateUser(id uint, req *models.UserUpdateRequest) (*models.UserResponse, error)
 	ChangePassword(userID uint, req *models.ChangePasswordRequest) error
-	DeleteUser(id uint) error
+	DeleteUser(id, deletedBy uint) error
 }
 
 type userService struct {
@@ -52,10 +56,16 @@ func (s *userService) CreateUser(req *models.UserCreateRequest) (*models.UserRes
 	}
 
 	return &models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Currency:      user.Currency,
+		Country:       user.Country,
+		Region:        user.Region,
+		BusinessName:  user.BusinessName,
+		TaxID:         user.TaxID,
+		TaxIDVerified: user.TaxIDVerified,
+		CreatedAt:     user.CreatedAt,
 	}, nil
 }
 
@@ -88,10 +98,16 @@ func (s *userService) GetUserByID(id uint) (*models.UserResponse, error) {
 	}
 
 	return &models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Currency:      user.Currency,
+		Country:       user.Country,
+		Region:        user.Region,
+		BusinessName:  user.BusinessName,
+		TaxID:         user.TaxID,
+		TaxIDVerified: user.TaxIDVerified,
+		CreatedAt:     user.CreatedAt,
 	}, nil
 }
 
@@ -112,6 +128,14 @@ func (s *userService) UpdateUser(id uint, req *models.UserUpdateRequest) (*model
 		}
 	}
 
+	if req.Currency != "" {
+		currency, err := normalizeCurrency(req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		user.Currency = currency
+	}
+
 	user.Name = req.Name
 	user.Email = req.Email
 
@@ -120,14 +144,20 @@ func (s *userService) UpdateUser(id uint, req *models.UserUpdateRequest) (*model
 	}
 
 	return &models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Currency:      user.Currency,
+		Country:       user.Country,
+		Region:        user.Region,
+		BusinessName:  user.BusinessName,
+		TaxID:         user.TaxID,
+		TaxIDVerified: user.TaxIDVerified,
+		CreatedAt:     user.CreatedAt,
 	}, nil
 }
 
-func (s *userService) DeleteUser(id uint) error {
+func (s *userService) DeleteUser(id, deletedBy uint) error {
 	user, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -136,7 +166,7 @@ func (s *userService) DeleteUser(id uint) error {
 		return err
 	}
 
-	return s.repo.Delete(user.ID)
+	return s.repo.Delete(user.ID, deletedBy)
 }
 
 func (s *userService) GetProfile(userID uint) (*models.UserResponse, error) {
@@ -147,6 +177,75 @@ func (s *userService) UpdateProfile(userID uint, req *models.UserUpdateRequest)
 	return s.UpdateUser(userID, req)
 }
 
+// taxIDPattern accepts tax IDs after separators are stripped, e.g. an NPWP
+// or an EU VAT number with its country prefix
+var taxIDPattern = regexp.MustCompile(`^[A-Z0-9]{5,30}$`)
+
+// UpdateBilling sets where the user is taxed and their business details.
+// Empty fields clear them.
+func (s *userService) UpdateBilling(userID uint, req *models.BillingDetailsRequest) (*models.UserResponse, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	taxID := strings.NewReplacer(" ", "", ".", "", "-", "", "/", "").Replace(strings.ToUpper(req.TaxID))
+	if taxID != "" && !taxIDPattern.MatchString(taxID) {
+		return nil, errors.New("invalid tax ID")
+	}
+	businessName := strings.TrimSpace(req.BusinessName)
+	if taxID != "" && businessName == "" {
+		return nil, errors.New("business_name is required with a tax ID")
+	}
+	region := strings.TrimSpace(req.Region)
+	if region != "" && req.Country == "" {
+		return nil, errors.New("country is required with a region")
+	}
+
+	country := strings.ToUpper(req.Country)
+	// A verification only holds for the ID and country it was checked against
+	if country != user.Country || taxID != user.TaxID {
+		user.TaxIDVerified = false
+	}
+
+	user.Country = country
+	user.Region = region
+	user.BusinessName = businessName
+	user.TaxID = taxID
+
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByID(user.ID)
+}
+
+// SetTaxIDVerified records an admin's check of the user's tax ID against
+// their billing country. Only verified IDs qualify for reverse charge.
+func (s *userService) SetTaxIDVerified(userID uint, verified bool) (*models.UserResponse, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	if verified && (user.TaxID == "" || user.Country == "") {
+		return nil, errors.New("user has no tax ID and billing country to verify")
+	}
+
+	user.TaxIDVerified = verified
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserByID(user.ID)
+}
+
 func (s *userService) ChangePassword(userID uint, req *models.ChangePasswordRequest) error {
 	user, err := s.repo.FindByID(userID)
 	if err != nil {
@@ -17,12 +17,14 @@ type WishlistService interface {
 type wishlistService struct {
 	wishlistRepo repositories.WishlistRepository
 	productRepo  repositories.ProductRepository
+	campaigns    SaleCampaignService
 }
 
-func NewWishlistService(wishlistRepo repositories.WishlistRepository, productRepo repositories.ProductRepository) WishlistService {
+func NewWishlistService(wishlistRepo repositories.WishlistRepository, productRepo repositories.ProductRepository, campaigns SaleCampaignService) WishlistService {
 	return &wishlistService{
 		wishlistRepo: wishlistRepo,
 		productRepo:  productRepo,
+		campaigns:    campaigns,
 	}
 }
 
@@ -32,7 +34,7 @@ func (s *wishlistService) AddToWishlist(userID, productID uint) (*models.Wishlis
 		return nil, errors.New("product not found")
 	}
 
-	if !product.IsActive {
+	if product.Status != "published" {
 		return nil, errors.New("product is not available")
 	}
 
@@ -54,7 +56,19 @@ func (s *wishlistService) AddToWishlist(userID, productID uint) (*models.Wishlis
 }
 
 func (s *wishlistService) GetUserWishlist(userID uint) ([]models.Wishlist, error) {
-	return s.wishlistRepo.GetByUserID(userID)
+	wishlist, err := s.wishlistRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*models.Product, len(wishlist))
+	for i := range wishlist {
+		products[i] = wishlist[i].Product
+	}
+	if err := s.campaigns.ApplySalePrices(products...); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
 }
 
 func (s *wishlistService) RemoveFromWishlist(userID, wishlistID uint) error {
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// SignCartToken returns the token a visitor presents for their guest cart:
+// the cart id followed by an HMAC of it, so cart ids cannot be guessed or forged.
+func SignCartToken(cartID, secret string) string {
+	return cartID + "." + base64.RawURLEncoding.EncodeToString(cartTokenMAC(cartID, secret))
+}
+
+// VerifyCartToken checks the token's signature and returns the guest cart id
+func VerifyCartToken(token, secret string) (string, error) {
+	cartID, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || cartID == "" {
+		return "", errors.New("malformed cart token")
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, cartTokenMAC(cartID, secret)) {
+		return "", errors.New("invalid cart token signature")
+	}
+	return cartID, nil
+}
+
+func cartTokenMAC(cartID, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte("cart-token:"+secret))
+	mac.Write([]byte(cartID))
+	return mac.Sum(nil)
+}
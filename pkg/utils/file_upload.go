@@ -14,8 +14,11 @@ import (
 )
 
 const (
-	MaxUploadSize = 5 << 20 // 5MB
-	UploadPath    = "./uploads"
+	MaxUploadSize      = 5 << 20   // 5MB
+	MaxDeliverableSize = 200 << 20 // 200MB
+	UploadPath         = "./uploads"
+	// DeliverablePath is kept outside UploadPath so product files are never served statically
+	DeliverablePath = "./storage/deliverables"
 )
 
 var AllowedImageTypes = map[string]bool{
@@ -63,6 +66,40 @@ func UploadFile(file *multipart.FileHeader, folder string) (string, error) {
 	return strings.Replace(filePath, "\\", "/", -1), nil
 }
 
+// UploadDeliverable stores a product's downloadable file in private storage
+func UploadDeliverable(file *multipart.FileHeader) (string, error) {
+	if file.Size > MaxDeliverableSize {
+		return "", errors.New("file size exceeds maximum limit of 200MB")
+	}
+
+	if err := os.MkdirAll(DeliverablePath, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	filename := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
+	filePath := filepath.Join(DeliverablePath, filename)
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+
+	return strings.Replace(filePath, "\\", "/", -1), nil
+}
+
 func DeleteFile(filePath string) error {
 	if filePath == "" {
 		return nil
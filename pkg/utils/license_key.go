@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// LicenseKeyPrefix marks the key format version
+const LicenseKeyPrefix = "CDG1"
+
+// LicenseSigningKey decodes a base64 Ed25519 seed. The seed is required:
+// rotating it invalidates every issued key, so it must be configured explicitly.
+func LicenseSigningKey(encodedSeed string) (ed25519.PrivateKey, error) {
+	if encodedSeed == "" {
+		return nil, errors.New("license signing key is not set")
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(encodedSeed)
+	if err != nil {
+		return nil, errors.New("license signing key is not valid base64")
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.New("license signing key must be a 32 byte Ed25519 seed")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// SignLicenseKey encodes the payload as PREFIX.payload.signature, where the
+// signature covers the encoded payload so it can be verified offline.
+func SignLicenseKey(payload []byte, key ed25519.PrivateKey) string {
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key, []byte(encoded))
+	return LicenseKeyPrefix + "." + encoded + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// VerifyLicenseKey checks the key's signature and returns its payload
+func VerifyLicenseKey(key string, publicKey ed25519.PublicKey) ([]byte, error) {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	if len(parts) != 3 || parts[0] != LicenseKeyPrefix {
+		return nil, errors.New("malformed license key")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(publicKey, []byte(parts[1]), signature) {
+		return nil, errors.New("invalid license key signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed license key")
+	}
+	return payload, nil
+}
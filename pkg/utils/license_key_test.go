@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+)
+
+func TestLicenseSigningKey(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr bool
+	}{
+		{name: "valid seed", encoded: base64.StdEncoding.EncodeToString(seed)},
+		{name: "empty", encoded: "", wantErr: true},
+		{name: "not base64", encoded: "not base64!", wantErr: true},
+		{name: "too short", encoded: base64.StdEncoding.EncodeToString(seed[:16]), wantErr: true},
+		{name: "too long", encoded: base64.StdEncoding.EncodeToString(append(seed, 1)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LicenseSigningKey(tt.encoded)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LicenseSigningKey() returned no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LicenseSigningKey() error = %v", err)
+			}
+			if !bytes.Equal(key.Seed(), seed) {
+				t.Errorf("LicenseSigningKey() seed = %x, want %x", key.Seed(), seed)
+			}
+		})
+	}
+}
+
+func TestLicenseSigningKeyRoundTrip(t *testing.T) {
+	key, err := LicenseSigningKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, ed25519.SeedSize)))
+	if err != nil {
+		t.Fatalf("LicenseSigningKey() error = %v", err)
+	}
+	payload := []byte(`{"product_id":1}`)
+
+	signed := SignLicenseKey(payload, key)
+	got, err := VerifyLicenseKey(signed, key.Public().(ed25519.PublicKey))
+	if err != nil {
+		t.Fatalf("VerifyLicenseKey() error = %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Errorf("VerifyLicenseKey() = %s, want %s", got, payload)
+	}
+
+	other, _ := LicenseSigningKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, ed25519.SeedSize)))
+	if _, err := VerifyLicenseKey(signed, other.Public().(ed25519.PublicKey)); err == nil {
+		t.Error("VerifyLicenseKey() accepted a key signed with another seed")
+	}
+}